@@ -0,0 +1,326 @@
+package config
+
+import (
+	"container/list"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	"github.com/go-masonry/mortar/utils"
+)
+
+const (
+	// SetFlag overrides a configuration key from the command line, can be repeated
+	//
+	//	--set mortar.logger.level=debug --set mortar.server.grpc.port=5380
+	SetFlag = "set"
+	// PrintConfigFlag prints the effective (obfuscated) configuration and exits
+	PrintConfigFlag = "print-config"
+	// SourceFlag is reported as a source of values that were set from the command line
+	SourceFlag = "flag"
+
+	obfuscationEdgeLength = 4
+)
+
+// FlagKey defines a configuration key that will have its own typed command line flag, named after the key itself.
+//
+//	--mortar.server.grpc.port=5380
+//
+// Flag type is determined by the type of Default, supported types are:
+//
+//	bool, int, float64, string, time.Duration, []string (comma separated)
+type FlagKey struct {
+	Key     string
+	Default interface{}
+	Usage   string
+}
+
+// DefaultFlagKeys are mortar configuration keys that always have a typed flag
+var DefaultFlagKeys = []FlagKey{
+	{Key: confkeys.ApplicationName, Default: "", Usage: "Application/Project name"},
+	{Key: confkeys.Profile, Default: "", Usage: "Environment profile(s) to overlay, requires WithProfiles"},
+	{Key: confkeys.Host, Default: "", Usage: "Host on which the webserver will serve APIs"},
+	{Key: confkeys.ExternalGRPCPort, Default: 0, Usage: "gRPC API External port"},
+	{Key: confkeys.ExternalRESTPort, Default: 0, Usage: "RESTful API External port"},
+	{Key: confkeys.InternalRESTPort, Default: 0, Usage: "RESTful API Internal port"},
+	{Key: confkeys.InternalGRPCPort, Default: 0, Usage: "gRPC API Internal port"},
+	{Key: confkeys.LogLevel, Default: "", Usage: "Default log level: trace, debug, info, warn, error"},
+	{Key: confkeys.MiddlewareLogLevel, Default: "", Usage: "Log level of all the bundled middleware"},
+}
+
+// FlagsBuilder wraps a cfg.Builder and adds command line flags as a configuration source
+type FlagsBuilder interface {
+	cfg.Builder
+	// SetArguments sets command line arguments to parse, by default os.Args[1:].
+	//
+	// Arguments that are not known to this builder are ignored, so it can live side by side with other flags.
+	SetArguments(args []string) FlagsBuilder
+	// RegisterKeys adds typed flags for the provided configuration keys, on top of DefaultFlagKeys
+	RegisterKeys(keys ...FlagKey) FlagsBuilder
+	// SetOutput sets where --print-config and flag errors are written to, by default os.Stdout
+	SetOutput(writer io.Writer) FlagsBuilder
+}
+
+type flagsConfig struct {
+	args   []string
+	keys   []FlagKey
+	output io.Writer
+}
+
+type flagsBuilder struct {
+	inner cfg.Builder
+	ll    *list.List
+	exit  func(code int)
+}
+
+// WithFlags wraps any cfg.Builder implementation and adds a command line flags source.
+//
+// Flags take precedence over environment variables and files.
+//
+//	config, err := config.WithFlags(bviper.Builder()).SetConfigFile("config/config.yaml").Build()
+//
+// When combined with WithProfiles, WithFlags must be the outer one, a profile selected with a flag is passed to
+// WithProfiles before the configuration is built. The opposite order is rejected by WithProfiles.
+//
+//	config, err := config.WithFlags(config.WithProfiles(bviper.Builder())).SetConfigFile("config/config.yaml").Build()
+//
+// Supported flags
+//   - --set key=value overrides any configuration key, can be repeated
+//   - --<key>=<value> typed flags of DefaultFlagKeys and keys provided with RegisterKeys
+//   - --print-config prints the effective configuration with obfuscated values and exits
+func WithFlags(builder cfg.Builder) FlagsBuilder {
+	return &flagsBuilder{
+		inner: builder,
+		ll:    list.New(),
+		exit:  os.Exit,
+	}
+}
+
+func (f *flagsBuilder) SetArguments(args []string) FlagsBuilder {
+	f.ll.PushBack(func(cfg *flagsConfig) {
+		cfg.args = args
+	})
+	return f
+}
+
+func (f *flagsBuilder) RegisterKeys(keys ...FlagKey) FlagsBuilder {
+	f.ll.PushBack(func(cfg *flagsConfig) {
+		cfg.keys = append(cfg.keys, keys...)
+	})
+	return f
+}
+
+func (f *flagsBuilder) SetOutput(writer io.Writer) FlagsBuilder {
+	f.ll.PushBack(func(cfg *flagsConfig) {
+		cfg.output = writer
+	})
+	return f
+}
+
+func (f *flagsBuilder) SetConfigFile(path string) cfg.Builder {
+	f.inner.SetConfigFile(path)
+	return f
+}
+
+func (f *flagsBuilder) AddExtraConfigFile(path string) cfg.Builder {
+	f.inner.AddExtraConfigFile(path)
+	return f
+}
+
+func (f *flagsBuilder) SetEnvDelimiterReplacer(from, to string) cfg.Builder {
+	f.inner.SetEnvDelimiterReplacer(from, to)
+	return f
+}
+
+func (f *flagsBuilder) Build() (cfg.Config, error) {
+	conf := &flagsConfig{
+		args:   os.Args[1:],
+		keys:   DefaultFlagKeys,
+		output: os.Stdout,
+	}
+	for e := f.ll.Front(); e != nil; e = e.Next() {
+		fn := e.Value.(func(cfg *flagsConfig))
+		fn(conf)
+	}
+	values, printConfig, err := parseFlags(conf)
+	if err != nil {
+		return nil, err
+	}
+	if selector, ok := f.inner.(profileSelector); ok {
+		for key, value := range values {
+			if strings.EqualFold(key, confkeys.Profile) {
+				selector.selectProfile(fmt.Sprint(value))
+			}
+		}
+	}
+	config, err := f.inner.Build()
+	if err != nil {
+		return nil, err
+	}
+	flagKeys := make(map[string]bool, len(values))
+	for key, value := range values {
+		config.Set(key, value)
+		flagKeys[strings.ToLower(key)] = true
+	}
+	config = &flagsSourceConfig{Config: config, flagKeys: flagKeys}
+	if printConfig {
+		if err = PrintConfig(conf.output, config); err != nil {
+			return nil, err
+		}
+		f.exit(0)
+	}
+	return config, nil
+}
+
+// PrintConfig writes the configuration map as indented JSON, values of keys that contain one of the
+// `mortar.handlers.config.obfuscate` keywords are obfuscated.
+func PrintConfig(writer io.Writer, config cfg.Config) error {
+	hideKeys := config.Get(confkeys.ConfigHandlerObfuscateKeys).StringSlice()
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(utils.ObfuscateMapWhereNeeded("", config.Map(), hideKeys, obfuscationEdgeLength))
+}
+
+func parseFlags(conf *flagsConfig) (values map[string]interface{}, printConfig bool, err error) {
+	flagSet := flag.NewFlagSet("mortar", flag.ContinueOnError)
+	flagSet.SetOutput(conf.output)
+	var overrides keyValueFlag
+	flagSet.Var(&overrides, SetFlag, "override a configuration key using key=value, can be repeated")
+	flagSet.BoolVar(&printConfig, PrintConfigFlag, false, "print the effective configuration and exit")
+	for _, key := range conf.keys {
+		if flagSet.Lookup(key.Key) != nil {
+			continue // registered twice, first one wins
+		}
+		if err = defineTypedFlag(flagSet, key); err != nil {
+			return nil, false, err
+		}
+	}
+	if err = flagSet.Parse(knownArguments(flagSet, conf.args)); err != nil {
+		return nil, false, err
+	}
+	values = make(map[string]interface{})
+	for _, pair := range overrides {
+		values[pair[0]] = pair[1]
+	}
+	flagSet.Visit(func(f *flag.Flag) { // only flags that were set
+		if f.Name != SetFlag && f.Name != PrintConfigFlag {
+			values[f.Name] = f.Value.(flag.Getter).Get()
+		}
+	})
+	return
+}
+
+func defineTypedFlag(flagSet *flag.FlagSet, key FlagKey) error {
+	switch value := key.Default.(type) {
+	case bool:
+		flagSet.Bool(key.Key, value, key.Usage)
+	case int:
+		flagSet.Int(key.Key, value, key.Usage)
+	case float64:
+		flagSet.Float64(key.Key, value, key.Usage)
+	case string:
+		flagSet.String(key.Key, value, key.Usage)
+	case time.Duration:
+		flagSet.Duration(key.Key, value, key.Usage)
+	case []string:
+		slice := stringSliceFlag(value)
+		flagSet.Var(&slice, key.Key, key.Usage)
+	default:
+		return fmt.Errorf("flag [%s] has unsupported type %T", key.Key, key.Default)
+	}
+	return nil
+}
+
+// knownArguments filters out every argument that is not a flag defined in flagSet (or its value)
+func knownArguments(flagSet *flag.FlagSet, args []string) (known []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			continue
+		}
+		name := strings.TrimLeft(arg, "-")
+		hasValue := strings.Contains(name, "=")
+		if hasValue {
+			name = name[:strings.Index(name, "=")]
+		}
+		defined := flagSet.Lookup(name)
+		if defined == nil {
+			continue
+		}
+		known = append(known, arg)
+		if boolFlag, ok := defined.Value.(interface{ IsBoolFlag() bool }); !hasValue && !(ok && boolFlag.IsBoolFlag()) && i+1 < len(args) {
+			i++
+			known = append(known, args[i])
+		}
+	}
+	return
+}
+
+type keyValueFlag [][2]string
+
+func (k *keyValueFlag) String() string {
+	var pairs []string
+	for _, pair := range *k {
+		pairs = append(pairs, pair[0]+"="+pair[1])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (k *keyValueFlag) Set(value string) error {
+	index := strings.Index(value, "=")
+	if index <= 0 {
+		return fmt.Errorf("expected key=value, got %s", value)
+	}
+	*k = append(*k, [2]string{value[:index], value[index+1:]})
+	return nil
+}
+
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSliceFlag) Set(value string) error {
+	*s = strings.Split(value, ",")
+	return nil
+}
+
+func (s *stringSliceFlag) Get() interface{} {
+	return []string(*s)
+}
+
+// flagsSourceConfig reports command line flags as the source of their values
+type flagsSourceConfig struct {
+	cfg.Config
+	flagKeys map[string]bool
+}
+
+func (f *flagsSourceConfig) Sub(prefix string) cfg.Config {
+	return Sub(f, prefix)
+}
+
+func (f *flagsSourceConfig) Sources() map[string]string {
+	output := make(map[string]string)
+	if valueSources, ok := f.Config.(cfg.ValueSources); ok {
+		for key, source := range valueSources.Sources() {
+			output[key] = source
+		}
+	}
+	for key := range f.flagKeys {
+		output[key] = SourceFlag
+	}
+	return output
+}
+
+var _ cfg.Builder = (*flagsBuilder)(nil)
+var _ cfg.ValueSources = (*flagsSourceConfig)(nil)
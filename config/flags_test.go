@@ -0,0 +1,87 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	mock_cfg "github.com/go-masonry/mortar/interfaces/cfg/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagsOverrideValues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	config := mock_cfg.NewMockConfig(ctrl)
+	config.EXPECT().Set("mortar.logger.level", "debug")
+	config.EXPECT().Set(confkeys.ExternalGRPCPort, 5380)
+	config.EXPECT().Set(confkeys.InternalGRPCPort, 5381)
+	config.EXPECT().Set("custom.timeout", 3*time.Second)
+	config.EXPECT().Set("custom.list", []string{"a", "b"})
+	inner := mock_cfg.NewMockBuilder(ctrl)
+	inner.EXPECT().SetConfigFile("config.yaml").Return(inner)
+	inner.EXPECT().Build().Return(config, nil)
+
+	built, err := WithFlags(inner).
+		SetArguments([]string{
+			"-v", "--unknown", "value", "positional",
+			"--set", "mortar.logger.level=debug",
+			"--mortar.server.grpc.port=5380",
+			"--mortar.server.grpc.internal.port", "5381",
+			"--custom.timeout", "3s",
+			"--custom.list=a,b",
+			"--", "--set", "after.terminator=ignored",
+		}).
+		RegisterKeys(
+			FlagKey{Key: "custom.timeout", Default: time.Second},
+			FlagKey{Key: "custom.list", Default: []string{}},
+		).
+		SetConfigFile("config.yaml").
+		Build()
+	require.NoError(t, err)
+	sources := built.(cfg.ValueSources).Sources()
+	assert.Equal(t, map[string]string{
+		"mortar.logger.level":              SourceFlag,
+		"mortar.server.grpc.port":          SourceFlag,
+		"mortar.server.grpc.internal.port": SourceFlag,
+		"custom.timeout":                   SourceFlag,
+		"custom.list":                      SourceFlag,
+	}, sources)
+}
+
+func TestFlagsBadValue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	var output bytes.Buffer
+	_, err := WithFlags(mock_cfg.NewMockBuilder(ctrl)).
+		SetOutput(&output).
+		SetArguments([]string{"--mortar.server.grpc.port=abc"}).
+		Build()
+	assert.Error(t, err)
+}
+
+func TestPrintConfig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	config := mock_cfg.NewMockConfig(ctrl)
+	config.EXPECT().Get(confkeys.ConfigHandlerObfuscateKeys).DoAndReturn(func(string) cfg.Value {
+		value := mock_cfg.NewMockValue(ctrl)
+		value.EXPECT().StringSlice().Return([]string{"pass"})
+		return value
+	})
+	config.EXPECT().Map().Return(map[string]interface{}{
+		"db": map[string]interface{}{"password": "verysecretpassword"},
+	})
+	inner := mock_cfg.NewMockBuilder(ctrl)
+	inner.EXPECT().Build().Return(config, nil)
+
+	var output bytes.Buffer
+	var exitCode = -1
+	builder := WithFlags(inner).SetOutput(&output).SetArguments([]string{"--print-config"})
+	builder.(*flagsBuilder).exit = func(code int) { exitCode = code }
+	_, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, 0, exitCode)
+	assert.JSONEq(t, `{"db":{"password":"very****word"}}`, output.String())
+}
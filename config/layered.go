@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-masonry/mortar/interfaces/cfg"
+)
+
+type layeredConfig struct {
+	sync.RWMutex
+	inner     cfg.Config
+	tracker   *tracker
+	callbacks []func(key string)
+}
+
+func newLayeredConfig(inner cfg.Config, layers []layer) cfg.Config {
+	return &layeredConfig{
+		inner:   inner,
+		tracker: newTracker(layers),
+	}
+}
+
+func (l *layeredConfig) Get(key string) cfg.Value {
+	l.RLock()
+	hidden := l.tracker.hidden(key)
+	l.RUnlock()
+	if hidden {
+		return unsetValue{}
+	}
+	return l.inner.Get(key)
+}
+
+func (l *layeredConfig) Set(key string, value interface{}) {
+	l.inner.Set(key, value)
+	l.Lock()
+	l.tracker.markSet(strings.ToLower(key), value, SourceSet)
+	callbacks := l.callbacks
+	l.Unlock()
+	for _, callback := range callbacks {
+		callback(key)
+	}
+}
+
+func (l *layeredConfig) Map() map[string]interface{} {
+	l.RLock()
+	defer l.RUnlock()
+	return l.prune("", l.inner.Map())
+}
+
+func (l *layeredConfig) Sub(prefix string) cfg.Config {
+	return Sub(l, prefix)
+}
+
+func (l *layeredConfig) Keys(prefix string) []string {
+	return Keys(l.Map(), prefix)
+}
+
+func (l *layeredConfig) OnChange(callback func(key string)) {
+	l.Lock()
+	defer l.Unlock()
+	l.callbacks = append(l.callbacks[:len(l.callbacks):len(l.callbacks)], callback)
+}
+
+func (l *layeredConfig) Implementation() interface{} {
+	return l.inner.Implementation()
+}
+
+// Sources reports which file supplied each of the final values.
+//
+// Values that are not found in the files, or differ from them, are reported by the wrapped configuration
+// if it implements cfg.ValueSources, otherwise they are reported as SourceUnknown and SourceOverride.
+func (l *layeredConfig) Sources() map[string]string {
+	values := make(map[string]interface{})
+	flatten("", l.Map(), values)
+	var innerSources map[string]string
+	if valueSources, ok := l.inner.(cfg.ValueSources); ok {
+		innerSources = valueSources.Sources()
+	}
+	l.RLock()
+	defer l.RUnlock()
+	output := make(map[string]string, len(values))
+	for key, value := range values {
+		source, ok := l.tracker.sources[key]
+		switch {
+		case !ok:
+			output[key] = innerSource(innerSources, key, SourceUnknown)
+		case source != SourceSet && fmt.Sprint(value) != fmt.Sprint(l.tracker.values[key]):
+			output[key] = innerSource(innerSources, key, SourceOverride) // file value was overridden
+		default:
+			output[key] = source
+		}
+	}
+	return output
+}
+
+func innerSource(sources map[string]string, key, fallback string) string {
+	if source, ok := sources[key]; ok && len(source) > 0 {
+		return source
+	}
+	return fallback
+}
+
+func (l *layeredConfig) prune(prefix string, confMap map[string]interface{}) map[string]interface{} {
+	output := make(map[string]interface{}, len(confMap))
+	for k, v := range confMap {
+		key := joinKeys(prefix, k)
+		if mValue, ok := v.(map[string]interface{}); ok {
+			if pruned := l.prune(key, mValue); len(pruned) > 0 || len(mValue) == 0 {
+				output[k] = pruned
+			}
+		} else if !l.tracker.hidden(key) {
+			output[k] = v
+		}
+	}
+	return output
+}
+
+var _ cfg.Config = (*layeredConfig)(nil)
+var _ cfg.ValueSources = (*layeredConfig)(nil)
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	keyDelimiter = "."
+	// SourceSet is reported as a source of values that were set using Config.Set
+	SourceSet = "set"
+	// SourceOverride is reported as a source of values that are different from the ones found in the files,
+	// when the wrapped configuration can't tell where they came from (environment, defaults, ...)
+	SourceOverride = "override"
+	// SourceUnknown is reported as a source of values that can't be found in any of the tracked files
+	SourceUnknown = "unknown"
+)
+
+// layer is a flat representation of a single configuration file, `nil` values mark deleted keys
+type layer struct {
+	file   string
+	values map[string]interface{}
+}
+
+func readLayers(files []string) (layers []layer, err error) {
+	for _, file := range files {
+		var nested map[string]interface{}
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".yaml", ".yml", ".json":
+			if nested, err = readFile(file); err != nil {
+				return nil, err
+			}
+		default:
+			continue // can't track this format
+		}
+		values := make(map[string]interface{})
+		flatten("", nested, values)
+		layers = append(layers, layer{file: file, values: values})
+	}
+	return
+}
+
+func readFile(file string) (output map[string]interface{}, err error) {
+	var content []byte
+	if content, err = os.ReadFile(file); err != nil {
+		return nil, fmt.Errorf("failed to read configuration file, %w", err)
+	}
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		err = json.Unmarshal(content, &output)
+	} else {
+		err = yaml.Unmarshal(content, &output)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file %s, %w", file, err)
+	}
+	return
+}
+
+// flatten converts nested maps into a flat map using '.' notation, keys are lower cased.
+//
+// Empty maps are ignored while nil values are kept since they mark deleted keys.
+func flatten(prefix string, nested map[string]interface{}, output map[string]interface{}) {
+	for k, v := range nested {
+		key := strings.ToLower(joinKeys(prefix, k))
+		switch value := v.(type) {
+		case map[string]interface{}:
+			flatten(key, value, output)
+		case map[interface{}]interface{}:
+			converted := make(map[string]interface{}, len(value))
+			for ik, iv := range value {
+				converted[fmt.Sprintf("%v", ik)] = iv
+			}
+			flatten(key, converted, output)
+		default:
+			output[key] = value
+		}
+	}
+}
+
+func joinKeys(prefix, key string) string {
+	if len(prefix) == 0 {
+		return key
+	}
+	if len(key) == 0 {
+		return prefix
+	}
+	return prefix + keyDelimiter + key
+}
+
+// tracker remembers the order in which keys were deleted and set, a key is hidden if one of its
+// parents (or itself) was deleted after it was set.
+type tracker struct {
+	seq     int
+	deleted map[string]int
+	set     map[string]int
+	values  map[string]interface{}
+	sources map[string]string
+}
+
+func newTracker(layers []layer) *tracker {
+	t := &tracker{
+		deleted: make(map[string]int),
+		set:     make(map[string]int),
+		values:  make(map[string]interface{}),
+		sources: make(map[string]string),
+	}
+	for _, l := range layers {
+		for key, value := range l.values {
+			if value == nil {
+				t.markDeleted(key)
+			} else {
+				t.markSet(key, value, l.file)
+			}
+		}
+	}
+	return t
+}
+
+func (t *tracker) markDeleted(key string) {
+	t.seq++
+	t.deleted[key] = t.seq
+}
+
+func (t *tracker) markSet(key string, value interface{}, source string) {
+	t.seq++
+	t.set[key] = t.seq
+	t.values[key] = value
+	t.sources[key] = source
+}
+
+func (t *tracker) hidden(key string) bool {
+	if len(t.deleted) == 0 {
+		return false
+	}
+	key = strings.ToLower(key)
+	deletedAt := 0
+	for path := key; ; {
+		if seq, ok := t.deleted[path]; ok && seq > deletedAt {
+			deletedAt = seq
+		}
+		index := strings.LastIndex(path, keyDelimiter)
+		if index < 0 {
+			break
+		}
+		path = path[:index]
+	}
+	if deletedAt == 0 {
+		return false
+	}
+	if seq, ok := t.set[key]; ok {
+		return seq < deletedAt
+	}
+	for leaf, seq := range t.set { // sub tree might be partially set again
+		if seq > deletedAt && strings.HasPrefix(leaf, key+keyDelimiter) {
+			return false
+		}
+	}
+	return true
+}
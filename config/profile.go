@@ -0,0 +1,177 @@
+// Package config contains helpers that wrap any cfg.Builder/cfg.Config implementation and extend it
+// with features that are not specific to a configuration library.
+package config
+
+import (
+	"container/list"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+)
+
+// ProfileEnvironmentVariable selects the active profile(s), it takes precedence over the `mortar.profile` key
+const ProfileEnvironmentVariable = "MORTAR_PROFILE"
+
+type profileConfig struct {
+	mainFile   string
+	extraFiles []string
+	profile    *string
+}
+
+// profileSelector is implemented by builders that resolve profiles, WithFlags uses it to pass the profile
+// selected on the command line before the configuration is built
+type profileSelector interface {
+	selectProfile(profile string)
+}
+
+type profileBuilder struct {
+	inner    cfg.Builder
+	ll       *list.List
+	overlays map[string]bool
+}
+
+// WithProfiles wraps any cfg.Builder implementation and adds environment profiles support.
+//
+// Once the configuration is built, active profiles are resolved from the `mortar.profile` command line flag,
+// the MORTAR_PROFILE environment variable or the `mortar.profile` key, in that order. For every profile `<name>` a `config.<name>.yaml` overlay that is found next to the
+// main configuration file is merged on top of it. Overlays can delete keys by setting them explicitly to `null`.
+//
+// The returned Config also implements cfg.ValueSources, reporting which file supplied each final value.
+//
+//	config, err := config.WithProfiles(bviper.Builder()).SetConfigFile("config/config.yaml").Build()
+//
+// To also use command line flags, WithFlags must wrap WithProfiles and not the other way around.
+// That way flags are parsed once, before the profiles are resolved, and override the overlays.
+//
+//	config, err := config.WithFlags(config.WithProfiles(bviper.Builder())).SetConfigFile("config/config.yaml").Build()
+//
+// **Note**
+//
+// Only YAML and JSON files are inspected to track deleted keys and value sources.
+func WithProfiles(builder cfg.Builder) cfg.Builder {
+	return &profileBuilder{
+		inner:    builder,
+		ll:       list.New(),
+		overlays: make(map[string]bool),
+	}
+}
+
+func (p *profileBuilder) SetConfigFile(path string) cfg.Builder {
+	p.inner.SetConfigFile(path)
+	p.ll.PushBack(func(cfg *profileConfig) {
+		cfg.mainFile = path
+	})
+	return p
+}
+
+func (p *profileBuilder) AddExtraConfigFile(path string) cfg.Builder {
+	p.inner.AddExtraConfigFile(path)
+	p.ll.PushBack(func(cfg *profileConfig) {
+		cfg.extraFiles = append(cfg.extraFiles, path)
+	})
+	return p
+}
+
+func (p *profileBuilder) SetEnvDelimiterReplacer(from, to string) cfg.Builder {
+	p.inner.SetEnvDelimiterReplacer(from, to)
+	return p
+}
+
+func (p *profileBuilder) selectProfile(profile string) {
+	p.ll.PushBack(func(cfg *profileConfig) {
+		cfg.profile = &profile
+	})
+}
+
+func (p *profileBuilder) Build() (cfg.Config, error) {
+	if _, ok := p.inner.(*flagsBuilder); ok {
+		return nil, fmt.Errorf("flags can't be parsed before profiles are resolved, use config.WithFlags(config.WithProfiles(builder)) instead")
+	}
+	conf := new(profileConfig)
+	for e := p.ll.Front(); e != nil; e = e.Next() {
+		f := e.Value.(func(cfg *profileConfig))
+		f(conf)
+	}
+	config, err := p.inner.Build()
+	if err != nil {
+		return nil, err
+	}
+	overlays, err := p.profileOverlays(conf.mainFile, activeProfiles(conf.profile, config))
+	if err != nil {
+		return nil, err
+	}
+	if rebuild := p.addOverlays(overlays); rebuild {
+		if config, err = p.inner.Build(); err != nil {
+			return nil, err
+		}
+	}
+	var files []string
+	if len(conf.mainFile) > 0 {
+		files = append(files, conf.mainFile)
+	}
+	files = append(files, conf.extraFiles...)
+	files = append(files, overlays...)
+	layers, err := readLayers(files)
+	if err != nil {
+		return nil, err
+	}
+	return newLayeredConfig(config, layers), nil
+}
+
+func (p *profileBuilder) profileOverlays(mainFile string, profiles []string) (overlays []string, err error) {
+	if len(profiles) > 0 && len(mainFile) == 0 {
+		return nil, fmt.Errorf("profiles %v selected, but no main configuration file was set", profiles)
+	}
+	for _, profile := range profiles {
+		overlay := ProfileFile(mainFile, profile)
+		if _, err = os.Stat(overlay); err != nil {
+			return nil, fmt.Errorf("profile [%s] configuration file is not accessible, %w", profile, err)
+		}
+		overlays = append(overlays, overlay)
+	}
+	return
+}
+
+// addOverlays adds overlay files to the wrapped builder only once, since Build can be called more than once
+func (p *profileBuilder) addOverlays(overlays []string) (added bool) {
+	for _, overlay := range overlays {
+		if !p.overlays[overlay] {
+			p.overlays[overlay] = true
+			p.inner.AddExtraConfigFile(overlay)
+			added = true
+		}
+	}
+	return
+}
+
+// ProfileFile returns the overlay file path of a profile, it's located next to the main configuration file.
+//
+//	ProfileFile("config/config.yaml", "staging") // config/config.staging.yaml
+func ProfileFile(mainFile, profile string) string {
+	ext := filepath.Ext(mainFile)
+	return fmt.Sprintf("%s.%s%s", strings.TrimSuffix(mainFile, ext), profile, ext)
+}
+
+func activeProfiles(selected *string, config cfg.Config) (profiles []string) {
+	var value string
+	if selected != nil {
+		value = *selected
+	} else if fromEnv, ok := os.LookupEnv(ProfileEnvironmentVariable); ok {
+		value = fromEnv
+	} else {
+		value = config.Get(confkeys.Profile).String()
+	}
+	for _, profile := range strings.Split(value, ",") {
+		if profile = strings.TrimSpace(profile); len(profile) > 0 {
+			profiles = append(profiles, profile)
+		}
+	}
+	return
+}
+
+var _ cfg.Builder = (*profileBuilder)(nil)
+var _ profileSelector = (*profileBuilder)(nil)
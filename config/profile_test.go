@@ -0,0 +1,227 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	mock_cfg "github.com/go-masonry/mortar/interfaces/cfg/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	mainContent = `
+mortar:
+  name: "profiles"
+  profile: "staging"
+  handlers:
+    config:
+      obfuscate:
+        - "pass"
+  logger:
+    level: debug
+`
+	stagingContent = `
+mortar:
+  logger:
+    level: info
+  handlers: null
+`
+)
+
+type profileSuite struct {
+	suite.Suite
+
+	ctrl     *gomock.Controller
+	dir      string
+	mainFile string
+}
+
+func TestProfiles(t *testing.T) {
+	suite.Run(t, new(profileSuite))
+}
+
+func (s *profileSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.dir = s.T().TempDir()
+	s.mainFile = filepath.Join(s.dir, "config.yaml")
+	s.Require().NoError(os.WriteFile(s.mainFile, []byte(mainContent), 0o600))
+	s.Require().NoError(os.WriteFile(filepath.Join(s.dir, "config.staging.yaml"), []byte(stagingContent), 0o600))
+}
+
+func (s *profileSuite) TestProfileFile() {
+	s.Equal("config/config.staging.yaml", ProfileFile("config/config.yaml", "staging"))
+	s.Equal("config.dev", ProfileFile("config", "dev"))
+}
+
+func (s *profileSuite) TestProfileFromConfigKey() {
+	inner := s.innerBuilder("staging", map[string]interface{}{"mortar.logger.level": "info", "mortar.name": "profiles"})
+	config, err := WithProfiles(inner).SetConfigFile(s.mainFile).Build()
+	s.Require().NoError(err)
+
+	s.Equal("info", config.Get("mortar.logger.level").String())
+	s.False(config.Get(confkeys.ConfigHandlerObfuscateKeys).IsSet(), "key was deleted by the overlay")
+	mortarMap := config.Map()["mortar"].(map[string]interface{})
+	s.NotContains(mortarMap, "handlers")
+	s.Contains(mortarMap, "logger")
+
+	sources := config.(cfg.ValueSources).Sources()
+	s.Equal(filepath.Join(s.dir, "config.staging.yaml"), sources["mortar.logger.level"])
+	s.Equal(s.mainFile, sources["mortar.name"])
+	s.NotContains(sources, "mortar.handlers.config.obfuscate")
+}
+
+func (s *profileSuite) TestOnChange() {
+	inner := s.innerBuilder("staging", map[string]interface{}{"mortar.logger.level": "info"})
+	config, err := WithProfiles(inner).SetConfigFile(s.mainFile).Build()
+	s.Require().NoError(err)
+	var changed, subChanged []string
+	config.OnChange(func(key string) {
+		changed = append(changed, key)
+	})
+	config.Sub("mortar.logger").OnChange(func(key string) {
+		subChanged = append(subChanged, key)
+	})
+
+	config.Set("mortar.name", "changed")
+	config.Sub("mortar.logger").Set("level", "warn")
+	s.Equal([]string{"mortar.name", "mortar.logger.level"}, changed)
+	s.Equal([]string{"level"}, subChanged)
+	s.Equal("warn", config.Get("mortar.logger.level").String())
+}
+
+func (s *profileSuite) TestProfileFromEnvironment() {
+	s.T().Setenv(ProfileEnvironmentVariable, "missing")
+	inner := mock_cfg.NewMockBuilder(s.ctrl)
+	inner.EXPECT().SetConfigFile(s.mainFile).Return(inner)
+	inner.EXPECT().Build().Return(mock_cfg.NewMockConfig(s.ctrl), nil)
+	_, err := WithProfiles(inner).SetConfigFile(s.mainFile).Build()
+	s.ErrorContains(err, "profile [missing] configuration file is not accessible")
+}
+
+func (s *profileSuite) TestSetOverridesDeletedKey() {
+	inner := s.innerBuilder("staging", map[string]interface{}{"mortar.logger.level": "info"})
+	config, err := WithProfiles(inner).SetConfigFile(s.mainFile).Build()
+	s.Require().NoError(err)
+	config.Set(confkeys.ConfigHandlerObfuscateKeys, []string{"secret"})
+	s.True(config.Get(confkeys.ConfigHandlerObfuscateKeys).IsSet())
+	s.Equal(SourceSet, config.(cfg.ValueSources).Sources()[strings.ToLower(confkeys.ConfigHandlerObfuscateKeys)])
+}
+
+func (s *profileSuite) TestProfileFromFlag() {
+	s.T().Setenv(ProfileEnvironmentVariable, "missing")
+	s.Require().NoError(os.WriteFile(filepath.Join(s.dir, "config.prod.yaml"), []byte(stagingContent), 0o600))
+	inner := s.innerBuilder("prod", map[string]interface{}{"mortar.logger.level": "info"})
+	var output bytes.Buffer
+	exitCode := -1
+	builder := WithFlags(WithProfiles(inner)).
+		SetArguments([]string{"--mortar.profile=prod", "--set", "mortar.name=flags", "--print-config"}).
+		SetOutput(&output)
+	builder.(*flagsBuilder).exit = func(code int) { exitCode = code }
+	config, err := builder.SetConfigFile(s.mainFile).Build()
+	s.Require().NoError(err)
+	s.Equal(0, exitCode)
+	s.NotContains(output.String(), "handlers", "configuration is printed once overlays are merged")
+	s.Contains(output.String(), `"name": "flags"`)
+
+	sources := config.(cfg.ValueSources).Sources()
+	s.Equal(SourceFlag, sources["mortar.name"])
+	s.Equal(SourceFlag, sources["mortar.profile"])
+	s.Equal(filepath.Join(s.dir, "config.prod.yaml"), sources["mortar.logger.level"])
+}
+
+func (s *profileSuite) TestFlagsInsideProfiles() {
+	_, err := WithProfiles(WithFlags(mock_cfg.NewMockBuilder(s.ctrl))).Build()
+	s.ErrorContains(err, "use config.WithFlags(config.WithProfiles(builder)) instead")
+}
+
+func (s *profileSuite) TestOverriddenValueSources() {
+	inner := s.innerBuilder("staging", map[string]interface{}{"mortar.logger.level": "warn", "mortar.name": "other"})
+	config, err := WithProfiles(inner).SetConfigFile(s.mainFile).Build()
+	s.Require().NoError(err)
+	sources := config.(cfg.ValueSources).Sources()
+	s.Equal(SourceOverride, sources["mortar.logger.level"], "inner config can't tell where the value came from")
+	s.Equal(SourceOverride, sources["mortar.name"])
+}
+
+func (s *profileSuite) TestInnerValueSources() {
+	innerSources := mock_cfg.NewMockValueSources(s.ctrl)
+	innerSources.EXPECT().Sources().Return(map[string]string{"mortar.logger.level": "env"})
+	inner := s.innerBuilder("staging", map[string]interface{}{"mortar.logger.level": "warn"})
+	config, err := WithProfiles(&sourcesBuilder{Builder: inner, sources: innerSources}).SetConfigFile(s.mainFile).Build()
+	s.Require().NoError(err)
+	sources := config.(cfg.ValueSources).Sources()
+	s.Equal("env", sources["mortar.logger.level"])
+	s.Equal(s.mainFile, sources["mortar.profile"])
+}
+
+// sourcesBuilder builds configurations that also implement cfg.ValueSources
+type sourcesBuilder struct {
+	cfg.Builder
+	sources cfg.ValueSources
+}
+
+func (b *sourcesBuilder) SetConfigFile(path string) cfg.Builder {
+	b.Builder.SetConfigFile(path)
+	return b
+}
+
+func (b *sourcesBuilder) Build() (cfg.Config, error) {
+	config, err := b.Builder.Build()
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		cfg.Config
+		cfg.ValueSources
+	}{config, b.sources}, nil
+}
+
+// innerBuilder mocks a configuration library that merges files, but doesn't know how to delete keys
+func (s *profileSuite) innerBuilder(profile string, final map[string]interface{}) cfg.Builder {
+	config := mock_cfg.NewMockConfig(s.ctrl)
+	values := map[string]interface{}{
+		"mortar.handlers.config.obfuscate": []interface{}{"pass"},
+		"mortar.profile":                   profile,
+	}
+	for k, v := range final {
+		values[k] = v
+	}
+	config.EXPECT().Get(gomock.Any()).DoAndReturn(func(key string) cfg.Value {
+		value := mock_cfg.NewMockValue(s.ctrl)
+		raw, ok := values[strings.ToLower(key)]
+		str, _ := raw.(string)
+		value.EXPECT().IsSet().Return(ok).AnyTimes()
+		value.EXPECT().String().Return(str).AnyTimes()
+		return value
+	}).AnyTimes()
+	config.EXPECT().Set(gomock.Any(), gomock.Any()).Do(func(key string, value interface{}) {
+		values[strings.ToLower(key)] = value
+	}).AnyTimes()
+	config.EXPECT().Map().DoAndReturn(func() map[string]interface{} {
+		output := make(map[string]interface{})
+		for k, v := range values {
+			current := output
+			parts := strings.Split(k, ".")
+			for _, part := range parts[:len(parts)-1] {
+				if _, ok := current[part]; !ok {
+					current[part] = make(map[string]interface{})
+				}
+				current = current[part].(map[string]interface{})
+			}
+			current[parts[len(parts)-1]] = v
+		}
+		return output
+	}).AnyTimes()
+
+	builder := mock_cfg.NewMockBuilder(s.ctrl)
+	builder.EXPECT().SetConfigFile(s.mainFile).Return(builder)
+	builder.EXPECT().AddExtraConfigFile(filepath.Join(s.dir, "config."+profile+".yaml")).Return(builder)
+	builder.EXPECT().Build().Return(config, nil).Times(2)
+	return builder
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-masonry/mortar/interfaces/cfg"
+)
+
+type subConfig struct {
+	parent cfg.Config
+	prefix string
+}
+
+// Sub creates a view of parent rooted at prefix, every key used with this view is relative to the prefix.
+//
+// Config implementations can use it to implement cfg.Config.Sub
+func Sub(parent cfg.Config, prefix string) cfg.Config {
+	if sub, ok := parent.(*subConfig); ok { // avoid nesting views
+		return &subConfig{parent: sub.parent, prefix: joinKeys(sub.prefix, prefix)}
+	}
+	return &subConfig{parent: parent, prefix: prefix}
+}
+
+func (s *subConfig) Get(key string) cfg.Value {
+	return s.parent.Get(joinKeys(s.prefix, key))
+}
+
+func (s *subConfig) Set(key string, value interface{}) {
+	s.parent.Set(joinKeys(s.prefix, key), value)
+}
+
+func (s *subConfig) Map() map[string]interface{} {
+	return subMap(s.parent.Map(), s.prefix)
+}
+
+func (s *subConfig) Sub(prefix string) cfg.Config {
+	return Sub(s, prefix)
+}
+
+func (s *subConfig) Keys(prefix string) []string {
+	return Keys(s.Map(), prefix)
+}
+
+// OnChange calls callback for keys under the prefix, a change of the prefix itself or one of its parents
+// is reported with an empty key since the entire view might have changed
+func (s *subConfig) OnChange(callback func(key string)) {
+	s.parent.OnChange(func(key string) {
+		if relative, ok := relativeKey(s.prefix, key); ok {
+			callback(relative)
+		}
+	})
+}
+
+func (s *subConfig) Implementation() interface{} {
+	return s.parent.Implementation()
+}
+
+// Keys returns all the leaf keys (using '.' notation) of confMap found under prefix, sorted.
+//
+// Config implementations can use it to implement cfg.Config.Keys
+func Keys(confMap map[string]interface{}, prefix string) []string {
+	values := make(map[string]interface{})
+	flatten("", subMap(confMap, prefix), values)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, joinKeys(strings.ToLower(prefix), key))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// relativeKey returns key relative to prefix and whether key is affecting the prefix tree, keys are matched case insensitively
+func relativeKey(prefix, key string) (string, bool) {
+	lowerPrefix, lowerKey := strings.ToLower(prefix), strings.ToLower(key)
+	switch {
+	case len(prefix) == 0:
+		return key, true
+	case strings.HasPrefix(lowerKey, lowerPrefix+keyDelimiter):
+		return key[len(prefix)+len(keyDelimiter):], true
+	case lowerKey == lowerPrefix || strings.HasPrefix(lowerPrefix, lowerKey+keyDelimiter):
+		return "", true
+	}
+	return "", false
+}
+
+// subMap returns the part of confMap found under prefix, keys are matched case insensitively
+func subMap(confMap map[string]interface{}, prefix string) map[string]interface{} {
+	if len(prefix) == 0 {
+		return confMap
+	}
+	current := confMap
+	for _, part := range strings.Split(prefix, keyDelimiter) {
+		var next map[string]interface{}
+		for k, v := range current {
+			if strings.EqualFold(k, part) {
+				next, _ = v.(map[string]interface{})
+				break
+			}
+		}
+		if next == nil {
+			return map[string]interface{}{}
+		}
+		current = next
+	}
+	return current
+}
+
+var _ cfg.Config = (*subConfig)(nil)
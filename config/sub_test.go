@@ -0,0 +1,82 @@
+package config
+
+import (
+	"testing"
+
+	mock_cfg "github.com/go-masonry/mortar/interfaces/cfg/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func testMap() map[string]interface{} {
+	return map[string]interface{}{
+		"mortar": map[string]interface{}{
+			"name": "sub",
+			"middleware": map[string]interface{}{
+				"trace": map[string]interface{}{
+					"grpc": map[string]interface{}{
+						"client": map[string]interface{}{
+							"request":  true,
+							"response": false,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestSubGetAndSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	parent := mock_cfg.NewMockConfig(ctrl)
+	value := mock_cfg.NewMockValue(ctrl)
+	parent.EXPECT().Get("mortar.middleware.trace.grpc.client.request").Return(value)
+	parent.EXPECT().Set("mortar.middleware.trace.grpc.client.response", true)
+
+	sub := Sub(parent, "mortar.middleware").Sub("trace")
+	assert.Equal(t, value, sub.Get("grpc.client.request"))
+	sub.Set("grpc.client.response", true)
+}
+
+func TestSubOnChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	parent := mock_cfg.NewMockConfig(ctrl)
+	var notify func(key string)
+	parent.EXPECT().OnChange(gomock.Any()).Do(func(callback func(key string)) {
+		notify = callback
+	})
+
+	var changed []string
+	Sub(parent, "mortar.middleware").Sub("trace").OnChange(func(key string) {
+		changed = append(changed, key)
+	})
+	notify("mortar.middleware.trace.grpc.client.request")
+	notify("Mortar.Middleware.Trace.grpc")
+	notify("mortar.middleware.tracer")
+	notify("mortar.name")
+	notify("mortar.middleware.trace")
+	notify("mortar")
+	assert.Equal(t, []string{"grpc.client.request", "grpc", "", ""}, changed)
+}
+
+func TestSubMapAndKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	parent := mock_cfg.NewMockConfig(ctrl)
+	parent.EXPECT().Map().DoAndReturn(testMap).AnyTimes()
+
+	sub := Sub(parent, "mortar.Middleware.trace")
+	assert.Equal(t, map[string]interface{}{"request": true, "response": false}, sub.Sub("grpc.client").Map())
+	assert.Equal(t, []string{"grpc.client.request", "grpc.client.response"}, sub.Keys(""))
+	assert.Equal(t, []string{"grpc.client.request", "grpc.client.response"}, sub.Keys("grpc"))
+	assert.Empty(t, sub.Keys("http"))
+	assert.Empty(t, Sub(parent, "mortar.name").Map())
+}
+
+func TestKeys(t *testing.T) {
+	assert.Equal(t, []string{
+		"mortar.middleware.trace.grpc.client.request",
+		"mortar.middleware.trace.grpc.client.response",
+		"mortar.name",
+	}, Keys(testMap(), ""))
+	assert.Empty(t, Keys(testMap(), "mortar.name"), "only sub trees have leaf keys")
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"time"
+
+	"github.com/go-masonry/mortar/interfaces/cfg"
+)
+
+// unsetValue is returned for keys that were deleted, it behaves like a missing key
+type unsetValue struct{}
+
+func (unsetValue) IsSet() bool                               { return false }
+func (unsetValue) Raw() interface{}                          { return nil }
+func (unsetValue) Bool() bool                                { return false }
+func (unsetValue) Int() int                                  { return 0 }
+func (unsetValue) Int32() int32                              { return 0 }
+func (unsetValue) Int64() int64                              { return 0 }
+func (unsetValue) Uint() uint                                { return 0 }
+func (unsetValue) Uint32() uint32                            { return 0 }
+func (unsetValue) Uint64() uint64                            { return 0 }
+func (unsetValue) Float64() float64                          { return 0 }
+func (unsetValue) Time() time.Time                           { return time.Time{} }
+func (unsetValue) Duration() time.Duration                   { return 0 }
+func (unsetValue) String() string                            { return "" }
+func (unsetValue) IntSlice() []int                           { return []int{} }
+func (unsetValue) StringSlice() []string                     { return []string{} }
+func (unsetValue) StringMap() map[string]interface{}         { return map[string]interface{}{} }
+func (unsetValue) StringMapString() map[string]string        { return map[string]string{} }
+func (unsetValue) StringMapStringSlice() map[string][]string { return map[string][]string{} }
+func (unsetValue) Unmarshal(result interface{}) error        { return nil }
+
+var _ cfg.Value = unsetValue{}
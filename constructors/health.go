@@ -0,0 +1,27 @@
+package constructors
+
+import (
+	healthImpl "github.com/go-masonry/mortar/health"
+	"github.com/go-masonry/mortar/interfaces/health"
+	"go.uber.org/fx"
+)
+
+// FxGroupHealthChecks defines group name
+const FxGroupHealthChecks = "healthChecks"
+
+type healthRegistryDeps struct {
+	fx.In
+
+	Checks []health.Check `group:"healthChecks"`
+}
+
+// HealthRegistry is a constructor that creates a health.Registry with all the checks provided to the health checks group
+func HealthRegistry(deps healthRegistryDeps) (health.Registry, error) {
+	registry := healthImpl.NewRegistry()
+	for _, check := range deps.Checks {
+		if err := registry.Register(check); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
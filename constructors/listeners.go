@@ -0,0 +1,99 @@
+package constructors
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/go-masonry/mortar/http/server/inherit"
+	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	"github.com/go-masonry/mortar/interfaces/log"
+	"go.uber.org/fx"
+)
+
+type listenersDeps struct {
+	fx.In
+
+	LifeCycle  fx.Lifecycle
+	Config     cfg.Config
+	Logger     log.Logger
+	Shutdowner fx.Shutdowner
+}
+
+// Listeners is a constructor that creates inherit.Listeners from the listeners this process inherited, if any.
+//
+// If enabled by configuration, listeners are handed to a new instance of the service on inherit.HandoffSignal
+// and once the new instance is up this one stops gracefully. If the new instance fails to start in time,
+// this one keeps serving.
+func Listeners(deps listenersDeps) (*inherit.Listeners, error) {
+	listeners, err := inherit.FromEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	if count := listeners.Inherited(); count > 0 {
+		deps.Logger.Debug(context.Background(), "Inherited %d listeners", count)
+	}
+	handoff := deps.Config.Get(confkeys.ListenersHandoff).Bool()
+	if handoff && inherit.HandoffSignal == nil {
+		return nil, fmt.Errorf("listeners handoff is not supported on this platform")
+	}
+	signals := make(chan os.Signal, 1)
+	stop := make(chan struct{})
+	deps.LifeCycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			if handoff {
+				signal.Notify(signals, inherit.HandoffSignal)
+				go deps.handoffOnSignal(listeners, signals, stop)
+			}
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			if handoff {
+				signal.Stop(signals)
+				close(stop)
+			}
+			return listeners.Close()
+		},
+	})
+	return listeners, nil
+}
+
+func (deps listenersDeps) handoffOnSignal(listeners *inherit.Listeners, signals <-chan os.Signal, stop <-chan struct{}) {
+	for {
+		select {
+		case <-signals:
+			process, err := deps.handoff(listeners, stop)
+			if err != nil {
+				deps.Logger.WithError(err).Error(context.Background(), "Failed to hand listeners to a new process, still serving")
+				continue
+			}
+			deps.Logger.Info(context.Background(), "Listeners were handed to process %d, stopping", process.Pid)
+			if err = deps.Shutdowner.Shutdown(); err != nil {
+				deps.Logger.WithError(err).Error(context.Background(), "Failed to stop after listeners handoff")
+			}
+			return
+		case <-stop:
+			return
+		}
+	}
+}
+
+// handoff waits for the new process to be up, unless this one is stopped first
+func (deps listenersDeps) handoff(listeners *inherit.Listeners, stop <-chan struct{}) (*os.Process, error) {
+	timeout := inherit.DefaultHandoffTimeout
+	if value := deps.Config.Get(confkeys.ListenersHandoffTimeout); value.IsSet() {
+		timeout = value.Duration()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return listeners.Handoff(ctx)
+}
@@ -0,0 +1,82 @@
+package constructors_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-masonry/mortar/constructors"
+	"github.com/go-masonry/mortar/http/server/inherit"
+	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	mock_cfg "github.com/go-masonry/mortar/interfaces/cfg/mock"
+	"github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/logger"
+	"github.com/go-masonry/mortar/logger/logtest"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+const handoffHelperEnv = "CONSTRUCTORS_TEST_HANDOFF"
+
+func TestMain(m *testing.M) {
+	if len(os.Getenv(handoffHelperEnv)) > 0 { // started by a listeners handoff, fail before reporting it's up
+		fmt.Fprintln(os.Stderr, "failed to start")
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func TestListenersHandoffToFailedProcess(t *testing.T) {
+	if inherit.HandoffSignal == nil {
+		t.Skip("listeners handoff is not supported on this platform")
+	}
+	t.Setenv(handoffHelperEnv, "true")
+	ctrl := gomock.NewController(t)
+	cfgMock := mock_cfg.NewMockConfig(ctrl)
+	cfgMock.EXPECT().Get(confkeys.ListenersHandoff).DoAndReturn(func(key string) cfg.Value {
+		value := mock_cfg.NewMockValue(ctrl)
+		value.EXPECT().Bool().Return(true)
+		return value
+	})
+	cfgMock.EXPECT().Get(confkeys.ListenersHandoffTimeout).DoAndReturn(func(key string) cfg.Value {
+		value := mock_cfg.NewMockValue(ctrl)
+		value.EXPECT().IsSet().Return(true)
+		value.EXPECT().Duration().Return(10 * time.Second)
+		return value
+	})
+	recorder := logtest.New().Echo(t)
+	var listeners *inherit.Listeners
+	app := fxtest.New(t,
+		fx.Supply(fx.Annotate(cfgMock, fx.As(new(cfg.Config)))),
+		fx.Provide(
+			func() log.Logger {
+				return logger.CreateMortarLogger(recorder.Builder())
+			},
+			constructors.Listeners,
+		),
+		fx.Populate(&listeners),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+	listener, err := listeners.Listen(inherit.GRPCListenerName, "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	self, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, self.Signal(inherit.HandoffSignal))
+	assert.Eventually(t, func() bool {
+		return len(recorder.Find(log.ErrorLevel, "still serving", nil)) > 0
+	}, 10*time.Second, 10*time.Millisecond)
+	recorder.AssertLogged(t, log.ErrorLevel, "Failed to hand listeners to a new process", nil)
+	select {
+	case <-app.Done():
+		t.Fatal("service was stopped although the new process failed")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -3,14 +3,21 @@ package constructors
 import (
 	"context"
 	"log"
+	"syscall"
+	"time"
 
 	"github.com/go-masonry/mortar/logger"
+	"github.com/go-masonry/mortar/logger/async"
+	"github.com/go-masonry/mortar/logger/file"
 	"github.com/go-masonry/mortar/logger/naive"
+	"github.com/go-masonry/mortar/logger/stdlog"
 
 	"github.com/go-masonry/mortar/interfaces/cfg"
 	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
 	logInt "github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/interfaces/monitor"
 	"github.com/go-masonry/mortar/mortar"
+	"github.com/go-masonry/mortar/utils"
 
 	"go.uber.org/fx"
 )
@@ -23,10 +30,15 @@ const (
 	gitCommit   = "git"
 )
 const compensateDefaultLogger = 1
+const (
+	defaultSamplingSummaryInterval = time.Minute
+	droppedLogEntriesMetric        = "logger_dropped_entries"
+)
 
 type loggerDeps struct {
 	fx.In
 
+	LifeCycle         fx.Lifecycle
 	Config            cfg.Config
 	LoggerBuilder     logInt.Builder            `optional:"true"`
 	ContextExtractors []logInt.ContextExtractor `group:"loggerContextExtractors"`
@@ -39,8 +51,148 @@ func DefaultLogger(deps loggerDeps) logInt.Logger {
 		logLevel = logInt.ParseLevel(levelValue.String())
 	}
 
-	builder := deps.getLogBuilder().SetLevel(logLevel).IncrementSkipFrames(compensateDefaultLogger)
-	return logger.CreateMortarLogger(builder, append(deps.ContextExtractors, deps.selfStaticFieldsContextExtractor)...)
+	builder := deps.asyncIfNeeded(deps.getLogBuilder()).IncrementSkipFrames(compensateDefaultLogger)
+	wrapperBuilder := logger.Builder().
+		SetLevel(logLevel). // can be changed at runtime
+		SetNamedLevels(deps.namedLevels()).
+		SetFieldRedactor(utils.RedactorFromConfig(deps.Config).Value).
+		AddExtractors(append(deps.ContextExtractors, deps.selfStaticFieldsContextExtractor)...)
+	if policy, enabled := deps.samplingPolicy(); enabled {
+		wrapperBuilder = wrapperBuilder.SetSampling(policy)
+	}
+	if policy, enabled := deps.recentLogsPolicy(); enabled {
+		wrapperBuilder = wrapperBuilder.SetRecentLogs(policy)
+	}
+	return wrapperBuilder.Build(builder)
+}
+
+type loggerSamplingMetricsDeps struct {
+	fx.In
+
+	Logger  logInt.Logger
+	Metrics monitor.Metrics `optional:"true"`
+}
+
+// LoggerSamplingMetrics counts log entries dropped by sampling, if both sampling is enabled and Metrics are available.
+//
+// Metrics depend on the Logger, that's why it can't be done while building the Logger.
+func LoggerSamplingMetrics(deps loggerSamplingMetricsDeps) {
+	sampled, ok := deps.Logger.(logger.Sampled)
+	if !ok || sampled.Sampling() == nil || deps.Metrics == nil {
+		return
+	}
+	counter := deps.Metrics.Counter(droppedLogEntriesMetric, "log entries dropped by sampling")
+	sampled.Sampling().OnDrop(func(level logInt.Level) {
+		counter.WithTags(monitor.Tags{"level": level.String()}).Inc()
+	})
+}
+
+type redirectStandardLogsDeps struct {
+	fx.In
+
+	LifeCycle fx.Lifecycle
+	Logger    logInt.Logger
+}
+
+// RedirectStandardLogs redirects the standard library `log` package and gRPC `grpclog` into the Logger.
+//
+// This includes Mortar own warnings, including the ones of DefaultLogger that are printed once the application starts,
+// and the default monitoring error handler. The standard library logger is restored on stop, grpclog is not since
+// it can't be safely replaced while gRPC might still be logging.
+//
+// **Important**
+//
+//	Logger must not write to the standard library logger, otherwise it will write to itself.
+func RedirectStandardLogs(deps redirectStandardLogsDeps) {
+	previousWriter, previousFlags := log.Writer(), log.Flags()
+	log.SetFlags(0) // time and caller are added by the Logger
+	log.SetOutput(stdlog.Writer(deps.Logger, logInt.InfoLevel, stdlog.SourceStdLog))
+	stdlog.RedirectGRPC(deps.Logger)
+	deps.LifeCycle.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			log.SetOutput(previousWriter)
+			log.SetFlags(previousFlags)
+			return nil
+		},
+	})
+}
+
+// RESTServerErrorLog creates a standard library logger for REST server errors (TLS handshakes, panics, ...)
+// that writes to the Logger
+func RESTServerErrorLog(logger logInt.Logger) *log.Logger {
+	return stdlog.New(logger, logInt.WarnLevel, stdlog.SourceHTTPServer)
+}
+
+// namedLevels flattens nested maps, so both `http.client: debug` and `http: {client: debug}` are supported
+func (d loggerDeps) namedLevels() map[string]logInt.Level {
+	output := make(map[string]logInt.Level)
+	var flatten func(prefix string, levels map[string]interface{})
+	flatten = func(prefix string, levels map[string]interface{}) {
+		for name, value := range levels {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				flatten(prefix+name+".", v)
+			case string:
+				output[prefix+name] = logInt.ParseLevel(v)
+			}
+		}
+	}
+	flatten("", d.Config.Get(confkeys.LogLevels).StringMap())
+	return output
+}
+
+// asyncIfNeeded wraps builder with an asynchronous builder if enabled, the queue is flushed when the application stops
+func (d loggerDeps) asyncIfNeeded(builder logInt.Builder) logInt.Builder {
+	if !d.Config.Get(confkeys.LogAsyncEnabled).Bool() {
+		return builder
+	}
+	return async.Wrap(builder).
+		SetQueueSize(d.Config.Get(confkeys.LogAsyncQueueSize).Int()).
+		SetPolicy(async.ParsePolicy(d.Config.Get(confkeys.LogAsyncPolicy).String())).
+		OnBuild(func(asyncLogger async.Logger) {
+			d.LifeCycle.Append(fx.Hook{
+				OnStop: func(ctx context.Context) error {
+					if dropped := asyncLogger.Dropped(); dropped > 0 {
+						asyncLogger.Warn(ctx, "asynchronous logger dropped %d entries", dropped)
+					}
+					return asyncLogger.Close(ctx)
+				},
+			})
+		})
+}
+
+func (d loggerDeps) samplingPolicy() (policy logger.SamplingPolicy, enabled bool) {
+	policy = logger.SamplingPolicy{
+		First:           d.Config.Get(confkeys.LogSamplingFirst).Int(),
+		Thereafter:      d.Config.Get(confkeys.LogSamplingThereafter).Int(),
+		Tick:            d.Config.Get(confkeys.LogSamplingTick).Duration(),
+		IncludeErrors:   d.Config.Get(confkeys.LogSamplingIncludeErrors).Bool(),
+		SummaryInterval: defaultSamplingSummaryInterval,
+		RateLimits:      make(map[logInt.Level]int),
+	}
+	if summary := d.Config.Get(confkeys.LogSamplingSummaryInterval); summary.IsSet() {
+		policy.SummaryInterval = summary.Duration()
+	}
+	rateLimits := d.Config.Sub(confkeys.LogSamplingRateLimits)
+	for _, level := range rateLimits.Keys("") {
+		policy.RateLimits[logInt.ParseLevel(level)] = rateLimits.Get(level).Int()
+	}
+	return policy, policy.First > 0 || len(policy.RateLimits) > 0
+}
+
+func (d loggerDeps) recentLogsPolicy() (policy logger.RecentLogsPolicy, enabled bool) {
+	policy = logger.RecentLogsPolicy{
+		Size:       d.Config.Get(confkeys.LogRecentSize).Int(),
+		LevelSizes: make(map[logInt.Level]int),
+	}
+	enabled = policy.Size > 0
+	levels := d.Config.Sub(confkeys.LogRecentLevels)
+	for _, level := range levels.Keys("") {
+		size := levels.Get(level).Int()
+		policy.LevelSizes[logInt.ParseLevel(level)] = size
+		enabled = enabled || size > 0
+	}
+	return policy, enabled
 }
 
 func (d loggerDeps) selfStaticFieldsContextExtractor(_ context.Context) map[string]interface{} {
@@ -61,8 +213,61 @@ func (d loggerDeps) selfStaticFieldsContextExtractor(_ context.Context) map[stri
 
 func (d loggerDeps) getLogBuilder() logInt.Builder {
 	if d.LoggerBuilder != nil {
+		if nativeBuilder, ok := d.LoggerBuilder.(naive.NativeLogBuilder); ok {
+			return d.fileIfNeeded(nativeBuilder)
+		}
+		if path := d.Config.Get(confkeys.LogFilePath).String(); len(path) > 0 {
+			d.warnOnStart("[Mortar] WARNING \tLog file %s is ignored, it's only supported by the default logger builder. Set a writer on the provided logger builder instead", path)
+		}
 		return d.LoggerBuilder
 	}
-	log.Printf("[Mortar] WARNING \tNo logger builder provided, using default logger. Some features will not be supported")
-	return naive.Builder()
+	d.warnOnStart("[Mortar] WARNING \tNo logger builder provided, using default logger. Some features will not be supported")
+	return d.fileIfNeeded(naive.Builder())
+}
+
+// warnOnStart prints a warning with the standard library logger once the application starts,
+// by then it's redirected into the Logger if RedirectStandardLogs is used
+func (d loggerDeps) warnOnStart(format string, args ...interface{}) {
+	d.LifeCycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			log.Printf(format, args...)
+			return nil
+		},
+	})
+}
+
+// fileIfNeeded sets a rotating file writer if configured and builder has no writer of its own,
+// the file is closed when the application stops
+func (d loggerDeps) fileIfNeeded(builder naive.NativeLogBuilder) logInt.Builder {
+	path := d.Config.Get(confkeys.LogFilePath).String()
+	if len(path) == 0 {
+		return builder
+	}
+	if builder.HasWriter() {
+		d.warnOnStart("[Mortar] WARNING \tLog file %s is ignored, the provided logger builder already has a writer", path)
+		return builder
+	}
+	fileBuilder := file.Builder().
+		SetPath(path).
+		SetMaxSize(d.Config.Get(confkeys.LogFileMaxSize).Int64() << 20).
+		SetInterval(d.Config.Get(confkeys.LogFileInterval).Duration()).
+		SetMaxBackups(d.Config.Get(confkeys.LogFileMaxBackups).Int()).
+		SetMaxAge(d.Config.Get(confkeys.LogFileMaxAge).Duration())
+	if d.Config.Get(confkeys.LogFileCompress).Bool() {
+		fileBuilder = fileBuilder.Compress()
+	}
+	if d.Config.Get(confkeys.LogFileReopenOnSIGHUP).Bool() {
+		fileBuilder = fileBuilder.ReopenOn(syscall.SIGHUP)
+	}
+	writer, err := fileBuilder.Build()
+	if err != nil {
+		d.warnOnStart("[Mortar] WARNING \tFailed to open log file %s, using stderr. %v", path, err)
+		return builder
+	}
+	d.LifeCycle.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			return writer.Close()
+		},
+	})
+	return builder.SetWriter(writer)
 }
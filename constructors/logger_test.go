@@ -0,0 +1,111 @@
+package constructors_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-masonry/mortar/constructors"
+	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	mock_cfg "github.com/go-masonry/mortar/interfaces/cfg/mock"
+	logInt "github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/logger/logtest"
+	"github.com/go-masonry/mortar/logger/naive"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+// logFileConfig mocks a configuration where only the log file path is set
+func logFileConfig(ctrl *gomock.Controller, logFile string) cfg.Config {
+	config := mock_cfg.NewMockConfig(ctrl)
+	config.EXPECT().Get(gomock.Any()).DoAndReturn(func(key string) cfg.Value {
+		value := mock_cfg.NewMockValue(ctrl)
+		path := ""
+		if key == confkeys.LogFilePath {
+			path = logFile
+		}
+		value.EXPECT().String().Return(path).AnyTimes()
+		value.EXPECT().IsSet().Return(len(path) > 0).AnyTimes()
+		value.EXPECT().Bool().Return(false).AnyTimes()
+		value.EXPECT().Int().Return(0).AnyTimes()
+		value.EXPECT().Int64().Return(int64(0)).AnyTimes()
+		value.EXPECT().Duration().Return(time.Duration(0)).AnyTimes()
+		value.EXPECT().StringSlice().Return(nil).AnyTimes()
+		value.EXPECT().StringMap().Return(nil).AnyTimes()
+		return value
+	}).AnyTimes()
+	empty := mock_cfg.NewMockConfig(ctrl)
+	empty.EXPECT().Keys(gomock.Any()).Return(nil).AnyTimes()
+	config.EXPECT().Sub(gomock.Any()).Return(empty).AnyTimes()
+	return config
+}
+
+func TestRedirectStandardLogsCapturesLoggerWarnings(t *testing.T) {
+	var output bytes.Buffer
+	app := fxtest.New(t,
+		fx.Supply(fx.Annotate(logFileConfig(gomock.NewController(t), "app.log"), fx.As(new(cfg.Config)))),
+		fx.Supply(fx.Annotate(naive.Builder().SetWriter(&output).JSON(), fx.As(new(logInt.Builder)))),
+		fx.Provide(constructors.DefaultLogger),
+		fx.Invoke(constructors.RedirectStandardLogs),
+	)
+	app.RequireStart()
+	app.RequireStop()
+	assert.Contains(t, output.String(), "Log file app.log is ignored, the provided logger builder already has a writer")
+	assert.Contains(t, output.String(), `"source":"stdlog"`)
+}
+
+func TestLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	var logger logInt.Logger
+	app := fxtest.New(t,
+		fx.Supply(fx.Annotate(logFileConfig(gomock.NewController(t), path), fx.As(new(cfg.Config)))),
+		fx.Supply(fx.Annotate(naive.Builder().JSON(), fx.As(new(logInt.Builder)))),
+		fx.Provide(constructors.DefaultLogger),
+		fx.Populate(&logger),
+	)
+	app.RequireStart()
+	logger.Info(nil, "written to file")
+	app.RequireStop()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "written to file")
+}
+
+func TestLogFileFailedToOpen(t *testing.T) {
+	notADirectory := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(notADirectory, nil, 0600))
+	var output bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&output)
+	app := fxtest.New(t,
+		fx.Supply(fx.Annotate(logFileConfig(gomock.NewController(t), filepath.Join(notADirectory, "app.log")), fx.As(new(cfg.Config)))),
+		fx.Supply(fx.Annotate(naive.Builder(), fx.As(new(logInt.Builder)))),
+		fx.Provide(constructors.DefaultLogger),
+		fx.Invoke(func(logInt.Logger) {}),
+	)
+	app.RequireStart()
+	app.RequireStop()
+	assert.Contains(t, output.String(), "Failed to open log file")
+}
+
+func TestLogFileUnsupportedBuilder(t *testing.T) {
+	var output bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&output)
+	app := fxtest.New(t,
+		fx.Supply(fx.Annotate(logFileConfig(gomock.NewController(t), "app.log"), fx.As(new(cfg.Config)))),
+		fx.Supply(fx.Annotate(logtest.New().Builder(), fx.As(new(logInt.Builder)))),
+		fx.Provide(constructors.DefaultLogger),
+		fx.Invoke(func(logInt.Logger) {}),
+	)
+	app.RequireStart()
+	app.RequireStop()
+	assert.Contains(t, output.String(), "Log file app.log is ignored, it's only supported by the default logger builder")
+}
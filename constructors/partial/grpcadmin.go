@@ -0,0 +1,68 @@
+package partial
+
+import (
+	"sync"
+
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	serverInt "github.com/go-masonry/mortar/interfaces/http/server"
+	"google.golang.org/grpc"
+	channelz "google.golang.org/grpc/channelz/service"
+	"google.golang.org/grpc/reflection"
+)
+
+// adminAPIs returns gRPC reflection and channelz registrations, unless configured otherwise
+// they are enabled only on the internal gRPC server since they can enumerate all the gRPC APIs
+func (deps httpServerDeps) adminAPIs(internalGRPC bool, servers *grpcServers) (apis []serverInt.GRPCServerAPI) {
+	if deps.adminAPIEnabled(confkeys.GRPCReflection, internalGRPC) {
+		apis = append(apis, func(srv *grpc.Server) {
+			reflection.Register(reflectionServer{Server: srv, services: servers})
+		})
+	}
+	if deps.adminAPIEnabled(confkeys.GRPCChannelz, internalGRPC) {
+		apis = append(apis, func(srv *grpc.Server) {
+			channelz.RegisterChannelzServiceToServer(srv)
+		})
+	}
+	return
+}
+
+func (deps httpServerDeps) adminAPIEnabled(key string, internalGRPC bool) bool {
+	if value := deps.Config.Get(key); value.IsSet() {
+		return value.Bool()
+	}
+	return internalGRPC
+}
+
+// grpcServers collects all the gRPC servers, so reflection of the internal server describes the external APIs as well
+type grpcServers struct {
+	sync.Mutex
+	servers []*grpc.Server
+}
+
+func (g *grpcServers) add(srv *grpc.Server) {
+	g.Lock()
+	defer g.Unlock()
+	g.servers = append(g.servers, srv)
+}
+
+func (g *grpcServers) GetServiceInfo() map[string]grpc.ServiceInfo {
+	g.Lock()
+	defer g.Unlock()
+	output := make(map[string]grpc.ServiceInfo)
+	for _, srv := range g.servers {
+		for name, info := range srv.GetServiceInfo() {
+			output[name] = info
+		}
+	}
+	return output
+}
+
+// reflectionServer registers reflection on the embedded server, but reports services of all the servers
+type reflectionServer struct {
+	*grpc.Server
+	services reflection.ServiceInfoProvider
+}
+
+func (r reflectionServer) GetServiceInfo() map[string]grpc.ServiceInfo {
+	return r.services.GetServiceInfo()
+}
@@ -0,0 +1,68 @@
+package partial
+
+import (
+	"testing"
+
+	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	mock_cfg "github.com/go-masonry/mortar/interfaces/cfg/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	reflectionService = "grpc.reflection.v1.ServerReflection"
+	channelzService   = "grpc.channelz.v1.Channelz"
+)
+
+func TestAdminGRPCAPIs(t *testing.T) {
+	tests := []struct {
+		name         string
+		configured   map[string]bool
+		internalGRPC bool
+		expected     []string
+	}{
+		{name: "internal server by default", internalGRPC: true, expected: []string{reflectionService, channelzService}},
+		{name: "no internal server by default"},
+		{name: "disabled on internal server", configured: map[string]bool{confkeys.GRPCChannelz: false}, internalGRPC: true, expected: []string{reflectionService}},
+		{name: "enabled without internal server", configured: map[string]bool{confkeys.GRPCReflection: true}, expected: []string{reflectionService}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			cfgMock := mock_cfg.NewMockConfig(ctrl)
+			cfgMock.EXPECT().Get(gomock.Any()).DoAndReturn(func(key string) cfg.Value {
+				value := mock_cfg.NewMockValue(ctrl)
+				enabled, set := test.configured[key]
+				value.EXPECT().IsSet().Return(set)
+				value.EXPECT().Bool().Return(enabled).AnyTimes()
+				return value
+			}).Times(2)
+			deps := httpServerDeps{Config: cfgMock}
+			srv := grpc.NewServer()
+			for _, api := range deps.adminAPIs(test.internalGRPC, new(grpcServers)) {
+				api(srv)
+			}
+			var registered []string
+			for name := range srv.GetServiceInfo() {
+				if name == reflectionService || name == channelzService {
+					registered = append(registered, name)
+				}
+			}
+			assert.ElementsMatch(t, test.expected, registered)
+		})
+	}
+}
+
+func TestReflectionDescribesAllServers(t *testing.T) {
+	external, internal := grpc.NewServer(), grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(external, health.NewServer())
+	servers := new(grpcServers)
+	servers.add(external)
+	servers.add(internal)
+	reflection := reflectionServer{Server: internal, services: servers}
+	assert.Contains(t, reflection.GetServiceInfo(), grpc_health_v1.Health_ServiceDesc.ServiceName)
+}
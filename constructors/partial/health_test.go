@@ -0,0 +1,99 @@
+package partial_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-masonry/mortar/constructors/partial"
+	"github.com/go-masonry/mortar/handlers"
+	healthImpl "github.com/go-masonry/mortar/health"
+	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	mock_cfg "github.com/go-masonry/mortar/interfaces/cfg/mock"
+	"github.com/go-masonry/mortar/interfaces/health"
+	serverInt "github.com/go-masonry/mortar/interfaces/http/server"
+	"github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/logger"
+	"github.com/go-masonry/mortar/logger/logtest"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestNotReadyDuringDrain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cfgMock := mock_cfg.NewMockConfig(ctrl)
+	configured := map[string]interface{}{
+		confkeys.Host:               "localhost",
+		confkeys.ExternalGRPCPort:   0,
+		confkeys.InternalRESTPort:   0,
+		confkeys.ShutdownDrainDelay: time.Second,
+	}
+	cfgMock.EXPECT().Get(gomock.Any()).DoAndReturn(func(key string) cfg.Value {
+		value := mock_cfg.NewMockValue(ctrl)
+		raw, set := configured[key]
+		str, _ := raw.(string)
+		number, _ := raw.(int)
+		duration, _ := raw.(time.Duration)
+		value.EXPECT().IsSet().Return(set).AnyTimes()
+		value.EXPECT().String().Return(str).AnyTimes()
+		value.EXPECT().Int().Return(number).AnyTimes()
+		value.EXPECT().Duration().Return(duration).AnyTimes()
+		value.EXPECT().Bool().Return(false).AnyTimes()
+		value.EXPECT().StringSlice().Return(nil).AnyTimes()
+		return value
+	}).AnyTimes()
+
+	var builder serverInt.GRPCWebServiceBuilder
+	app := fxtest.New(t,
+		fx.Supply(fx.Annotate(cfgMock, fx.As(new(cfg.Config)))),
+		fx.Provide(
+			func() log.Logger {
+				return logger.CreateMortarLogger(logtest.New().Builder())
+			},
+			func() health.Registry {
+				return healthImpl.NewRegistry()
+			},
+			fx.Annotated{
+				Group:  partial.FxGroupInternalHTTPHandlers + ",flatten",
+				Target: handlers.HealthHandlers,
+			},
+			partial.HTTPServerBuilder,
+		),
+		fx.Populate(&builder),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+	service, err := builder.Build()
+	require.NoError(t, err)
+	go service.Run(context.Background())
+	var restAddress string
+	for _, info := range service.Ports() {
+		if info.Type == serverInt.RESTServer {
+			restAddress = info.Address
+		}
+	}
+	status := func(path string) int {
+		resp, err := http.Get("http://" + restAddress + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusOK, status("/health/ready"))
+	assert.Equal(t, http.StatusOK, status("/health"))
+
+	stopped := make(chan error)
+	go func() {
+		stopped <- service.Stop(context.Background())
+	}()
+	assert.Eventually(t, func() bool {
+		return status("/health/ready") == http.StatusServiceUnavailable
+	}, 500*time.Millisecond, 10*time.Millisecond, "REST readiness probe fails while draining")
+	assert.Equal(t, http.StatusServiceUnavailable, status("/health"), "mortar.health.v1 fails while draining")
+	assert.Equal(t, http.StatusOK, status("/health/live"))
+	assert.NoError(t, <-stopped)
+}
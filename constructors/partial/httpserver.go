@@ -1,13 +1,19 @@
 package partial
 
 import (
+	"context"
 	"fmt"
+	stdLog "log"
+	"net"
 	"net/http"
 
+	healthImpl "github.com/go-masonry/mortar/health"
 	"github.com/go-masonry/mortar/http/server"
 	"github.com/go-masonry/mortar/http/server/health"
+	"github.com/go-masonry/mortar/http/server/inherit"
 	"github.com/go-masonry/mortar/interfaces/cfg"
 	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	healthInt "github.com/go-masonry/mortar/interfaces/health"
 	serverInt "github.com/go-masonry/mortar/interfaces/http/server"
 	"github.com/go-masonry/mortar/interfaces/log"
 	"github.com/go-masonry/mortar/interfaces/monitor"
@@ -16,8 +22,14 @@ import (
 	"google.golang.org/grpc"
 )
 
-// PanicHandlerCounter is the metric name to count all recovered panics
-const PanicHandlerCounter = "panic_handler_total"
+const (
+	// PanicHandlerCounter is the metric name to count all recovered panics
+	PanicHandlerCounter = "panic_handler_total"
+	// ShutdownPhaseTimer is the metric name of shutdown phase durations, tagged by phase
+	ShutdownPhaseTimer = "shutdown_phase_duration"
+	// ShutdownInFlightGauge is the metric name of in-flight calls at the end of every shutdown phase, tagged by phase and server type
+	ShutdownInFlightGauge = "shutdown_in_flight"
+)
 
 // Group order is not guaranteed, if it's important then add them manually
 const (
@@ -37,6 +49,12 @@ const (
 	FxGroupUnaryServerInterceptors = "unaryServerInterceptors"
 	// FxGroupUnaryServerInterceptors defines group name
 	FxGroupStreamServerInterceptors = "streamServerInterceptors"
+	// FxGroupInternalGRPCServerAPIs defines group name
+	FxGroupInternalGRPCServerAPIs = "internalGrpcServerAPIs"
+	// FxGroupInternalUnaryServerInterceptors defines group name
+	FxGroupInternalUnaryServerInterceptors = "internalUnaryServerInterceptors"
+	// FxGroupInternalStreamServerInterceptors defines group name
+	FxGroupInternalStreamServerInterceptors = "internalStreamServerInterceptors"
 	// FxGroupInternalHTTPHandlers defines group name
 	FxGroupInternalHTTPHandlers = "internalHttpHandlers"
 	// FxGroupInternalHTTPHandlerFunctions defines group name
@@ -45,6 +63,14 @@ const (
 	FxGroupInternalHTTPInterceptors = "internalHttpInterceptors"
 )
 
+// FxNameRESTServerErrorLog names the standard library logger REST servers write their errors to (TLS handshakes, panics, ...),
+// if it's not provided they are written to the standard library default logger
+const FxNameRESTServerErrorLog = "restServerErrorLog"
+
+// FxNameExternalCORSInterceptor names the CORS interceptor of the external REST port, if it's provided
+// it also wraps external HTTP handlers and gRPC-Web/Connect calls that other external interceptors don't
+const FxNameExternalCORSInterceptor = "externalCorsInterceptor"
+
 // HTTPHandlerPatternPair defines pattern -> handler pair
 type HTTPHandlerPatternPair struct {
 	Pattern string
@@ -60,19 +86,31 @@ type HTTPHandlerFuncPatternPair struct {
 type httpServerDeps struct {
 	fx.In
 
-	Config  cfg.Config
-	Logger  log.Logger
-	Metrics monitor.Metrics `optional:"true"`
+	LifeCycle fx.Lifecycle
+	Config    cfg.Config
+	Logger    log.Logger
+	Metrics   monitor.Metrics `optional:"true"`
+	// Health checks of the internal health service
+	HealthRegistry healthInt.Registry `optional:"true"`
+	// Inherited listeners and listeners to hand off
+	Listeners *inherit.Listeners `optional:"true"`
+	// REST servers error log
+	RESTServerErrorLog *stdLog.Logger `name:"restServerErrorLog" optional:"true"`
 	// GRPC
 	GRPCServerAPIs     []serverInt.GRPCServerAPI      `group:"grpcServerAPIs"`
 	UnaryInterceptors  []grpc.UnaryServerInterceptor  `group:"unaryServerInterceptors"`
 	StreamInterceptors []grpc.StreamServerInterceptor `group:"streamServerInterceptors"`
+	// Internal GRPC
+	InternalGRPCServerAPIs     []serverInt.GRPCServerAPI      `group:"internalGrpcServerAPIs"`
+	InternalUnaryInterceptors  []grpc.UnaryServerInterceptor  `group:"internalUnaryServerInterceptors"`
+	InternalStreamInterceptors []grpc.StreamServerInterceptor `group:"internalStreamServerInterceptors"`
 	// External REST
 	GRPCGatewayGeneratedHandlers []serverInt.GRPCGatewayGeneratedHandlers `group:"grpcGatewayGeneratedHandlers"`
 	GRPCGatewayMuxOptions        []runtime.ServeMuxOption                 `group:"grpcGatewayMuxOptions"`
 	ExternalHTTPHandlers         []HTTPHandlerPatternPair                 `group:"externalHttpHandlers"`
 	ExternalHTTPHandlerFunctions []HTTPHandlerFuncPatternPair             `group:"externalHttpHandlerFunctions"`
 	ExternalHTTPInterceptors     []serverInt.GRPCGatewayInterceptor       `group:"externalHttpInterceptors"`
+	ExternalCORSInterceptor      serverInt.GRPCGatewayInterceptor         `name:"externalCorsInterceptor" optional:"true"`
 	// Internal REST
 	InternalHTTPHandlers         []HTTPHandlerPatternPair           `group:"internalHttpHandlers"`
 	InternalHTTPHandlerFunctions []HTTPHandlerFuncPatternPair       `group:"internalHttpHandlerFunctions"`
@@ -88,7 +126,16 @@ func HTTPServerBuilder(deps httpServerDeps) serverInt.GRPCWebServiceBuilder {
 	host := deps.Config.Get(confkeys.Host).String()
 	// GRPC port
 	if grpcPort := deps.Config.Get(confkeys.ExternalGRPCPort); grpcPort.IsSet() {
-		builder = builder.ListenOn(fmt.Sprintf("%s:%d", host, grpcPort.Int()))
+		addr := fmt.Sprintf("%s:%d", host, grpcPort.Int())
+		if listener := deps.listen(inherit.GRPCListenerName, addr); listener != nil {
+			builder = builder.SetCustomListener(listener)
+		} else {
+			builder = builder.ListenOn(addr)
+		}
+	}
+	// GRPC server options from configuration
+	if options := deps.grpcServerOptions(); len(options) > 0 {
+		builder = builder.AddGRPCServerOptions(options...)
 	}
 	// GRPC unary server interceptors
 	if len(deps.UnaryInterceptors) > 0 {
@@ -101,7 +148,8 @@ func HTTPServerBuilder(deps httpServerDeps) serverInt.GRPCWebServiceBuilder {
 		builder = builder.AddGRPCServerOptions(interceptorsOption)
 	}
 	builder = deps.buildExternalAPI(builder)
-	return deps.buildInternalAPI(builder)
+	builder = deps.buildInternalAPI(builder)
+	return deps.buildShutdown(builder)
 }
 
 func (deps httpServerDeps) buildExternalAPI(builder serverInt.GRPCWebServiceBuilder) serverInt.GRPCWebServiceBuilder {
@@ -111,15 +159,17 @@ func (deps httpServerDeps) buildExternalAPI(builder serverInt.GRPCWebServiceBuil
 	// add GRPC Gateway on top and expose on external REST Port
 	host := deps.Config.Get(confkeys.Host).String()
 	externalRESTPort := deps.Config.Get(confkeys.ExternalRESTPort)
-	if externalRESTPort.IsSet() && (len(deps.ExternalHTTPHandlerFunctions) > 0 || len(deps.ExternalHTTPHandlers) > 0 || len(deps.GRPCGatewayGeneratedHandlers) > 0) {
-		restBuilder := builder.AddRESTServerConfiguration().
-			ListenOn(fmt.Sprintf("%s:%d", host, externalRESTPort.Int()))
+	grpcWebOptions := deps.grpcWebOptions()
+	if externalRESTPort.IsSet() && (len(deps.ExternalHTTPHandlerFunctions) > 0 || len(deps.ExternalHTTPHandlers) > 0 || len(deps.GRPCGatewayGeneratedHandlers) > 0 || grpcWebOptions != nil) {
+		restBuilder := deps.configureREST(builder.AddRESTServerConfiguration(), inherit.ExternalRESTListenerName,
+			fmt.Sprintf("%s:%d", host, externalRESTPort.Int()))
 
+		// external interceptors wrap only the gateway, CORS also covers external handlers and gRPC-Web/Connect calls
 		for _, handlerPair := range deps.ExternalHTTPHandlers {
-			restBuilder = restBuilder.AddHandler(handlerPair.Pattern, handlerPair.Handler)
+			restBuilder = restBuilder.AddHandler(handlerPair.Pattern, deps.corsIntercept(handlerPair.Handler))
 		}
 		for _, handlerFuncPair := range deps.ExternalHTTPHandlerFunctions {
-			restBuilder = restBuilder.AddHandlerFunc(handlerFuncPair.Pattern, handlerFuncPair.HandlerFunc)
+			restBuilder = restBuilder.AddHandlerFunc(handlerFuncPair.Pattern, deps.corsIntercept(handlerFuncPair.HandlerFunc).ServeHTTP)
 		}
 		if len(deps.ExternalHTTPInterceptors) > 0 {
 			restBuilder = restBuilder.AddGRPCGatewayInterceptors(deps.ExternalHTTPInterceptors...)
@@ -128,22 +178,64 @@ func (deps httpServerDeps) buildExternalAPI(builder serverInt.GRPCWebServiceBuil
 			restBuilder = restBuilder.AddGRPCGatewayOptions(deps.GRPCGatewayMuxOptions...).
 				RegisterGRPCGatewayHandlers(deps.GRPCGatewayGeneratedHandlers...)
 		}
+		if grpcWebOptions != nil { // browsers call gRPC APIs on the external REST port
+			restBuilder = restBuilder.EnableGRPCWeb(*grpcWebOptions)
+		}
 		builder = restBuilder.BuildRESTPart()
 
 	}
 	return builder
 }
 
+// corsIntercept wraps handler with the external CORS interceptor if it's provided
+func (deps httpServerDeps) corsIntercept(handler http.Handler) http.Handler {
+	if deps.ExternalCORSInterceptor == nil {
+		return handler
+	}
+	return deps.ExternalCORSInterceptor(handler)
+}
+
 func (deps httpServerDeps) buildInternalAPI(builder serverInt.GRPCWebServiceBuilder) serverInt.GRPCWebServiceBuilder {
-	builder = builder.RegisterGRPCAPIs(health.RegisterInternalHealthService) // add internal GRPC health endpoint
-	// Internal
+	registry := deps.HealthRegistry
+	if registry == nil { // without checks, but still reports the service as not ready once it's stopping
+		registry = healthImpl.NewRegistry()
+	}
+	// add internal GRPC health endpoint
+	internalAPIs := []serverInt.GRPCServerAPI{health.RegisterInternalHealthServiceWithRegistry(registry)}
+	// add standard grpc.health.v1 endpoint
+	standardHealth := health.NewStandardHealth(registry, health.DefaultStandardHealthInterval)
+	internalAPIs = append(internalAPIs, standardHealth.Register)
+	// every health surface (REST probes, mortar.health.v1 and grpc.health.v1) fails readiness as soon as the service is stopping
+	builder = builder.AddStopHooks(func(context.Context) { registry.Shutdown() }, standardHealth.Shutdown)
+	deps.LifeCycle.Append(fx.Hook{
+		OnStart: standardHealth.Start,
+		OnStop: func(ctx context.Context) error {
+			standardHealth.Shutdown(ctx) // in case the service was never stopped
+			return nil
+		},
+	})
 	host := deps.Config.Get(confkeys.Host).String()
+	// Internal GRPC
+	servers := new(grpcServers)
+	builder = builder.RegisterGRPCAPIs(servers.add)
+	if internalGRPCPort := deps.Config.Get(confkeys.InternalGRPCPort); internalGRPCPort.IsSet() {
+		internalAPIs = append(internalAPIs, deps.adminAPIs(true, servers)...)
+		builder = deps.buildInternalGRPC(builder, fmt.Sprintf("%s:%d", host, internalGRPCPort.Int()), append(internalAPIs, servers.add))
+		// clients and load balancers check health of the external port as well
+		builder = builder.RegisterGRPCAPIs(standardHealth.Register)
+	} else {
+		if len(deps.InternalGRPCServerAPIs) > 0 {
+			deps.Logger.Warn(context.Background(), "Internal gRPC APIs are not registered, since %s is not set", confkeys.InternalGRPCPort)
+		}
+		internalAPIs = append(internalAPIs, deps.adminAPIs(false, servers)...)
+		builder = builder.RegisterGRPCAPIs(internalAPIs...)
+	}
+	// Internal REST
 	internalPort := deps.Config.Get(confkeys.InternalRESTPort)
 	includeInternalREST := internalPort.IsSet() && (len(deps.InternalHTTPHandlerFunctions) > 0 || len(deps.InternalHTTPHandlers) > 0)
 	if includeInternalREST {
-		restBuilder := builder.
-			AddRESTServerConfiguration().
-			ListenOn(fmt.Sprintf("%s:%d", host, internalPort.Int()))
+		restBuilder := deps.configureREST(builder.AddRESTServerConfiguration(), inherit.InternalRESTListenerName,
+			fmt.Sprintf("%s:%d", host, internalPort.Int()))
 		for _, handlerPair := range deps.InternalHTTPHandlers {
 			restBuilder = restBuilder.AddHandler(handlerPair.Pattern, handlerPair.Handler)
 		}
@@ -153,12 +245,79 @@ func (deps httpServerDeps) buildInternalAPI(builder serverInt.GRPCWebServiceBuil
 		if len(deps.InternalHTTPInterceptors) > 0 {
 			restBuilder = restBuilder.AddGRPCGatewayInterceptors(deps.InternalHTTPInterceptors...)
 		}
-		restBuilder = restBuilder.RegisterGRPCGatewayHandlers(health.RegisterInternalGRPCGatewayHandler) // Health
+		restBuilder = restBuilder.UseInternalGRPCServer().
+			RegisterGRPCGatewayHandlers(health.RegisterInternalGRPCGatewayHandler) // Health
 		builder = restBuilder.BuildRESTPart()
 	}
 	return builder
 }
 
+func (deps httpServerDeps) buildInternalGRPC(builder serverInt.GRPCWebServiceBuilder, addr string, apis []serverInt.GRPCServerAPI) serverInt.GRPCWebServiceBuilder {
+	internalBuilder := builder.AddInternalGRPCServerConfiguration()
+	if listener := deps.listen(inherit.InternalGRPCListenerName, addr); listener != nil {
+		internalBuilder = internalBuilder.SetCustomListener(listener)
+	} else {
+		internalBuilder = internalBuilder.ListenOn(addr)
+	}
+	if options := deps.grpcServerOptions(); len(options) > 0 {
+		internalBuilder = internalBuilder.AddGRPCServerOptions(options...)
+	}
+	// Internal GRPC server interceptors, interceptors of the external server are not applied
+	if len(deps.InternalUnaryInterceptors) > 0 {
+		internalBuilder = internalBuilder.AddGRPCServerOptions(grpc.ChainUnaryInterceptor(deps.InternalUnaryInterceptors...))
+	}
+	if len(deps.InternalStreamInterceptors) > 0 {
+		internalBuilder = internalBuilder.AddGRPCServerOptions(grpc.ChainStreamInterceptor(deps.InternalStreamInterceptors...))
+	}
+	return internalBuilder.
+		RegisterGRPCAPIs(deps.InternalGRPCServerAPIs...).
+		RegisterGRPCAPIs(apis...).
+		BuildInternalGRPCPart()
+}
+
+func (deps httpServerDeps) configureREST(restBuilder serverInt.RESTBuilder, name, addr string) serverInt.RESTBuilder {
+	if server := deps.restServer(); server != nil {
+		restBuilder = restBuilder.SetCustomServer(server)
+	}
+	if listener := deps.listen(name, addr); listener != nil {
+		return restBuilder.SetCustomListener(listener)
+	}
+	return restBuilder.ListenOn(addr)
+}
+
+// listen returns an inherited or a new listener that can be handed off, nil if Listeners are not provided
+func (deps httpServerDeps) listen(name, addr string) net.Listener {
+	if deps.Listeners == nil {
+		return nil
+	}
+	listener, err := deps.Listeners.Listen(name, addr)
+	if err != nil { // Build will fail with the same error
+		deps.Logger.WithError(err).Warn(context.Background(), "Failed to listen on %s", addr)
+		return nil
+	}
+	return listener
+}
+
+func (deps httpServerDeps) buildShutdown(builder serverInt.GRPCWebServiceBuilder) serverInt.GRPCWebServiceBuilder {
+	builder = builder.SetShutdownPolicy(serverInt.ShutdownPolicy{
+		DrainDelay:  deps.Config.Get(confkeys.ShutdownDrainDelay).Duration(),
+		GracePeriod: deps.Config.Get(confkeys.ShutdownGracePeriod).Duration(),
+	})
+	if deps.Metrics != nil {
+		builder = builder.AddShutdownObservers(deps.shutdownMetrics)
+	}
+	return builder
+}
+
+func (deps httpServerDeps) shutdownMetrics(_ context.Context, report serverInt.ShutdownPhaseReport) {
+	phase := string(report.Phase)
+	deps.Metrics.Timer(ShutdownPhaseTimer, "Duration of web service shutdown phases").
+		WithTags(monitor.Tags{"phase": phase}).Record(report.Duration)
+	inFlight := deps.Metrics.Gauge(ShutdownInFlightGauge, "In-flight calls at the end of web service shutdown phases")
+	inFlight.WithTags(monitor.Tags{"phase": phase, "type": string(serverInt.GRPCServer)}).Set(float64(report.GRPCInFlight))
+	inFlight.WithTags(monitor.Tags{"phase": phase, "type": string(serverInt.RESTServer)}).Set(float64(report.RESTInFlight))
+}
+
 func (deps httpServerDeps) panicHandler(r interface{}) error {
 	if deps.Metrics != nil {
 		deps.Metrics.Counter(PanicHandlerCounter, "Count gRPC panic recoveries").Inc()
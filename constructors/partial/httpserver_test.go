@@ -1,8 +1,10 @@
 package partial
 
 import (
+	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/go-masonry/mortar/interfaces/cfg"
 	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
@@ -10,7 +12,11 @@ import (
 	serverInt "github.com/go-masonry/mortar/interfaces/http/server"
 	"github.com/go-masonry/mortar/interfaces/log"
 	mock_log "github.com/go-masonry/mortar/interfaces/log/mock"
+	"github.com/go-masonry/mortar/logger"
+	"github.com/go-masonry/mortar/logger/logtest"
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/fx"
 	"go.uber.org/fx/fxtest"
@@ -55,6 +61,85 @@ func (s *partialSuite) TestExternalHTTPGroups() {
 	})
 }
 
+func TestExternalHandlersOnlyWrappedByCORS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cfgMock := mock_cfg.NewMockConfig(ctrl)
+	configured := map[string]interface{}{
+		confkeys.Host:             "localhost",
+		confkeys.ExternalGRPCPort: 0,
+		confkeys.ExternalRESTPort: 0,
+	}
+	cfgMock.EXPECT().Get(gomock.Any()).DoAndReturn(func(key string) cfg.Value {
+		value := mock_cfg.NewMockValue(ctrl)
+		raw, set := configured[key]
+		str, _ := raw.(string)
+		number, _ := raw.(int)
+		value.EXPECT().IsSet().Return(set).AnyTimes()
+		value.EXPECT().String().Return(str).AnyTimes()
+		value.EXPECT().Int().Return(number).AnyTimes()
+		value.EXPECT().Duration().Return(time.Duration(0)).AnyTimes()
+		value.EXPECT().Bool().Return(false).AnyTimes()
+		value.EXPECT().StringSlice().Return(nil).AnyTimes()
+		return value
+	}).AnyTimes()
+	headerInterceptor := func(header string) serverInt.GRPCGatewayInterceptor {
+		return func(handler http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(header, "true")
+				handler.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	var builder serverInt.GRPCWebServiceBuilder
+	app := fxtest.New(t,
+		fx.Supply(fx.Annotate(cfgMock, fx.As(new(cfg.Config)))),
+		fx.Provide(
+			func() log.Logger {
+				return logger.CreateMortarLogger(logtest.New().Builder())
+			},
+			fx.Annotated{
+				Group: FxGroupExternalHTTPHandlers,
+				Target: func() HTTPHandlerPatternPair {
+					return HTTPHandlerPatternPair{Pattern: "/external", Handler: http.NotFoundHandler()}
+				},
+			},
+			fx.Annotated{
+				Group: FxGroupExternalHTTPInterceptors,
+				Target: func() serverInt.GRPCGatewayInterceptor {
+					return headerInterceptor("X-Gateway")
+				},
+			},
+			fx.Annotated{
+				Name: FxNameExternalCORSInterceptor,
+				Target: func() serverInt.GRPCGatewayInterceptor {
+					return headerInterceptor("X-Cors")
+				},
+			},
+			HTTPServerBuilder,
+		),
+		fx.Populate(&builder),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+	service, err := builder.Build()
+	require.NoError(t, err)
+	go service.Run(context.Background())
+	defer service.Stop(context.Background())
+	var restAddress string
+	for _, info := range service.Ports() {
+		if info.Type == serverInt.RESTServer {
+			restAddress = info.Address
+		}
+	}
+	resp, err := http.Get("http://" + restAddress + "/external")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get("X-Cors"), "CORS wraps external handlers")
+	assert.Empty(t, resp.Header.Get("X-Gateway"), "external interceptors wrap only the gateway")
+}
+
 func (s *partialSuite) SetupTest() {
 	// This one runs before `BeforeTest`
 	s.ctrl = gomock.NewController(s.T())
@@ -98,6 +183,24 @@ func (s *partialSuite) SetupTest() {
 		value.EXPECT().IsSet().Return(true)
 		return value
 	})
+	// shutdown
+	s.cfgMock.EXPECT().Get(confkeys.ShutdownDrainDelay).DoAndReturn(func(key string) cfg.Value {
+		value := mock_cfg.NewMockValue(s.ctrl)
+		value.EXPECT().Duration().Return(time.Second)
+		return value
+	})
+	s.cfgMock.EXPECT().Get(confkeys.ShutdownGracePeriod).DoAndReturn(func(key string) cfg.Value {
+		value := mock_cfg.NewMockValue(s.ctrl)
+		value.EXPECT().Duration().Return(10 * time.Second)
+		return value
+	})
+	// server tuning, declared last since it matches every key
+	s.cfgMock.EXPECT().Get(gomock.Any()).DoAndReturn(func(key string) cfg.Value {
+		value := mock_cfg.NewMockValue(s.ctrl)
+		value.EXPECT().IsSet().Return(false).AnyTimes()
+		value.EXPECT().Bool().Return(false).AnyTimes()
+		return value
+	}).AnyTimes()
 }
 
 func (s *partialSuite) setupGroups() fx.Option {
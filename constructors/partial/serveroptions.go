@@ -0,0 +1,95 @@
+package partial
+
+import (
+	"net/http"
+	"time"
+
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	serverInt "github.com/go-masonry/mortar/interfaces/http/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// grpcServerOptions creates gRPC server options from configuration, only keys that are set are applied
+func (deps httpServerDeps) grpcServerOptions() (options []grpc.ServerOption) {
+	var params keepalive.ServerParameters
+	paramsSet := deps.setDuration(confkeys.GRPCKeepaliveTime, &params.Time)
+	paramsSet = deps.setDuration(confkeys.GRPCKeepaliveTimeout, &params.Timeout) || paramsSet
+	paramsSet = deps.setDuration(confkeys.GRPCKeepaliveMaxConnectionIdle, &params.MaxConnectionIdle) || paramsSet
+	paramsSet = deps.setDuration(confkeys.GRPCKeepaliveMaxConnectionAge, &params.MaxConnectionAge) || paramsSet
+	paramsSet = deps.setDuration(confkeys.GRPCKeepaliveMaxConnectionAgeGrace, &params.MaxConnectionAgeGrace) || paramsSet
+	if paramsSet {
+		options = append(options, grpc.KeepaliveParams(params))
+	}
+	var policy keepalive.EnforcementPolicy
+	policySet := deps.setDuration(confkeys.GRPCKeepaliveEnforcementMinTime, &policy.MinTime)
+	if value := deps.Config.Get(confkeys.GRPCKeepaliveEnforcementPermitWithoutStream); value.IsSet() {
+		policy.PermitWithoutStream = value.Bool()
+		policySet = true
+	}
+	if policySet {
+		options = append(options, grpc.KeepaliveEnforcementPolicy(policy))
+	}
+	if value := deps.Config.Get(confkeys.GRPCMaxSendMsgSize); value.IsSet() {
+		options = append(options, grpc.MaxSendMsgSize(value.Int()))
+	}
+	if value := deps.Config.Get(confkeys.GRPCMaxRecvMsgSize); value.IsSet() {
+		options = append(options, grpc.MaxRecvMsgSize(value.Int()))
+	}
+	if value := deps.Config.Get(confkeys.GRPCMaxConcurrentStreams); value.IsSet() {
+		options = append(options, grpc.MaxConcurrentStreams(uint32(value.Int())))
+	}
+	if value := deps.Config.Get(confkeys.GRPCConnectionTimeout); value.IsSet() {
+		options = append(options, grpc.ConnectionTimeout(value.Duration()))
+	}
+	if value := deps.Config.Get(confkeys.GRPCInitialWindowSize); value.IsSet() {
+		options = append(options, grpc.InitialWindowSize(int32(value.Int())))
+	}
+	if value := deps.Config.Get(confkeys.GRPCInitialConnWindowSize); value.IsSet() {
+		options = append(options, grpc.InitialConnWindowSize(int32(value.Int())))
+	}
+	return
+}
+
+// restServer creates an http.Server with timeouts, limits and error log, nil if none of them is set
+func (deps httpServerDeps) restServer() *http.Server {
+	server := &http.Server{
+		ErrorLog: deps.RESTServerErrorLog,
+	}
+	set := deps.setDuration(confkeys.RESTReadTimeout, &server.ReadTimeout)
+	set = deps.setDuration(confkeys.RESTReadHeaderTimeout, &server.ReadHeaderTimeout) || set
+	set = deps.setDuration(confkeys.RESTWriteTimeout, &server.WriteTimeout) || set
+	set = deps.setDuration(confkeys.RESTIdleTimeout, &server.IdleTimeout) || set
+	if value := deps.Config.Get(confkeys.RESTMaxHeaderBytes); value.IsSet() {
+		server.MaxHeaderBytes = value.Int()
+		set = true
+	}
+	if !set && server.ErrorLog == nil {
+		return nil
+	}
+	return server
+}
+
+// grpcWebOptions creates gRPC-Web and Connect options of the external REST server, nil if both are disabled
+func (deps httpServerDeps) grpcWebOptions() *serverInt.GRPCWebOptions {
+	options := &serverInt.GRPCWebOptions{
+		GRPCWeb: deps.Config.Get(confkeys.ExternalRESTGRPCWeb).Bool(),
+		Connect: deps.Config.Get(confkeys.ExternalRESTConnect).Bool(),
+	}
+	if !options.GRPCWeb && !options.Connect {
+		return nil
+	}
+	if value := deps.Config.Get(confkeys.GRPCMaxRecvMsgSize); value.IsSet() {
+		options.MaxRecvMsgSize = value.Int()
+	}
+	options.CORS = deps.ExternalCORSInterceptor
+	return options
+}
+
+func (deps httpServerDeps) setDuration(key string, target *time.Duration) bool {
+	value := deps.Config.Get(key)
+	if value.IsSet() {
+		*target = value.Duration()
+	}
+	return value.IsSet()
+}
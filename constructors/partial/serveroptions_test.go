@@ -0,0 +1,83 @@
+package partial
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	mock_cfg "github.com/go-masonry/mortar/interfaces/cfg/mock"
+	serverInt "github.com/go-masonry/mortar/interfaces/http/server"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerTuningFromConfig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cfgMock := mock_cfg.NewMockConfig(ctrl)
+	values := map[string]interface{}{
+		confkeys.GRPCKeepaliveTime:                           time.Minute,
+		confkeys.GRPCKeepaliveMaxConnectionAge:               time.Hour,
+		confkeys.GRPCKeepaliveEnforcementMinTime:             time.Second,
+		confkeys.GRPCMaxRecvMsgSize:                          1 << 20,
+		confkeys.GRPCMaxConcurrentStreams:                    10,
+		confkeys.RESTReadHeaderTimeout:                       5 * time.Second,
+		confkeys.RESTIdleTimeout:                             time.Minute,
+		confkeys.RESTMaxHeaderBytes:                          4096,
+		confkeys.GRPCKeepaliveEnforcementPermitWithoutStream: true,
+		confkeys.ExternalRESTGRPCWeb:                         false,
+		confkeys.ExternalRESTConnect:                         true,
+	}
+	cfgMock.EXPECT().Get(gomock.Any()).DoAndReturn(func(key string) cfg.Value {
+		value := mock_cfg.NewMockValue(ctrl)
+		configured, set := values[key]
+		value.EXPECT().IsSet().Return(set).AnyTimes()
+		switch v := configured.(type) {
+		case time.Duration:
+			value.EXPECT().Duration().Return(v)
+		case int:
+			value.EXPECT().Int().Return(v)
+		case bool:
+			value.EXPECT().Bool().Return(v)
+		case []string:
+			value.EXPECT().StringSlice().Return(v)
+		}
+		return value
+	}).AnyTimes()
+	deps := httpServerDeps{Config: cfgMock}
+
+	// keepalive params, enforcement policy, max recv message size and max concurrent streams
+	assert.Len(t, deps.grpcServerOptions(), 4)
+	server := deps.restServer()
+	require.NotNil(t, server)
+	assert.Zero(t, server.ReadTimeout)
+	assert.Equal(t, 5*time.Second, server.ReadHeaderTimeout)
+	assert.Zero(t, server.WriteTimeout)
+	assert.Equal(t, time.Minute, server.IdleTimeout)
+	assert.Equal(t, 4096, server.MaxHeaderBytes)
+	assert.Equal(t, &serverInt.GRPCWebOptions{Connect: true, MaxRecvMsgSize: 1 << 20}, deps.grpcWebOptions())
+}
+
+func TestServerTuningNotConfigured(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cfgMock := mock_cfg.NewMockConfig(ctrl)
+	cfgMock.EXPECT().Get(gomock.Any()).DoAndReturn(func(key string) cfg.Value {
+		value := mock_cfg.NewMockValue(ctrl)
+		value.EXPECT().IsSet().Return(false).AnyTimes()
+		value.EXPECT().Bool().Return(false).AnyTimes()
+		return value
+	}).AnyTimes()
+	deps := httpServerDeps{Config: cfgMock}
+	assert.Empty(t, deps.grpcServerOptions())
+	assert.Nil(t, deps.restServer())
+	assert.Nil(t, deps.grpcWebOptions())
+
+	deps.RESTServerErrorLog = log.New(io.Discard, "", 0)
+	server := deps.restServer()
+	require.NotNil(t, server)
+	assert.Equal(t, deps.RESTServerErrorLog, server.ErrorLog)
+	assert.Zero(t, server.ReadHeaderTimeout)
+}
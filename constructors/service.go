@@ -5,10 +5,13 @@ import (
 	"fmt"
 
 	"github.com/go-masonry/mortar/http/server/health"
+	"github.com/go-masonry/mortar/http/server/inherit"
 	"github.com/go-masonry/mortar/interfaces/http/server"
 	"github.com/go-masonry/mortar/interfaces/log"
 	"go.uber.org/fx"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 )
 
 type webServiceDependencies struct {
@@ -17,6 +20,8 @@ type webServiceDependencies struct {
 	LifeCycle         fx.Lifecycle
 	Logger            log.Logger
 	WebServiceBuilder server.GRPCWebServiceBuilder
+	// Listeners tell the process that handed them over that this one is up
+	Listeners *inherit.Listeners `optional:"true"`
 }
 
 // Service should be invoked by FX, it will build the entire dependencies graph and add lifecycle hooks
@@ -28,7 +33,15 @@ func Service(deps webServiceDependencies) (server.WebService, error) {
 	deps.LifeCycle.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			go webService.Run(ctx) // this should exit only when service was shutdown
-			return deps.pingService(ctx, webService)
+			if err := deps.pingService(ctx, webService); err != nil {
+				return err
+			}
+			if deps.Listeners != nil {
+				if err := deps.Listeners.Ready(); err != nil {
+					deps.Logger.WithError(err).Warn(ctx, "Failed to report the service is up to the previous process")
+				}
+			}
+			return nil
 		},
 		OnStop: func(ctx context.Context) error {
 			return webService.Stop(ctx)
@@ -46,6 +59,7 @@ func (deps webServiceDependencies) pingService(ctx context.Context, service serv
 			defer conn.Close()
 			healthClient := health.NewHealthClient(conn)
 			_, err = healthClient.Check(ctx, &health.HealthCheckRequest{})
+			err = deps.ignoreNotReady(ctx, err)
 		}
 	}
 	if err == nil {
@@ -56,11 +70,27 @@ func (deps webServiceDependencies) pingService(ctx context.Context, service serv
 	}
 	return
 }
-func (deps webServiceDependencies) getGRPCAddress(ports []server.ListenInfo) string {
+
+// ignoreNotReady the service is up even if some of its readiness checks fail
+func (deps webServiceDependencies) ignoreNotReady(ctx context.Context, err error) error {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetReason() == health.NotReadyReason {
+			deps.Logger.WithError(err).Warn(ctx, "Service is up, but not ready %v", info.GetMetadata())
+			return nil
+		}
+	}
+	return err
+}
+
+// getGRPCAddress prefers the internal gRPC server, since health service is registered there when it exists
+func (deps webServiceDependencies) getGRPCAddress(ports []server.ListenInfo) (address string) {
 	for _, info := range ports {
-		if info.Type == server.GRPCServer {
+		switch info.Type {
+		case server.InternalGRPCServer:
 			return info.Address
+		case server.GRPCServer:
+			address = info.Address
 		}
 	}
-	return ""
+	return
 }
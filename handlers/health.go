@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-masonry/mortar/constructors/partial"
+	"github.com/go-masonry/mortar/interfaces/health"
+	"github.com/go-masonry/mortar/interfaces/log"
+	"go.uber.org/fx"
+)
+
+const healthHandlerPrefix = "/health"
+
+type healthHandlerDeps struct {
+	fx.In
+
+	Logger   log.Logger
+	Registry health.Registry
+}
+
+// HealthHandlers health probe handlers, respond with 503 if a critical check fails and report every check.
+// Readiness fails once the service is shutting down, see health.Registry Shutdown
+//
+//	GET /health/live
+//	GET /health/ready
+//	GET /health/startup
+func HealthHandlers(deps healthHandlerDeps) []partial.HTTPHandlerPatternPair {
+	return []partial.HTTPHandlerPatternPair{
+		{Pattern: healthHandlerPrefix + "/live", Handler: deps.Probe(health.Liveness)},
+		{Pattern: healthHandlerPrefix + "/ready", Handler: deps.Probe(health.Readiness)},
+		{Pattern: healthHandlerPrefix + "/startup", Handler: deps.Probe(health.Startup)},
+	}
+}
+
+func (h *healthHandlerDeps) Probe(probe health.Probe) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		report := h.Registry.Run(req.Context(), probe)
+		w.Header().Set("Content-type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if report.Status == health.Fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			h.Logger.WithError(err).Warn(req.Context(), "failed to serve %s health probe", probe)
+		}
+	}
+}
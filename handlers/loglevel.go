@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-masonry/mortar/constructors/partial"
+	"github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/logger"
+	"go.uber.org/fx"
+)
+
+type logLevelHandlerDeps struct {
+	fx.In
+
+	Logger log.Logger
+}
+
+type logLevelRequest struct {
+	Level string `json:"level"`
+	// TTL is a duration string such as "10m", level will revert to its previous value once it expires
+	TTL string `json:"ttl,omitempty"`
+	// ChangedBy is reported by the client and recorded as LevelChange.ClaimedBy, the change itself is always attributed to the client address
+	ChangedBy string `json:"changed_by,omitempty"`
+}
+
+type logLevelResponse struct {
+	Level   log.Level            `json:"level"`
+	Changes []logger.LevelChange `json:"changes"`
+}
+
+// LogLevelHandlers log level handlers, allows to get and change log level at runtime
+//
+//	GET /self/loglevel
+//	PUT /self/loglevel {"level":"debug","ttl":"10m","changed_by":"john"}
+func LogLevelHandlers(deps logLevelHandlerDeps) []partial.HTTPHandlerPatternPair {
+	return []partial.HTTPHandlerPatternPair{
+		{Pattern: selfHandlerPrefix + "/loglevel", Handler: deps.LogLevel()},
+	}
+}
+
+func (l *logLevelHandlerDeps) LogLevel() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		atomicLevel, ok := l.Logger.Configuration().(logger.AtomicLevel)
+		if !ok {
+			http.Error(w, "logger doesn't support runtime log level changes", http.StatusNotImplemented)
+			return
+		}
+		switch req.Method {
+		case http.MethodGet:
+			l.writeJSON(w, logLevelResponse{Level: atomicLevel.Level(), Changes: atomicLevel.Changes()})
+		case http.MethodPut:
+			level, ttl, claimedBy, err := parseLogLevelRequest(req)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			change := atomicLevel.SetLevel(level, ttl, req.RemoteAddr, claimedBy)
+			l.Logger.Warn(req.Context(), "log level changed from %s to %s by %s (claimed %q), ttl %s", change.From, change.To, change.ChangedBy, change.ClaimedBy, ttl)
+			l.writeJSON(w, change)
+		default:
+			w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodPut}, ", "))
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func (l *logLevelHandlerDeps) writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		l.Logger.WithError(err).Warn(context.TODO(), "failed to serve log level")
+	}
+}
+
+func parseLogLevelRequest(req *http.Request) (level log.Level, ttl time.Duration, claimedBy string, err error) {
+	var body logLevelRequest
+	if err = json.NewDecoder(req.Body).Decode(&body); err != nil {
+		return level, ttl, claimedBy, fmt.Errorf("invalid request body, %w", err)
+	}
+	level = log.ParseLevel(body.Level)
+	if level.String() != strings.ToLower(body.Level) { // ParseLevel defaults to trace
+		return level, ttl, claimedBy, fmt.Errorf("unknown log level [%s]", body.Level)
+	}
+	if len(body.TTL) > 0 {
+		if ttl, err = time.ParseDuration(body.TTL); err != nil || ttl < 0 {
+			return level, ttl, claimedBy, fmt.Errorf("invalid ttl [%s]", body.TTL)
+		}
+	}
+	return level, ttl, body.ChangedBy, nil
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	logInt "github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/logger"
+	"github.com/go-masonry/mortar/logger/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLevelChangedByIsNotTrusted(t *testing.T) {
+	deps := logLevelHandlerDeps{Logger: logger.Builder().SetLevel(logInt.InfoLevel).Build(logtest.New().Builder())}
+	req := httptest.NewRequest(http.MethodPut, "/self/loglevel", strings.NewReader(`{"level":"debug","changed_by":"admin"}`))
+	req.RemoteAddr = "10.0.0.1:5555"
+	recorder := httptest.NewRecorder()
+	deps.LogLevel()(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var change map[string]interface{}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&change))
+	assert.Equal(t, "debug", change["to"])
+	assert.Equal(t, "10.0.0.1:5555", change["changed_by"])
+	assert.Equal(t, "admin", change["claimed_by"])
+}
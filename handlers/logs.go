@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-masonry/mortar/constructors/partial"
+	"github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/logger"
+	"go.uber.org/fx"
+)
+
+const ndjsonFormat = "ndjson"
+
+type recentLogsHandlerDeps struct {
+	fx.In
+
+	Logger log.Logger
+}
+
+// RecentLogsHandlers recent logs handlers, allows to query log entries kept in memory by the mortar logger.
+//
+//	GET /self/logs?level=warn&from=2006-01-02T15:04:05Z&to=...&field=logger:middleware.grpc.server&contains=timeout&limit=100&format=ndjson
+//
+// All query parameters are optional, `field` can be repeated and `format` is either json (default) or ndjson.
+func RecentLogsHandlers(deps recentLogsHandlerDeps) []partial.HTTPHandlerPatternPair {
+	return []partial.HTTPHandlerPatternPair{
+		{Pattern: selfHandlerPrefix + "/logs", Handler: deps.RecentLogs()},
+	}
+}
+
+func (r *recentLogsHandlerDeps) RecentLogs() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		recorded, ok := r.Logger.(logger.Recorded)
+		if !ok || recorded.RecentLogs() == nil {
+			http.Error(w, "logger doesn't keep recent log entries", http.StatusNotImplemented)
+			return
+		}
+		if req.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		filter, err := parseRecentFilter(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		entries := recorded.RecentLogs().Query(filter)
+		if req.URL.Query().Get("format") == ndjsonFormat {
+			w.Header().Set("Content-type", "application/x-ndjson; charset=utf-8")
+			encoder := json.NewEncoder(w)
+			for _, entry := range entries {
+				if err = encoder.Encode(entry); err != nil {
+					r.Logger.WithError(err).Warn(req.Context(), "failed to serve recent logs")
+					return
+				}
+			}
+			return
+		}
+		w.Header().Set("Content-type", "application/json; charset=utf-8")
+		if err = json.NewEncoder(w).Encode(entries); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			r.Logger.WithError(err).Warn(req.Context(), "failed to serve recent logs")
+		}
+	}
+}
+
+func parseRecentFilter(req *http.Request) (filter logger.RecentFilter, err error) {
+	query := req.URL.Query()
+	if level := query.Get("level"); len(level) > 0 {
+		filter.Level = log.ParseLevel(level)
+		if filter.Level.String() != strings.ToLower(level) { // ParseLevel defaults to trace
+			return filter, fmt.Errorf("unknown log level [%s]", level)
+		}
+	}
+	if from := query.Get("from"); len(from) > 0 {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return filter, fmt.Errorf("invalid from [%s], expected RFC3339", from)
+		}
+	}
+	if to := query.Get("to"); len(to) > 0 {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return filter, fmt.Errorf("invalid to [%s], expected RFC3339", to)
+		}
+	}
+	if limit := query.Get("limit"); len(limit) > 0 {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 0 {
+			return filter, fmt.Errorf("invalid limit [%s]", limit)
+		}
+	}
+	for _, field := range query["field"] {
+		name, value, found := strings.Cut(field, ":")
+		if !found {
+			return filter, fmt.Errorf("invalid field [%s], expected name:value", field)
+		}
+		if filter.Fields == nil {
+			filter.Fields = make(map[string]string)
+		}
+		filter.Fields[name] = value
+	}
+	filter.Contains = query.Get("contains")
+	if format := query.Get("format"); len(format) > 0 && format != "json" && format != ndjsonFormat {
+		return filter, fmt.Errorf("unknown format [%s]", format)
+	}
+	return filter, nil
+}
@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-masonry/mortar/constructors/partial"
+	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	"github.com/go-masonry/mortar/interfaces/log"
+	"go.uber.org/fx"
+	"gopkg.in/yaml.v3"
+)
+
+// FxGroupOpenAPIDocuments defines group name
+const FxGroupOpenAPIDocuments = "openAPIDocuments"
+
+const (
+	defaultOpenAPISpecPath = "/openapi.json"
+	defaultOpenAPIUIPath   = "/docs/"
+	// openAPIUIPolicy makes sure the docs UI loads nothing but its own files and the OpenAPI document
+	openAPIUIPolicy = "default-src 'none'; script-src 'self'; style-src 'self'; img-src 'self' data:; connect-src 'self'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+)
+
+//go:embed openapiui
+var openAPIUI embed.FS
+
+var openAPIUIIndex = template.Must(template.ParseFS(openAPIUI, "openapiui/index.html"))
+
+// OpenAPIDocument is an OpenAPI v2 (swagger) or v3 document in JSON or YAML, usually the `*.swagger.json` generated next to `*.pb.gw.go`
+type OpenAPIDocument struct {
+	// Name of the document, usually its file name
+	Name string
+	// Content of the document
+	Content []byte
+	// Internal documents describe APIs of the internal REST port, otherwise of the external one
+	Internal bool
+}
+
+// OpenAPIDocumentsFromFS reads documents that match pattern, see fs.Glob. Usually fsys is an embed.FS
+//
+//	//go:embed api/*.swagger.json
+//	var apiDocuments embed.FS
+//
+//	handlers.OpenAPIDocumentsFromFS(apiDocuments, "api/*.swagger.json", false)
+func OpenAPIDocumentsFromFS(fsys fs.FS, pattern string, internal bool) (documents []OpenAPIDocument, err error) {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no OpenAPI documents match %s", pattern)
+	}
+	for _, name := range names {
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, OpenAPIDocument{Name: name, Content: content, Internal: internal})
+	}
+	return documents, nil
+}
+
+type openAPIHandlersDeps struct {
+	fx.In
+
+	Logger    log.Logger
+	Config    cfg.Config
+	Documents []OpenAPIDocument `group:"openAPIDocuments"`
+}
+
+// ExternalOpenAPIHandlers serves external documents merged into one, and the docs UI, on the external port
+//
+//	GET /openapi.json, see keys.OpenAPISpecPath
+//	GET /docs/, see keys.OpenAPIUIPath
+func ExternalOpenAPIHandlers(deps openAPIHandlersDeps) ([]partial.HTTPHandlerPatternPair, error) {
+	return deps.handlers(false)
+}
+
+// InternalOpenAPIHandlers serves internal documents merged into one, and the docs UI, on the internal port
+//
+//	GET /openapi.json, see keys.OpenAPISpecPath
+//	GET /docs/, see keys.OpenAPIUIPath
+func InternalOpenAPIHandlers(deps openAPIHandlersDeps) ([]partial.HTTPHandlerPatternPair, error) {
+	return deps.handlers(true)
+}
+
+func (o *openAPIHandlersDeps) handlers(internal bool) ([]partial.HTTPHandlerPatternPair, error) {
+	var documents []OpenAPIDocument
+	for _, document := range o.Documents {
+		if document.Internal == internal {
+			documents = append(documents, document)
+		}
+	}
+	if len(documents) == 0 {
+		return nil, nil // nothing to serve on this port
+	}
+	merged, conflicts, err := mergeOpenAPIDocuments(documents)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		o.Logger.WithField("conflicts", conflicts).Warn(context.Background(), "OpenAPI documents define different values for the same fields, the first document wins")
+	}
+	o.setTitle(merged)
+	spec, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	specPath := o.Config.Get(confkeys.OpenAPISpecPath).String()
+	if len(specPath) == 0 {
+		specPath = defaultOpenAPISpecPath
+	}
+	handlers := []partial.HTTPHandlerPatternPair{{Pattern: specPath, Handler: o.Spec(spec)}}
+	if uiEnabled := o.Config.Get(confkeys.OpenAPIUIEnabled); !uiEnabled.IsSet() || uiEnabled.Bool() {
+		uiPath := o.Config.Get(confkeys.OpenAPIUIPath).String()
+		if len(uiPath) == 0 {
+			uiPath = defaultOpenAPIUIPath
+		}
+		if !strings.HasSuffix(uiPath, "/") {
+			uiPath += "/"
+		}
+		ui, err := o.DocsUI(uiPath, specPath)
+		if err != nil {
+			return nil, err
+		}
+		handlers = append(handlers, partial.HTTPHandlerPatternPair{Pattern: uiPath, Handler: ui})
+	}
+	return handlers, nil
+}
+
+func (o *openAPIHandlersDeps) setTitle(document map[string]interface{}) {
+	title := o.Config.Get(confkeys.OpenAPITitle).String()
+	if len(title) == 0 {
+		title = o.Config.Get(confkeys.ApplicationName).String()
+	}
+	if len(title) > 0 {
+		info, ok := document["info"].(map[string]interface{})
+		if !ok {
+			info = make(map[string]interface{})
+			document["info"] = info
+		}
+		info["title"] = title
+	}
+}
+
+// Spec serves the merged OpenAPI document
+func (o *openAPIHandlersDeps) Spec(spec []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-cache")
+		if _, err := w.Write(spec); err != nil {
+			o.Logger.WithError(err).Debug(req.Context(), "failed to serve OpenAPI document")
+		}
+	}
+}
+
+// DocsUI serves the bundled docs UI, every file it uses is embedded
+func (o *openAPIHandlersDeps) DocsUI(uiPath, specPath string) (http.Handler, error) {
+	var index bytes.Buffer
+	if err := openAPIUIIndex.Execute(&index, struct{ SpecPath string }{specPath}); err != nil {
+		return nil, err
+	}
+	static, err := fs.Sub(openAPIUI, "openapiui/static")
+	if err != nil {
+		return nil, err
+	}
+	files := http.StripPrefix(uiPath+"static/", http.FileServer(http.FS(static)))
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Security-Policy", openAPIUIPolicy)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		switch {
+		case req.URL.Path == uiPath:
+			w.Header().Set("Content-type", "text/html; charset=utf-8")
+			w.Write(index.Bytes())
+		case strings.HasPrefix(req.URL.Path, uiPath+"static/"):
+			files.ServeHTTP(w, req)
+		default:
+			http.NotFound(w, req)
+		}
+	}), nil
+}
+
+// mergeOpenAPIDocuments merges documents of the same OpenAPI version into the first one, sorted by name.
+// Objects are merged recursively and arrays are joined, fields with different values keep the first value and are reported as conflicts
+func mergeOpenAPIDocuments(documents []OpenAPIDocument) (merged map[string]interface{}, conflicts []string, err error) {
+	sort.SliceStable(documents, func(i, j int) bool { return documents[i].Name < documents[j].Name })
+	var mergedVersion string
+	for _, document := range documents {
+		parsed, err := parseOpenAPIDocument(document)
+		if err != nil {
+			return nil, nil, err
+		}
+		version, err := openAPIMajorVersion(parsed)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", document.Name, err)
+		}
+		if merged == nil {
+			merged, mergedVersion = parsed, version
+			continue
+		}
+		if version != mergedVersion {
+			return nil, nil, fmt.Errorf("%s: OpenAPI %s document can't be merged with OpenAPI %s documents", document.Name, version, mergedVersion)
+		}
+		for key, value := range parsed {
+			switch key {
+			case "swagger", "openapi", "info": // document wide, first document wins
+				continue
+			}
+			merged[key] = mergeOpenAPIValues(merged[key], value, "/"+key, &conflicts)
+		}
+	}
+	sort.Strings(conflicts)
+	return merged, conflicts, nil
+}
+
+func parseOpenAPIDocument(document OpenAPIDocument) (map[string]interface{}, error) {
+	var parsed interface{}
+	if err := json.Unmarshal(document.Content, &parsed); err != nil {
+		if yamlErr := yaml.Unmarshal(document.Content, &parsed); yamlErr != nil {
+			return nil, fmt.Errorf("%s is neither JSON nor YAML, %v", document.Name, yamlErr)
+		}
+	}
+	object, ok := normalizeOpenAPIValue(parsed).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s is not an OpenAPI document", document.Name)
+	}
+	return object, nil
+}
+
+// normalizeOpenAPIValue YAML allows keys that are not strings, such as response codes
+func normalizeOpenAPIValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeOpenAPIValue(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			object[fmt.Sprint(key)] = normalizeOpenAPIValue(item)
+		}
+		return object
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeOpenAPIValue(item)
+		}
+		return v
+	}
+	return value
+}
+
+func openAPIMajorVersion(document map[string]interface{}) (string, error) {
+	if swagger, ok := document["swagger"].(string); ok && strings.HasPrefix(swagger, "2.") {
+		return "v2", nil
+	}
+	if openapi, ok := document["openapi"].(string); ok && strings.HasPrefix(openapi, "3.") {
+		return "v3", nil
+	}
+	return "", fmt.Errorf("unsupported OpenAPI version, expected `swagger: 2.0` or `openapi: 3.x`")
+}
+
+func mergeOpenAPIValues(existing, value interface{}, path string, conflicts *[]string) interface{} {
+	if existing == nil {
+		return value
+	}
+	switch current := existing.(type) {
+	case map[string]interface{}:
+		if object, ok := value.(map[string]interface{}); ok {
+			for key, item := range object {
+				current[key] = mergeOpenAPIValues(current[key], item, path+"/"+key, conflicts)
+			}
+			return current
+		}
+	case []interface{}:
+		if list, ok := value.([]interface{}); ok {
+			for _, item := range list {
+				if !containsOpenAPIValue(current, item, path == "/tags") {
+					current = append(current, item)
+				}
+			}
+			return current
+		}
+	}
+	if !reflect.DeepEqual(existing, value) {
+		*conflicts = append(*conflicts, path)
+	}
+	return existing
+}
+
+// containsOpenAPIValue tags are unique by name, everything else must be equal
+func containsOpenAPIValue(list []interface{}, value interface{}, byName bool) bool {
+	for _, item := range list {
+		if byName {
+			itemObject, itemOK := item.(map[string]interface{})
+			valueObject, valueOK := value.(map[string]interface{})
+			if itemOK && valueOK && itemObject["name"] == valueObject["name"] {
+				return true
+			}
+		}
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
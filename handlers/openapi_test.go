@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	mock_cfg "github.com/go-masonry/mortar/interfaces/cfg/mock"
+	logInt "github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/logger/logtest"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pingSwagger = `{
+  "swagger": "2.0",
+  "info": {"title": "ping.proto", "version": "1"},
+  "tags": [{"name": "Ping"}],
+  "paths": {"/v1/ping": {"get": {"operationId": "Ping_Ping", "tags": ["Ping"]}}},
+  "definitions": {"rpcStatus": {"type": "object"}, "Pong": {"type": "object", "description": "pong"}}
+}`
+	pongSwagger = `{
+  "swagger": "2.0",
+  "info": {"title": "pong.proto", "version": "1"},
+  "tags": [{"name": "Ping", "description": "again"}, {"name": "Pong"}],
+  "paths": {"/v1/pong": {"post": {"operationId": "Pong_Pong", "tags": ["Pong"]}}},
+  "definitions": {"rpcStatus": {"type": "object"}, "Pong": {"type": "object", "description": "different pong"}}
+}`
+	statusOpenAPI = `openapi: 3.0.3
+info:
+  title: status
+  version: "1"
+paths:
+  /v1/status:
+    get:
+      responses:
+        200:
+          description: OK
+`
+)
+
+func TestMergeOpenAPIDocuments(t *testing.T) {
+	merged, conflicts, err := mergeOpenAPIDocuments([]OpenAPIDocument{
+		{Name: "pong.swagger.json", Content: []byte(pongSwagger)},
+		{Name: "ping.swagger.json", Content: []byte(pingSwagger)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ping.proto", merged["info"].(map[string]interface{})["title"], "documents are merged by name order")
+	assert.Len(t, merged["paths"], 2)
+	assert.Len(t, merged["definitions"], 2)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "Ping"},
+		map[string]interface{}{"name": "Pong"},
+	}, merged["tags"])
+	assert.Equal(t, []string{"/definitions/Pong/description"}, conflicts)
+
+	_, _, err = mergeOpenAPIDocuments([]OpenAPIDocument{
+		{Name: "ping.swagger.json", Content: []byte(pingSwagger)},
+		{Name: "status.yaml", Content: []byte(statusOpenAPI)},
+	})
+	assert.EqualError(t, err, "status.yaml: OpenAPI v3 document can't be merged with OpenAPI v2 documents")
+
+	_, _, err = mergeOpenAPIDocuments([]OpenAPIDocument{{Name: "other.json", Content: []byte(`{"info": {}}`)}})
+	assert.EqualError(t, err, "other.json: unsupported OpenAPI version, expected `swagger: 2.0` or `openapi: 3.x`")
+}
+
+func TestOpenAPIHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cfgMock := mock_cfg.NewMockConfig(ctrl)
+	values := map[string]string{
+		confkeys.OpenAPISpecPath: "/api/openapi.json",
+		confkeys.OpenAPITitle:    "Demo API",
+	}
+	cfgMock.EXPECT().Get(gomock.Any()).DoAndReturn(func(key string) cfg.Value {
+		value := mock_cfg.NewMockValue(ctrl)
+		configured, set := values[key]
+		value.EXPECT().IsSet().Return(set).AnyTimes()
+		value.EXPECT().String().Return(configured).AnyTimes()
+		return value
+	}).AnyTimes()
+	documents, err := OpenAPIDocumentsFromFS(fstest.MapFS{
+		"api/status.yaml":  {Data: []byte(statusOpenAPI)},
+		"api/ignored.json": {Data: []byte(`{}`)},
+	}, "api/*.yaml", true)
+	require.NoError(t, err)
+	recorder := logtest.New()
+	deps := openAPIHandlersDeps{
+		Logger:    recorder.Builder().Build(),
+		Config:    cfgMock,
+		Documents: documents,
+	}
+	external, err := ExternalOpenAPIHandlers(deps)
+	require.NoError(t, err)
+	assert.Empty(t, external, "there are no external documents")
+	internal, err := InternalOpenAPIHandlers(deps)
+	require.NoError(t, err)
+	require.Len(t, internal, 2)
+	mux := http.NewServeMux()
+	for _, pair := range internal {
+		mux.Handle(pair.Pattern, pair.Handler)
+	}
+
+	recorded := httptest.NewRecorder()
+	mux.ServeHTTP(recorded, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	require.Equal(t, http.StatusOK, recorded.Code)
+	var spec map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorded.Body.Bytes(), &spec))
+	assert.Equal(t, "Demo API", spec["info"].(map[string]interface{})["title"])
+	assert.Contains(t, recorded.Body.String(), `"200":{"description":"OK"}`)
+
+	recorded = httptest.NewRecorder()
+	mux.ServeHTTP(recorded, httptest.NewRequest(http.MethodGet, "/docs/", nil))
+	require.Equal(t, http.StatusOK, recorded.Code)
+	assert.Contains(t, recorded.Body.String(), `<meta name="openapi-spec" content="/api/openapi.json">`)
+	assert.Contains(t, recorded.Header().Get("Content-Security-Policy"), "script-src 'self'")
+
+	recorded = httptest.NewRecorder()
+	mux.ServeHTTP(recorded, httptest.NewRequest(http.MethodGet, "/docs/static/docs.js", nil))
+	require.Equal(t, http.StatusOK, recorded.Code)
+	script, err := io.ReadAll(recorded.Body)
+	require.NoError(t, err)
+	assert.NotContains(t, string(script), "https://", "docs UI must not load anything from other hosts")
+
+	recorded = httptest.NewRecorder()
+	mux.ServeHTTP(recorded, httptest.NewRequest(http.MethodGet, "/docs/index.html", nil))
+	assert.Equal(t, http.StatusNotFound, recorded.Code)
+	recorder.AssertNotLogged(t, logInt.WarnLevel, "OpenAPI", nil)
+}
@@ -0,0 +1,325 @@
+// Renders the OpenAPI v2/v3 document served by Mortar, without any external dependencies.
+(function () {
+	'use strict';
+
+	var METHODS = ['get', 'put', 'post', 'delete', 'options', 'head', 'patch', 'trace'];
+	var MAX_DEPTH = 8;
+
+	function el(tag, attributes, children) {
+		var node = document.createElement(tag);
+		Object.keys(attributes || {}).forEach(function (name) {
+			if (name === 'text') {
+				node.textContent = attributes[name];
+			} else {
+				node.setAttribute(name, attributes[name]);
+			}
+		});
+		(children || []).forEach(function (child) {
+			if (child) {
+				node.appendChild(typeof child === 'string' ? document.createTextNode(child) : child);
+			}
+		});
+		return node;
+	}
+
+	function resolve(spec, value) {
+		var seen = 0;
+		while (value && typeof value.$ref === 'string' && value.$ref.indexOf('#/') === 0 && seen++ < MAX_DEPTH) {
+			value = value.$ref.substring(2).split('/').reduce(function (current, part) {
+				part = part.replace(/~1/g, '/').replace(/~0/g, '~');
+				return current ? current[part] : undefined;
+			}, spec);
+		}
+		return value || {};
+	}
+
+	// example builds a sample value of schema, used to describe messages and to prefill request bodies
+	function example(spec, schema, depth) {
+		schema = resolve(spec, schema);
+		if (depth > MAX_DEPTH) {
+			return '...';
+		}
+		if (schema.example !== undefined) {
+			return schema.example;
+		}
+		if (schema.default !== undefined) {
+			return schema.default;
+		}
+		if (schema.enum && schema.enum.length) {
+			return schema.enum[0];
+		}
+		var composed = schema.allOf || schema.oneOf || schema.anyOf;
+		if (composed && composed.length) {
+			return example(spec, composed[0], depth + 1);
+		}
+		switch (schema.type) {
+		case 'array':
+			return [example(spec, schema.items, depth + 1)];
+		case 'integer':
+		case 'number':
+			return 0;
+		case 'boolean':
+			return false;
+		case 'string':
+			return schema.format ? schema.format : 'string';
+		}
+		if (schema.properties || schema.type === 'object') {
+			var object = {};
+			Object.keys(schema.properties || {}).forEach(function (name) {
+				object[name] = example(spec, schema.properties[name], depth + 1);
+			});
+			if (schema.additionalProperties && typeof schema.additionalProperties === 'object') {
+				object.key = example(spec, schema.additionalProperties, depth + 1);
+			}
+			return object;
+		}
+		return {};
+	}
+
+	function json(value) {
+		return JSON.stringify(value, null, 2);
+	}
+
+	// Differences between OpenAPI v2 and v3
+
+	function baseURL(spec) {
+		if (spec.servers && spec.servers.length && spec.servers[0].url.charAt(0) === '/') {
+			return spec.servers[0].url.replace(/\/$/, '');
+		}
+		return (spec.basePath || '').replace(/\/$/, '');
+	}
+
+	function parameterType(spec, parameter) {
+		var schema = resolve(spec, parameter.schema || parameter);
+		if (schema.type === 'array') {
+			return (resolve(spec, schema.items).type || 'object') + '[]';
+		}
+		return schema.type || 'object';
+	}
+
+	function requestBody(spec, operation, parameters) {
+		if (operation.requestBody) {
+			var body = resolve(spec, operation.requestBody);
+			var content = body.content || {};
+			var media = content['application/json'] || content[Object.keys(content)[0]];
+			return media ? {schema: media.schema, description: body.description} : null;
+		}
+		var bodyParameter = parameters.filter(function (parameter) {
+			return parameter.in === 'body';
+		})[0];
+		return bodyParameter ? {schema: bodyParameter.schema, description: bodyParameter.description} : null;
+	}
+
+	function responseSchema(spec, response) {
+		if (response.content) {
+			var media = response.content['application/json'] || response.content[Object.keys(response.content)[0]];
+			return media && media.schema;
+		}
+		return response.schema;
+	}
+
+	// Rendering
+
+	function renderInfo(spec) {
+		var info = spec.info || {};
+		var header = document.getElementById('info');
+		header.textContent = '';
+		header.appendChild(el('h1', {}, [info.title || 'API documentation',
+			info.version ? el('span', {'class': 'version', text: info.version}) : null]));
+		if (info.description) {
+			header.appendChild(el('p', {'class': 'description', text: info.description}));
+		}
+		document.title = info.title || document.title;
+	}
+
+	function renderParameters(spec, parameters) {
+		var rows = parameters.filter(function (parameter) {
+			return parameter.in !== 'body';
+		}).map(function (parameter) {
+			return el('tr', {}, [
+				el('td', {'class': 'path', text: parameter.name + (parameter.required ? ' *' : '')}),
+				el('td', {text: parameter.in}),
+				el('td', {text: parameterType(spec, parameter)}),
+				el('td', {text: parameter.description || ''})
+			]);
+		});
+		if (!rows.length) {
+			return null;
+		}
+		return el('table', {}, [
+			el('thead', {}, [el('tr', {}, [el('th', {text: 'Name'}), el('th', {text: 'In'}), el('th', {text: 'Type'}), el('th', {text: 'Description'})])]),
+			el('tbody', {}, rows)
+		]);
+	}
+
+	function renderResponses(spec, responses) {
+		var rows = Object.keys(responses || {}).map(function (code) {
+			var response = resolve(spec, responses[code]);
+			var schema = responseSchema(spec, response);
+			return el('tr', {}, [
+				el('td', {'class': 'path', text: code}),
+				el('td', {}, [response.description || '', schema ? el('pre', {text: json(example(spec, schema, 0))}) : null])
+			]);
+		});
+		return rows.length ? el('table', {}, [el('tbody', {}, rows)]) : null;
+	}
+
+	function renderTry(spec, method, path, parameters, body) {
+		var inputs = {};
+		var fields = parameters.filter(function (parameter) {
+			return parameter.in === 'path' || parameter.in === 'query' || parameter.in === 'header';
+		}).map(function (parameter) {
+			var input = el('input', {type: 'text', placeholder: parameterType(spec, parameter)});
+			inputs[parameter.in + ':' + parameter.name] = input;
+			return el('div', {}, [el('label', {text: parameter.name + ' (' + parameter.in + ')'}), input]);
+		});
+		var bodyInput = null;
+		if (body) {
+			bodyInput = el('textarea', {spellcheck: 'false'});
+			bodyInput.value = json(example(spec, body.schema, 0));
+			fields.push(el('div', {}, [el('label', {text: 'Body'}), bodyInput]));
+		}
+		var output = el('div');
+		var button = el('button', {type: 'button', text: 'Send'});
+		button.addEventListener('click', function () {
+			var query = [];
+			var headers = {};
+			var url = baseURL(spec) + path;
+			parameters.forEach(function (parameter) {
+				var input = inputs[parameter.in + ':' + parameter.name];
+				if (!input || input.value === '') {
+					return;
+				}
+				if (parameter.in === 'path') {
+					url = url.split('{' + parameter.name + '}').join(encodeURIComponent(input.value));
+				} else if (parameter.in === 'query') {
+					query.push(encodeURIComponent(parameter.name) + '=' + encodeURIComponent(input.value));
+				} else {
+					headers[parameter.name] = input.value;
+				}
+			});
+			if (query.length) {
+				url += '?' + query.join('&');
+			}
+			var request = {method: method.toUpperCase(), headers: headers};
+			if (bodyInput) {
+				headers['Content-Type'] = 'application/json';
+				request.body = bodyInput.value;
+			}
+			output.textContent = '';
+			fetch(url, request).then(function (response) {
+				return response.text().then(function (text) {
+					try {
+						text = json(JSON.parse(text));
+					} catch (ignored) {
+						// not JSON, show as is
+					}
+					output.appendChild(el('div', {'class': 'response-status', text: response.status + ' ' + response.statusText}));
+					output.appendChild(el('pre', {text: text}));
+				});
+			}).catch(function (error) {
+				output.appendChild(el('div', {'class': 'response-status error', text: String(error)}));
+			});
+		});
+		return el('div', {'class': 'try'}, fields.concat([button, output]));
+	}
+
+	function renderOperation(spec, method, path, operation, pathParameters) {
+		var parameters = pathParameters.concat(operation.parameters || []).map(function (parameter) {
+			return resolve(spec, parameter);
+		});
+		var body = requestBody(spec, operation, parameters);
+		var details = el('details', {'class': 'operation' + (operation.deprecated ? ' deprecated' : '')}, [
+			el('summary', {}, [
+				el('span', {'class': 'method ' + method, text: method}),
+				el('span', {'class': 'path', text: path}),
+				el('span', {'class': 'summary', text: operation.summary || operation.operationId || ''})
+			])
+		]);
+		// render the body only when opened, documents can be big
+		details.addEventListener('toggle', function () {
+			if (!details.open || details.querySelector('.body')) {
+				return;
+			}
+			var parametersTable = renderParameters(spec, parameters);
+			var responsesTable = renderResponses(spec, operation.responses);
+			details.appendChild(el('div', {'class': 'body'}, [
+				operation.description ? el('p', {'class': 'description', text: operation.description}) : null,
+				parametersTable ? el('h4', {text: 'Parameters'}) : null,
+				parametersTable,
+				body ? el('h4', {text: 'Request body'}) : null,
+				body && body.description ? el('p', {'class': 'description', text: body.description}) : null,
+				body ? el('pre', {text: json(example(spec, body.schema, 0))}) : null,
+				responsesTable ? el('h4', {text: 'Responses'}) : null,
+				responsesTable,
+				el('h4', {text: 'Try it'}),
+				renderTry(spec, method, path, parameters, body)
+			]));
+		});
+		details.dataset.search = [method, path, operation.summary, operation.operationId].concat(operation.tags || []).join(' ').toLowerCase();
+		return details;
+	}
+
+	function render(spec) {
+		renderInfo(spec);
+		var byTag = {};
+		var tagOrder = (spec.tags || []).map(function (tag) {
+			return tag.name;
+		});
+		Object.keys(spec.paths || {}).sort().forEach(function (path) {
+			var item = resolve(spec, spec.paths[path]);
+			METHODS.forEach(function (method) {
+				if (!item[method]) {
+					return;
+				}
+				var tag = (item[method].tags || ['default'])[0];
+				if (!byTag[tag]) {
+					byTag[tag] = [];
+					if (tagOrder.indexOf(tag) < 0) {
+						tagOrder.push(tag);
+					}
+				}
+				byTag[tag].push(renderOperation(spec, method, path, item[method], item.parameters || []));
+			});
+		});
+		var main = document.getElementById('operations');
+		main.textContent = '';
+		tagOrder.filter(function (tag) {
+			return byTag[tag];
+		}).forEach(function (tag) {
+			var section = el('section', {}, [el('h2', {'class': 'tag', text: tag})].concat(byTag[tag]));
+			main.appendChild(section);
+		});
+		if (!main.children.length) {
+			main.appendChild(el('p', {'class': 'description', text: 'No operations are documented.'}));
+		}
+		document.getElementById('filter').addEventListener('input', function (event) {
+			var filter = event.target.value.toLowerCase();
+			Array.prototype.forEach.call(main.querySelectorAll('section'), function (section) {
+				var visible = 0;
+				Array.prototype.forEach.call(section.querySelectorAll('details.operation'), function (operation) {
+					var match = operation.dataset.search.indexOf(filter) >= 0;
+					operation.hidden = !match;
+					visible += match ? 1 : 0;
+				});
+				section.hidden = visible === 0;
+			});
+		});
+	}
+
+	function load() {
+		var specURL = document.querySelector('meta[name="openapi-spec"]').getAttribute('content');
+		fetch(specURL, {headers: {Accept: 'application/json'}}).then(function (response) {
+			if (!response.ok) {
+				throw new Error('failed to load ' + specURL + ': ' + response.status + ' ' + response.statusText);
+			}
+			return response.json();
+		}).then(render).catch(function (error) {
+			var header = document.getElementById('info');
+			header.textContent = '';
+			header.appendChild(el('h1', {'class': 'error', text: String(error.message || error)}));
+		});
+	}
+
+	load();
+}());
@@ -3,7 +3,6 @@ package handlers
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"os"
 	"strings"
@@ -54,6 +53,9 @@ func (s *selfHandlerDeps) ConfigMap() http.HandlerFunc {
 		output := make(map[string]interface{})
 		output["config"] = s.getConfigVariables()
 		output["environment"] = s.getEnvVariables()
+		if valueSources, ok := s.Config.(cfg.ValueSources); ok {
+			output["sources"] = valueSources.Sources()
+		}
 		if err := json.NewEncoder(w).Encode(output); err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			s.Logger.WithError(err).Warn(context.TODO(), "failed to server config map")
@@ -62,49 +64,21 @@ func (s *selfHandlerDeps) ConfigMap() http.HandlerFunc {
 }
 
 func (s *selfHandlerDeps) getConfigVariables() map[string]interface{} {
-	return s.obfuscateMapWhereNeeded("", s.Config.Map())
-}
-
-func (s *selfHandlerDeps) obfuscateMapWhereNeeded(prefix string, confMap map[string]interface{}) map[string]interface{} {
-	output := make(map[string]interface{})
-	for k, v := range confMap {
-		obfuscateKey := fmt.Sprintf("%s.%s", prefix, k)
-		if mValue, ok := v.(map[string]interface{}); ok {
-			output[k] = s.obfuscateMapWhereNeeded(obfuscateKey, mValue)
-		} else {
-			output[k] = s.obfuscateIfNeeded(obfuscateKey, v)
-		}
-	}
-	return output
+	return utils.ObfuscateMapWhereNeeded("", s.Config.Map(), s.hideKeys(), obfuscationEdgeLength)
 }
 
 func (s *selfHandlerDeps) getEnvVariables() map[string]string {
 	output := make(map[string]string)
+	hideKeys := s.hideKeys()
 	for _, keyValue := range os.Environ() {
 		if keyValueSlice := strings.Split(keyValue, "="); len(keyValueSlice) > 1 {
 			key := keyValueSlice[0]
-			output[key] = s.obfuscateIfNeeded(key, keyValueSlice[1])
+			output[key] = utils.ObfuscateIfNeeded(key, keyValueSlice[1], hideKeys, obfuscationEdgeLength)
 		}
 	}
 	return output
 }
 
-func (s *selfHandlerDeps) obfuscateIfNeeded(key string, value interface{}) string {
-	var valueAsString string
-	if value == nil {
-		return ""
-	}
-	switch v := value.(type) {
-	case string, fmt.Stringer:
-		valueAsString = fmt.Sprintf("%s", v)
-	default:
-		valueAsString = fmt.Sprintf("%v", v)
-	}
-	hideKeys := s.Config.Get(confkeys.ConfigHandlerObfuscateKeys).StringSlice() // if none exist slice will be empty
-	for _, hidePart := range hideKeys {
-		if strings.Contains(strings.ToLower(key), strings.ToLower(hidePart)) {
-			return utils.Obfuscate(valueAsString, obfuscationEdgeLength)
-		}
-	}
-	return valueAsString
+func (s *selfHandlerDeps) hideKeys() []string {
+	return s.Config.Get(confkeys.ConfigHandlerObfuscateKeys).StringSlice() // if none exist slice will be empty
 }
@@ -0,0 +1,168 @@
+// Package health provides a health.Registry implementation
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-masonry/mortar/interfaces/health"
+)
+
+const (
+	// DefaultTimeout of a single check
+	DefaultTimeout = 5 * time.Second
+	// ShutdownCheckName is the name of the critical Readiness check that fails once the registry is shut down
+	ShutdownCheckName = "shutdown"
+)
+
+type registeredCheck struct {
+	health.Check
+	sync.Mutex
+	last *health.CheckResult
+}
+
+type registry struct {
+	sync.RWMutex
+	checks       map[string]*registeredCheck
+	now          func() time.Time
+	shuttingDown atomic.Bool
+}
+
+// NewRegistry creates an empty health.Registry
+func NewRegistry() health.Registry {
+	return &registry{
+		checks: make(map[string]*registeredCheck),
+		now:    time.Now,
+	}
+}
+
+func (r *registry) Register(check health.Check) error {
+	if len(check.Name) == 0 || check.Check == nil {
+		return fmt.Errorf("health check must have a name and a check function")
+	}
+	if check.Timeout <= 0 {
+		check.Timeout = DefaultTimeout
+	}
+	if len(check.Probes) == 0 {
+		check.Probes = []health.Probe{health.Readiness}
+	}
+	r.Lock()
+	defer r.Unlock()
+	if _, exists := r.checks[check.Name]; exists {
+		return fmt.Errorf("health check [%s] is already registered", check.Name)
+	}
+	r.checks[check.Name] = &registeredCheck{Check: check}
+	return nil
+}
+
+func (r *registry) Run(ctx context.Context, probe health.Probe) health.Report {
+	checks := r.probeChecks(probe)
+	report := health.Report{
+		Probe:  probe,
+		Status: health.Pass,
+		Checks: make([]health.CheckResult, len(checks)),
+	}
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check *registeredCheck) {
+			defer wg.Done()
+			report.Checks[i] = r.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+	if probe == health.Readiness && r.shuttingDown.Load() {
+		report.Checks = append([]health.CheckResult{{
+			Name:      ShutdownCheckName,
+			Status:    health.Fail,
+			Critical:  true,
+			Error:     "service is shutting down",
+			CheckedAt: r.now(),
+		}}, report.Checks...)
+	}
+	for _, result := range report.Checks {
+		switch {
+		case result.Status != health.Fail:
+		case result.Critical:
+			report.Status = health.Fail
+		case report.Status == health.Pass:
+			report.Status = health.Warn
+		}
+	}
+	return report
+}
+
+func (r *registry) Shutdown() {
+	r.shuttingDown.Store(true)
+}
+
+// probeChecks returns checks of the probe sorted by name
+func (r *registry) probeChecks(probe health.Probe) (output []*registeredCheck) {
+	r.RLock()
+	defer r.RUnlock()
+	for _, check := range r.checks {
+		for _, p := range check.Probes {
+			if p == probe {
+				output = append(output, check)
+				break
+			}
+		}
+	}
+	sort.Slice(output, func(i, j int) bool {
+		return output[i].Name < output[j].Name
+	})
+	return
+}
+
+// run runs a single check, concurrent probes of a cached check share a single call
+func (r *registry) run(ctx context.Context, check *registeredCheck) health.CheckResult {
+	check.Lock()
+	defer check.Unlock()
+	if check.last != nil && check.CacheInterval > 0 && r.now().Sub(check.last.CheckedAt) < check.CacheInterval {
+		cached := *check.last
+		cached.Cached = true
+		return cached
+	}
+	start := r.now()
+	err := callWithTimeout(ctx, check.Timeout, check.Check.Check)
+	result := health.CheckResult{
+		Name:      check.Name,
+		Status:    health.Pass,
+		Critical:  check.Critical,
+		Latency:   r.now().Sub(start),
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = health.Fail
+		result.Error = err.Error()
+	}
+	check.last = &result
+	return result
+}
+
+// callWithTimeout returns when either check returns or the timeout expires, even if check ignores ctx
+func callWithTimeout(ctx context.Context, timeout time.Duration, check health.CheckFunc) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("health check panicked, %v", r)
+			}
+		}()
+		done <- check(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("health check timed out after %s, %w", timeout, ctx.Err())
+	}
+}
+
+var _ health.Registry = (*registry)(nil)
@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-masonry/mortar/interfaces/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryProbes(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(health.Check{Name: "db", Critical: true, Check: func(context.Context) error { return nil }}))
+	require.NoError(t, registry.Register(health.Check{Name: "cache", Check: func(context.Context) error { return errors.New("connection refused") }}))
+	require.NoError(t, registry.Register(health.Check{Name: "deadlock", Critical: true, Probes: []health.Probe{health.Liveness}, Check: func(context.Context) error { return nil }}))
+	assert.Error(t, registry.Register(health.Check{Name: "db", Check: func(context.Context) error { return nil }}))
+	assert.Error(t, registry.Register(health.Check{Name: "no function"}))
+
+	report := registry.Run(context.Background(), health.Readiness)
+	assert.Equal(t, health.Warn, report.Status, "non critical failure")
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, "cache", report.Checks[0].Name)
+	assert.Equal(t, health.Fail, report.Checks[0].Status)
+	assert.Equal(t, "connection refused", report.Checks[0].Error)
+	assert.Equal(t, health.Pass, report.Checks[1].Status)
+
+	report = registry.Run(context.Background(), health.Liveness)
+	assert.Equal(t, health.Pass, report.Status)
+	assert.Len(t, report.Checks, 1)
+
+	report = registry.Run(context.Background(), health.Startup)
+	assert.Equal(t, health.Pass, report.Status)
+	assert.Empty(t, report.Checks)
+}
+
+func TestRegistryTimeoutAndPanic(t *testing.T) {
+	registry := NewRegistry()
+	block := make(chan struct{})
+	defer close(block)
+	require.NoError(t, registry.Register(health.Check{Name: "stuck", Critical: true, Timeout: 10 * time.Millisecond, Check: func(context.Context) error {
+		<-block // ignores ctx
+		return nil
+	}}))
+	require.NoError(t, registry.Register(health.Check{Name: "panic", Check: func(context.Context) error { panic("boom") }}))
+	report := registry.Run(context.Background(), health.Readiness)
+	assert.Equal(t, health.Fail, report.Status)
+	assert.Contains(t, report.Checks[0].Error, "panicked, boom")
+	assert.Contains(t, report.Checks[1].Error, "timed out after 10ms")
+}
+
+func TestRegistryCache(t *testing.T) {
+	reg := NewRegistry().(*registry)
+	now := time.Now()
+	reg.now = func() time.Time { return now }
+	calls := 0
+	require.NoError(t, reg.Register(health.Check{Name: "counted", CacheInterval: time.Minute, Check: func(context.Context) error {
+		calls++
+		return nil
+	}}))
+	assert.False(t, reg.Run(context.Background(), health.Readiness).Checks[0].Cached)
+	assert.True(t, reg.Run(context.Background(), health.Readiness).Checks[0].Cached)
+	now = now.Add(time.Minute)
+	assert.False(t, reg.Run(context.Background(), health.Readiness).Checks[0].Cached)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRegistryShutdown(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(health.Check{Name: "db", Critical: true, Check: func(context.Context) error { return nil }}))
+	registry.Shutdown()
+	report := registry.Run(context.Background(), health.Readiness)
+	assert.Equal(t, health.Fail, report.Status)
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, ShutdownCheckName, report.Checks[0].Name)
+	assert.Equal(t, health.Pass, report.Checks[1].Status, "checks still run")
+	assert.Equal(t, health.Pass, registry.Run(context.Background(), health.Liveness).Status, "process is still alive")
+}
@@ -25,6 +25,8 @@ type restConfig struct {
 	grpcGatewayHandlers     []server.GRPCGatewayGeneratedHandlers
 	grpcGatewayOptions      []runtime.ServeMuxOption
 	grpcGatewayInterceptors []server.GRPCGatewayInterceptor
+	useInternalGRPC         bool
+	grpcWeb                 *server.GRPCWebOptions
 }
 
 type restBuilder struct {
@@ -110,6 +112,20 @@ func (r *restBuilder) AddGRPCGatewayInterceptors(interceptors ...server.GRPCGate
 	return r
 }
 
+func (r *restBuilder) UseInternalGRPCServer() server.RESTBuilder {
+	r.ll.PushBack(func(cfg *restConfig) {
+		cfg.useInternalGRPC = true
+	})
+	return r
+}
+
+func (r *restBuilder) EnableGRPCWeb(options server.GRPCWebOptions) server.RESTBuilder {
+	r.ll.PushBack(func(cfg *restConfig) {
+		cfg.grpcWeb = &options
+	})
+	return r
+}
+
 func (r *restBuilder) BuildRESTPart() server.GRPCWebServiceBuilder {
 	for e := r.ll.Front(); e != nil; e = e.Next() {
 		f := e.Value.(func(cfg *restConfig))
@@ -118,6 +134,67 @@ func (r *restBuilder) BuildRESTPart() server.GRPCWebServiceBuilder {
 	return r.parent
 }
 
+// ******************************************************************************************************************************************************
+// ****************************************************************INTERNAL GRPC BUILDER*****************************************************************
+// ******************************************************************************************************************************************************
+
+type internalGRPCBuilder struct {
+	parent server.GRPCWebServiceBuilder
+	cfg    *grpcConfig
+	ll     *list.List
+}
+
+func newInternalGRPCBuilder(cfg *grpcConfig, parent server.GRPCWebServiceBuilder) server.InternalGRPCBuilder {
+	return &internalGRPCBuilder{
+		parent: parent,
+		cfg:    cfg,
+		ll:     list.New(),
+	}
+}
+
+func (i *internalGRPCBuilder) ListenOn(addr string) server.InternalGRPCBuilder {
+	i.ll.PushBack(func(cfg *grpcConfig) {
+		cfg.addr = addr
+	})
+	return i
+}
+
+func (i *internalGRPCBuilder) SetCustomGRPCServer(server *grpc.Server) server.InternalGRPCBuilder {
+	i.ll.PushBack(func(cfg *grpcConfig) {
+		cfg.server = server
+	})
+	return i
+}
+
+func (i *internalGRPCBuilder) SetCustomListener(listener net.Listener) server.InternalGRPCBuilder {
+	i.ll.PushBack(func(cfg *grpcConfig) {
+		cfg.listener = listener
+	})
+	return i
+}
+
+func (i *internalGRPCBuilder) RegisterGRPCAPIs(apis ...server.GRPCServerAPI) server.InternalGRPCBuilder {
+	i.ll.PushBack(func(cfg *grpcConfig) {
+		cfg.registerAPI = append(cfg.registerAPI, apis...)
+	})
+	return i
+}
+
+func (i *internalGRPCBuilder) AddGRPCServerOptions(options ...grpc.ServerOption) server.InternalGRPCBuilder {
+	i.ll.PushBack(func(cfg *grpcConfig) {
+		cfg.options = append(cfg.options, options...)
+	})
+	return i
+}
+
+func (i *internalGRPCBuilder) BuildInternalGRPCPart() server.GRPCWebServiceBuilder {
+	for e := i.ll.Front(); e != nil; e = e.Next() {
+		f := e.Value.(func(cfg *grpcConfig))
+		f(i.cfg)
+	}
+	return i.parent
+}
+
 // ******************************************************************************************************************************************************
 // ***************************************************************************GRPC BUILDER***************************************************************
 // ******************************************************************************************************************************************************
@@ -132,9 +209,14 @@ type grpcConfig struct {
 }
 
 type webServiceConfig struct {
-	grpc   *grpcConfig
-	rest   []*restConfig
-	logger func(ctx context.Context, format string, args ...interface{})
+	grpc         *grpcConfig
+	internalGRPC *grpcConfig
+	rest         []*restConfig
+	logger       func(ctx context.Context, format string, args ...interface{})
+	stopHooks    []func(ctx context.Context)
+	shutdown     server.ShutdownPolicy
+	observers    []server.ShutdownObserver
+	inFlight     *inFlight
 }
 
 type serviceBuilder struct {
@@ -195,6 +277,27 @@ func (s *serviceBuilder) SetLogger(logger func(ctx context.Context, format strin
 	return s
 }
 
+func (s *serviceBuilder) AddStopHooks(hooks ...func(ctx context.Context)) server.GRPCWebServiceBuilder {
+	s.ll.PushBack(func(cfg *webServiceConfig) {
+		cfg.stopHooks = append(cfg.stopHooks, hooks...)
+	})
+	return s
+}
+
+func (s *serviceBuilder) SetShutdownPolicy(policy server.ShutdownPolicy) server.GRPCWebServiceBuilder {
+	s.ll.PushBack(func(cfg *webServiceConfig) {
+		cfg.shutdown = policy
+	})
+	return s
+}
+
+func (s *serviceBuilder) AddShutdownObservers(observers ...server.ShutdownObserver) server.GRPCWebServiceBuilder {
+	s.ll.PushBack(func(cfg *webServiceConfig) {
+		cfg.observers = append(cfg.observers, observers...)
+	})
+	return s
+}
+
 func (s *serviceBuilder) AddRESTServerConfiguration() server.RESTBuilder {
 	emptyRESTConfig := new(restConfig)
 	s.ll.PushBack(func(cfg *webServiceConfig) {
@@ -203,9 +306,18 @@ func (s *serviceBuilder) AddRESTServerConfiguration() server.RESTBuilder {
 	return newRESTBuilder(emptyRESTConfig, s)
 }
 
+func (s *serviceBuilder) AddInternalGRPCServerConfiguration() server.InternalGRPCBuilder {
+	emptyGRPCConfig := new(grpcConfig)
+	s.ll.PushBack(func(cfg *webServiceConfig) {
+		cfg.internalGRPC = emptyGRPCConfig
+	})
+	return newInternalGRPCBuilder(emptyGRPCConfig, s)
+}
+
 func (s *serviceBuilder) Build() (server.WebService, error) {
 	cfg := &webServiceConfig{
-		grpc: new(grpcConfig),
+		grpc:     new(grpcConfig),
+		inFlight: new(inFlight),
 	}
 	for e := s.ll.Front(); e != nil; e = e.Next() {
 		f := e.Value.(func(cfg *webServiceConfig))
@@ -217,13 +329,22 @@ func (s *serviceBuilder) Build() (server.WebService, error) {
 	if cfg.grpc.panicHandler == nil {
 		cfg.grpc.panicHandler = defaultPanicHandler
 	}
-	cfg.grpc.options = append([]grpc.ServerOption{ // make sure they are outer most
-		grpc.ChainUnaryInterceptor(panicHandlerUnaryInterceptor(cfg.grpc.panicHandler)),
-		grpc.ChainStreamInterceptor(panicHandlerStreamInterceptor(cfg.grpc.panicHandler)),
-	}, cfg.grpc.options...)
+	cfg.grpc.options = cfg.outerMostOptions(cfg.grpc.options)
+	if cfg.internalGRPC != nil {
+		cfg.internalGRPC.options = cfg.outerMostOptions(cfg.internalGRPC.options)
+	}
 	return newWebService(cfg)
 }
 
+// outerMostOptions adds in-flight counting and panic handling before the provided options
+func (cfg *webServiceConfig) outerMostOptions(options []grpc.ServerOption) []grpc.ServerOption {
+	return append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(cfg.inFlight.unaryInterceptor(), panicHandlerUnaryInterceptor(cfg.grpc.panicHandler)),
+		grpc.ChainStreamInterceptor(cfg.inFlight.streamInterceptor(), panicHandlerStreamInterceptor(cfg.grpc.panicHandler)),
+	}, options...)
+}
+
 // Sanity
 var _ server.GRPCWebServiceBuilder = (*serviceBuilder)(nil)
 var _ server.RESTBuilder = (*restBuilder)(nil)
+var _ server.InternalGRPCBuilder = (*internalGRPCBuilder)(nil)
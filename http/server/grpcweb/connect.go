@@ -0,0 +1,414 @@
+package grpcweb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	connectTimeoutHeader       = "Connect-Timeout-Ms"
+	connectContentEncoding     = "Connect-Content-Encoding"
+	connectProtocolVersion     = "Connect-Protocol-Version"
+	connectUnaryTrailerPrefix  = "Trailer-"
+	connectMaxGRPCTimeoutValue = 99999999 // grpc-timeout allows at most 8 digits
+)
+
+var connectCodes = map[codes.Code]string{
+	codes.Canceled:           "canceled",
+	codes.Unknown:            "unknown",
+	codes.InvalidArgument:    "invalid_argument",
+	codes.DeadlineExceeded:   "deadline_exceeded",
+	codes.NotFound:           "not_found",
+	codes.AlreadyExists:      "already_exists",
+	codes.PermissionDenied:   "permission_denied",
+	codes.ResourceExhausted:  "resource_exhausted",
+	codes.FailedPrecondition: "failed_precondition",
+	codes.Aborted:            "aborted",
+	codes.OutOfRange:         "out_of_range",
+	codes.Unimplemented:      "unimplemented",
+	codes.Internal:           "internal",
+	codes.Unavailable:        "unavailable",
+	codes.DataLoss:           "data_loss",
+	codes.Unauthenticated:    "unauthenticated",
+}
+
+// connectHTTPStatus of unary calls that failed
+var connectHTTPStatus = map[codes.Code]int{
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+type connectError struct {
+	Code    string          `json:"code"`
+	Message string          `json:"message,omitempty"`
+	Details []connectDetail `json:"details,omitempty"`
+}
+
+type connectDetail struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type connectEndStream struct {
+	Error    *connectError       `json:"error,omitempty"`
+	Metadata map[string][]string `json:"metadata,omitempty"`
+}
+
+// codec converts Connect messages to and from the protobuf messages the gRPC server expects
+type codec struct {
+	json bool
+}
+
+func connectCodec(contentType string) (codec, bool) {
+	switch contentType {
+	case connectUnaryProto, connectStreamPrefix + "proto":
+		return codec{}, true
+	case connectUnaryJSON, connectStreamPrefix + "json":
+		return codec{json: true}, true
+	}
+	return codec{}, false
+}
+
+// method finds descriptors of method, only JSON needs them
+func (c codec) method(path string) (protoreflect.MethodDescriptor, error) {
+	if !c.json {
+		return nil, nil
+	}
+	fullName := strings.Replace(strings.TrimPrefix(path, "/"), "/", ".", 1)
+	descriptor, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(fullName))
+	if err != nil {
+		return nil, status.Errorf(codes.Unimplemented, "JSON is not supported for %s, %v", path, err)
+	}
+	method, ok := descriptor.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "%s is not a method", fullName)
+	}
+	return method, nil
+}
+
+func (c codec) toProto(descriptor protoreflect.MessageDescriptor, data []byte) ([]byte, error) {
+	if !c.json {
+		return data, nil
+	}
+	message := dynamicpb.NewMessage(descriptor)
+	if err := protojson.Unmarshal(data, message); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to unmarshal JSON message, %v", err)
+	}
+	return proto.Marshal(message)
+}
+
+func (c codec) fromProto(descriptor protoreflect.MessageDescriptor, data []byte) ([]byte, error) {
+	if !c.json {
+		return data, nil
+	}
+	message := dynamicpb.NewMessage(descriptor)
+	if err := proto.Unmarshal(data, message); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to unmarshal response message, %v", err)
+	}
+	return protojson.Marshal(message)
+}
+
+// serveConnect unary calls are sent without envelopes, streaming calls end with an end of stream message instead of trailers
+func (h *handler) serveConnect(w http.ResponseWriter, r *http.Request, c codec, streaming bool) {
+	contentType := r.Header.Get("Content-Type")
+	method, err := c.method(r.URL.Path)
+	if err == nil {
+		err = checkConnectEncoding(r, streaming)
+	}
+	if err != nil {
+		writeConnectFailure(w, contentType, streaming, status.Convert(err))
+		return
+	}
+	req := grpcRequest(r)
+	for _, key := range []string{connectTimeoutHeader, connectContentEncoding, connectProtocolVersion, "Content-Encoding", "Connect-Accept-Encoding", "Accept-Encoding"} {
+		req.Header.Del(key)
+	}
+	if timeout := r.Header.Get(connectTimeoutHeader); len(timeout) > 0 {
+		req.Header.Set("Grpc-Timeout", grpcTimeout(timeout))
+	}
+	var protocol protocol
+	if streaming {
+		streamProtocol := &connectStreamProtocol{w: w, contentType: contentType, codec: c, method: method}
+		req.Body = c.requestStream(method, r.Body, h.maxRecvMsgSize(), streamProtocol.requestFailed)
+		protocol = streamProtocol
+	} else {
+		var body []byte
+		if body, err = readAll(r.Body, h.maxRecvMsgSize()); err == nil {
+			body, err = c.toProto(inputOf(method), body)
+		}
+		if err != nil {
+			writeConnectFailure(w, contentType, false, status.Convert(err))
+			return
+		}
+		req.Body = io.NopCloser(bytes.NewReader(envelope(0, body)))
+		protocol = &connectUnaryProtocol{w: w, contentType: contentType, codec: c, method: method}
+	}
+	rw := newResponseWriter(protocol)
+	h.srv.ServeHTTP(rw, req)
+	req.Body.Close() // stops converting the request stream
+	rw.finish()
+}
+
+func checkConnectEncoding(r *http.Request, streaming bool) error {
+	encoding := r.Header.Get("Content-Encoding")
+	if streaming {
+		encoding = r.Header.Get(connectContentEncoding)
+	}
+	if len(encoding) > 0 && encoding != "identity" {
+		return status.Errorf(codes.Unimplemented, "unsupported compression %q", encoding)
+	}
+	return nil
+}
+
+// grpcTimeout converts Connect timeout in milliseconds to a grpc-timeout value
+func grpcTimeout(milliseconds string) string {
+	value, err := strconv.ParseInt(milliseconds, 10, 64)
+	if err != nil || value < 0 {
+		return milliseconds // let the gRPC server reject it
+	}
+	if value > connectMaxGRPCTimeoutValue {
+		return strconv.FormatInt(value/1000, 10) + "S"
+	}
+	return strconv.FormatInt(value, 10) + "m"
+}
+
+// requestStream converts JSON messages of the request stream while they are read, failed is called with
+// the status of a message that can't be converted before the stream is closed with it
+func (c codec) requestStream(method protoreflect.MethodDescriptor, body io.ReadCloser, maxSize int, failed func(st *status.Status)) io.ReadCloser {
+	if !c.json {
+		return body // the gRPC server enforces the same limit on proto messages
+	}
+	reader, writer := io.Pipe()
+	go func() {
+		defer body.Close()
+		for {
+			flags, payload, err := readFrame(body, maxSize)
+			if err == nil {
+				if payload, err = c.toProto(inputOf(method), payload); err == nil {
+					_, err = writer.Write(envelope(flags, payload))
+				}
+			}
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				} else if st, ok := status.FromError(err); ok {
+					failed(st)
+				}
+				writer.CloseWithError(err)
+				return
+			}
+		}
+	}()
+	return reader
+}
+
+type connectUnaryProtocol struct {
+	w           http.ResponseWriter
+	contentType string
+	codec       codec
+	method      protoreflect.MethodDescriptor
+	header      http.Header
+	body        frameBuffer
+}
+
+func (c *connectUnaryProtocol) writeHeader(header http.Header) {
+	c.header = header
+}
+
+func (c *connectUnaryProtocol) writeData(p []byte) {
+	c.body.Write(p)
+}
+
+func (c *connectUnaryProtocol) flush() {}
+
+func (c *connectUnaryProtocol) finish(trailer http.Header) {
+	for key, values := range c.header {
+		c.w.Header()[key] = values
+	}
+	for key, values := range trailerMetadata(trailer) {
+		c.w.Header()[connectUnaryTrailerPrefix+key] = values
+	}
+	st := statusFromTrailer(trailer)
+	var message []byte
+	if st.Code() == codes.OK {
+		flags, payload, ok := c.body.next()
+		switch {
+		case !ok:
+			st = status.New(codes.Internal, "gRPC server didn't send a response message")
+		case flags&compressedFlag != 0:
+			st = status.New(codes.Internal, "gRPC server sent a compressed response message")
+		default:
+			var err error
+			if message, err = c.codec.fromProto(outputOf(c.method), payload); err != nil {
+				st = status.Convert(err)
+			}
+		}
+	}
+	if st.Code() != codes.OK {
+		writeConnectFailure(c.w, c.contentType, false, st)
+		return
+	}
+	c.w.Header().Set("Content-Type", c.contentType)
+	c.w.Header().Set("Content-Length", strconv.Itoa(len(message)))
+	c.w.WriteHeader(http.StatusOK)
+	c.w.Write(message)
+}
+
+type connectStreamProtocol struct {
+	w           http.ResponseWriter
+	contentType string
+	codec       codec
+	method      protoreflect.MethodDescriptor
+	frames      frameBuffer
+	failure     *status.Status
+	// requestFailure is set by the request stream goroutine
+	requestFailure atomic.Pointer[status.Status]
+}
+
+func (c *connectStreamProtocol) requestFailed(st *status.Status) {
+	c.requestFailure.Store(st)
+}
+
+func (c *connectStreamProtocol) writeHeader(header http.Header) {
+	for key, values := range header {
+		c.w.Header()[key] = values
+	}
+	c.w.Header().Set("Content-Type", c.contentType)
+	c.w.WriteHeader(http.StatusOK)
+}
+
+func (c *connectStreamProtocol) writeData(p []byte) {
+	c.frames.Write(p)
+	for c.failure == nil {
+		flags, payload, ok := c.frames.next()
+		if !ok {
+			return
+		}
+		message, err := c.codec.fromProto(outputOf(c.method), payload)
+		if err != nil {
+			c.failure = status.Convert(err)
+			return
+		}
+		c.w.Write(envelope(flags, message))
+	}
+}
+
+func (c *connectStreamProtocol) flush() {
+	if flusher, ok := c.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (c *connectStreamProtocol) finish(trailer http.Header) {
+	st := statusFromTrailer(trailer)
+	if requestFailure := c.requestFailure.Load(); requestFailure != nil {
+		st = requestFailure // the call failed because its request stream was closed
+	}
+	if c.failure != nil {
+		st = c.failure
+	}
+	c.w.Write(envelope(endStreamFlag, endStream(st, trailerMetadata(trailer))))
+	c.flush()
+}
+
+// writeConnectFailure writes st as a failed unary call or as a stream that has only the end of stream message
+func writeConnectFailure(w http.ResponseWriter, contentType string, streaming bool, st *status.Status) {
+	if streaming {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		w.Write(envelope(endStreamFlag, endStream(st, nil)))
+		return
+	}
+	body, _ := json.Marshal(newConnectError(st))
+	w.Header().Set("Content-Type", connectUnaryJSON)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	code, ok := connectHTTPStatus[st.Code()]
+	if !ok {
+		code = http.StatusInternalServerError
+	}
+	w.WriteHeader(code)
+	w.Write(body)
+}
+
+func endStream(st *status.Status, md map[string][]string) []byte {
+	message := connectEndStream{Metadata: md}
+	if st.Code() != codes.OK {
+		message.Error = newConnectError(st)
+	}
+	data, _ := json.Marshal(message)
+	return data
+}
+
+func newConnectError(st *status.Status) *connectError {
+	code, ok := connectCodes[st.Code()]
+	if !ok {
+		code = connectCodes[codes.Unknown]
+	}
+	connectErr := &connectError{Code: code, Message: st.Message()}
+	for _, detail := range st.Proto().GetDetails() {
+		typeURL := detail.GetTypeUrl()
+		connectErr.Details = append(connectErr.Details, connectDetail{
+			Type:  typeURL[strings.LastIndex(typeURL, "/")+1:],
+			Value: base64.RawStdEncoding.EncodeToString(detail.GetValue()),
+		})
+	}
+	return connectErr
+}
+
+// trailerMetadata are custom trailers of the call
+func trailerMetadata(trailer http.Header) map[string][]string {
+	md := make(map[string][]string)
+	for key, values := range trailer {
+		switch key {
+		case grpcStatusHeader, grpcMessageHeader, grpcStatusDetailsHeader:
+		default:
+			md[key] = values
+		}
+	}
+	if len(md) == 0 {
+		return nil
+	}
+	return md
+}
+
+func inputOf(method protoreflect.MethodDescriptor) protoreflect.MessageDescriptor {
+	if method == nil {
+		return nil
+	}
+	return method.Input()
+}
+
+func outputOf(method protoreflect.MethodDescriptor) protoreflect.MessageDescriptor {
+	if method == nil {
+		return nil
+	}
+	return method.Output()
+}
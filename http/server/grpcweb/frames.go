@@ -0,0 +1,217 @@
+package grpcweb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	frameHeaderLength = 5
+	// compressedFlag marks a compressed message
+	compressedFlag byte = 0x01
+	// endStreamFlag marks the Connect end of stream message
+	endStreamFlag byte = 0x02
+	// trailerFlag marks the gRPC-Web trailers frame
+	trailerFlag byte = 0x80
+)
+
+const (
+	grpcStatusHeader        = "Grpc-Status"
+	grpcMessageHeader       = "Grpc-Message"
+	grpcStatusDetailsHeader = "Grpc-Status-Details-Bin"
+)
+
+// envelope prefixes payload with flags and length, the same way gRPC, gRPC-Web and Connect streams do
+func envelope(flags byte, payload []byte) []byte {
+	output := make([]byte, frameHeaderLength+len(payload))
+	output[0] = flags
+	binary.BigEndian.PutUint32(output[1:], uint32(len(payload)))
+	copy(output[frameHeaderLength:], payload)
+	return output
+}
+
+// readFrame reads a single enveloped message, io.EOF is returned only if there is nothing to read.
+//
+// Messages larger than maxSize are rejected with codes.ResourceExhausted before they are read
+func readFrame(reader io.Reader, maxSize int) (flags byte, payload []byte, err error) {
+	header := make([]byte, frameHeaderLength)
+	if _, err = io.ReadFull(reader, header); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = fmt.Errorf("incomplete message header")
+		}
+		return
+	}
+	length := binary.BigEndian.Uint32(header[1:])
+	if uint64(length) > uint64(maxSize) {
+		return 0, nil, tooLarge(int64(length), maxSize)
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(reader, payload); err != nil {
+		return 0, nil, fmt.Errorf("incomplete message, %w", err)
+	}
+	return header[0], payload, nil
+}
+
+// readAll reads reader up to maxSize bytes, larger content is rejected with codes.ResourceExhausted
+func readAll(reader io.Reader, maxSize int) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, int64(maxSize)+1))
+	if err == nil && len(data) > maxSize {
+		return nil, tooLarge(int64(len(data)), maxSize)
+	}
+	return data, err
+}
+
+func tooLarge(size int64, maxSize int) error {
+	return status.Errorf(codes.ResourceExhausted, "received message larger than max (%d vs. %d)", size, maxSize)
+}
+
+// frameBuffer collects written bytes and returns complete frames
+type frameBuffer struct {
+	bytes.Buffer
+}
+
+func (f *frameBuffer) next() (flags byte, payload []byte, ok bool) {
+	data := f.Bytes()
+	if len(data) < frameHeaderLength {
+		return 0, nil, false
+	}
+	length := int(binary.BigEndian.Uint32(data[1:]))
+	if len(data) < frameHeaderLength+length {
+		return 0, nil, false
+	}
+	flags = data[0]
+	payload = append([]byte(nil), data[frameHeaderLength:frameHeaderLength+length]...)
+	f.Next(frameHeaderLength + length)
+	return flags, payload, true
+}
+
+// protocol translates the output of the gRPC server
+type protocol interface {
+	// writeHeader is called once with response headers of the gRPC call
+	writeHeader(header http.Header)
+	// writeData is called with parts of the gRPC response body
+	writeData(p []byte)
+	// flush is called when the gRPC server flushes its output
+	flush()
+	// finish is called once after the call is done with its trailers, gRPC status is part of them
+	finish(trailer http.Header)
+}
+
+// responseWriter is the http.ResponseWriter given to the gRPC server, headers that are set after the first write are trailers
+type responseWriter struct {
+	header      http.Header
+	wroteHeader bool
+	protocol    protocol
+	// rejection is set when the gRPC server rejects the request before the call starts
+	rejection *bytes.Buffer
+}
+
+func newResponseWriter(p protocol) *responseWriter {
+	return &responseWriter{
+		header:   make(http.Header),
+		protocol: p,
+	}
+}
+
+func (rw *responseWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *responseWriter) Write(p []byte) (int, error) {
+	rw.WriteHeader(http.StatusOK)
+	if rw.rejection != nil {
+		return rw.rejection.Write(p)
+	}
+	rw.protocol.writeData(p)
+	return len(p), nil
+}
+
+func (rw *responseWriter) WriteHeader(code int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	if code != http.StatusOK {
+		rw.rejection = new(bytes.Buffer)
+		return
+	}
+	rw.writeHeader()
+}
+
+func (rw *responseWriter) writeHeader() {
+	header := make(http.Header, len(rw.header))
+	for key, values := range rw.header {
+		switch key {
+		case "Trailer", "Content-Type", "Date", grpcStatusHeader, grpcMessageHeader, grpcStatusDetailsHeader:
+		default:
+			header[key] = append([]string(nil), values...)
+		}
+	}
+	rw.protocol.writeHeader(header)
+}
+
+func (rw *responseWriter) Flush() {
+	rw.WriteHeader(http.StatusOK)
+	if rw.rejection == nil {
+		rw.protocol.flush()
+	}
+}
+
+// finish passes trailers of the call to the protocol
+func (rw *responseWriter) finish() {
+	rw.WriteHeader(http.StatusOK)
+	if rw.rejection != nil {
+		rw.header = http.Header{
+			grpcStatusHeader:  {strconv.Itoa(int(codes.Internal))},
+			grpcMessageHeader: {url.PathEscape(strings.TrimSpace(rw.rejection.String()))},
+		}
+		rw.writeHeader()
+	}
+	trailer := make(http.Header)
+	for key, values := range rw.header {
+		switch {
+		case key == grpcStatusHeader || key == grpcMessageHeader || key == grpcStatusDetailsHeader:
+			trailer[key] = values
+		case strings.HasPrefix(key, http.TrailerPrefix):
+			trailer[http.CanonicalHeaderKey(strings.TrimPrefix(key, http.TrailerPrefix))] = values
+		}
+	}
+	if len(trailer.Get(grpcStatusHeader)) == 0 { // the call didn't reach the server, it was probably stopped
+		trailer.Set(grpcStatusHeader, strconv.Itoa(int(codes.Unavailable)))
+		trailer.Set(grpcMessageHeader, "gRPC server is not serving")
+	}
+	rw.protocol.finish(trailer)
+}
+
+// statusFromTrailer parses gRPC status of the call
+func statusFromTrailer(trailer http.Header) *status.Status {
+	code, err := strconv.Atoi(trailer.Get(grpcStatusHeader))
+	if err != nil {
+		return status.Newf(codes.Unknown, "invalid gRPC status %q", trailer.Get(grpcStatusHeader))
+	}
+	if details := trailer.Get(grpcStatusDetailsHeader); len(details) > 0 {
+		if data, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(details, "=")); err == nil {
+			statusProto := new(spb.Status)
+			if proto.Unmarshal(data, statusProto) == nil {
+				return status.FromProto(statusProto)
+			}
+		}
+	}
+	message := trailer.Get(grpcMessageHeader)
+	if decoded, err := url.PathUnescape(message); err == nil {
+		message = decoded
+	}
+	return status.New(codes.Code(code), message)
+}
@@ -0,0 +1,131 @@
+// Package grpcweb translates gRPC-Web and Connect calls made by browsers to calls of a gRPC server.
+//
+// Calls are served by grpc.Server.ServeHTTP, so every gRPC server option and interceptor applies to them.
+package grpcweb
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-masonry/mortar/interfaces/http/server"
+	"google.golang.org/grpc"
+)
+
+const (
+	grpcWebContentType     = "application/grpc-web"
+	grpcWebTextContentType = "application/grpc-web-text"
+	connectStreamPrefix    = "application/connect+"
+	connectUnaryProto      = "application/proto"
+	connectUnaryJSON       = "application/json"
+	grpcContentType        = "application/grpc+proto"
+	// DefaultMaxRecvMsgSize is used when GRPCWebOptions.MaxRecvMsgSize is not set, it's the gRPC server default
+	DefaultMaxRecvMsgSize = 4 << 20
+)
+
+// exposedHeaders are readable by browsers of origins allowed by CORS
+var exposedHeaders = strings.Join([]string{"grpc-status", "grpc-message", "grpc-status-details-bin"}, ", ")
+
+type handler struct {
+	srv         *grpc.Server
+	next        http.Handler
+	options     server.GRPCWebOptions
+	intercepted http.Handler
+	methodsOnce sync.Once
+	methods     map[string]struct{}
+}
+
+// Wrap returns an http.Handler that serves enabled protocols using srv, every other request is passed to next.
+//
+// Calls and CORS preflight requests of gRPC methods are wrapped by options.CORS, such as the CORS interceptor of the gRPC gateway.
+func Wrap(srv *grpc.Server, next http.Handler, options server.GRPCWebOptions) http.Handler {
+	h := &handler{
+		srv:     srv,
+		next:    next,
+		options: options,
+	}
+	h.intercepted = http.HandlerFunc(h.serveProtocol)
+	if options.CORS != nil {
+		h.intercepted = options.CORS(h.intercepted)
+	}
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.protocol(r); ok || h.isPreflight(r) {
+		h.intercepted.ServeHTTP(w, r)
+		return
+	}
+	h.next.ServeHTTP(w, r)
+}
+
+// serveProtocol serves calls that reach it through CORS, preflight requests it didn't answer are passed to next
+func (h *handler) serveProtocol(w http.ResponseWriter, r *http.Request) {
+	serve, ok := h.protocol(r)
+	if !ok {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+	if len(w.Header().Get("Access-Control-Allow-Origin")) > 0 {
+		w.Header().Add("Access-Control-Expose-Headers", exposedHeaders)
+	}
+	serve(w, r)
+}
+
+// protocol returns the function that serves r if it's a call of an enabled protocol
+func (h *handler) protocol(r *http.Request) (http.HandlerFunc, bool) {
+	if r.Method != http.MethodPost {
+		return nil, false
+	}
+	contentType := strings.ToLower(r.Header.Get("Content-Type"))
+	switch {
+	case h.options.GRPCWeb && strings.HasPrefix(contentType, grpcWebTextContentType):
+		return func(w http.ResponseWriter, r *http.Request) { h.serveGRPCWeb(w, r, true) }, true
+	case h.options.GRPCWeb && strings.HasPrefix(contentType, grpcWebContentType):
+		return func(w http.ResponseWriter, r *http.Request) { h.serveGRPCWeb(w, r, false) }, true
+	case h.options.Connect && h.isMethod(r.URL.Path):
+		if codec, ok := connectCodec(contentType); ok {
+			return func(w http.ResponseWriter, r *http.Request) {
+				h.serveConnect(w, r, codec, strings.HasPrefix(contentType, connectStreamPrefix))
+			}, true
+		}
+	}
+	return nil, false
+}
+
+func (h *handler) maxRecvMsgSize() int {
+	if h.options.MaxRecvMsgSize > 0 {
+		return h.options.MaxRecvMsgSize
+	}
+	return DefaultMaxRecvMsgSize
+}
+
+// isMethod checks if path is "/package.Service/Method" of a method registered on the gRPC server
+func (h *handler) isMethod(path string) bool {
+	h.methodsOnce.Do(func() {
+		h.methods = make(map[string]struct{})
+		for service, info := range h.srv.GetServiceInfo() {
+			for _, method := range info.Methods {
+				h.methods["/"+service+"/"+method.Name] = struct{}{}
+			}
+		}
+	})
+	_, ok := h.methods[path]
+	return ok
+}
+
+func (h *handler) isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		len(r.Header.Get("Access-Control-Request-Method")) > 0 &&
+		h.isMethod(r.URL.Path)
+}
+
+// grpcRequest converts r to a request that grpc.Server.ServeHTTP accepts
+func grpcRequest(r *http.Request) *http.Request {
+	req := r.Clone(r.Context())
+	req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2.0"
+	req.Header.Set("Content-Type", grpcContentType)
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	return req
+}
@@ -0,0 +1,259 @@
+package grpcweb
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	demopackage "github.com/go-masonry/mortar/http/server/proto"
+	"github.com/go-masonry/mortar/interfaces/http/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type demoServer struct {
+	demopackage.UnimplementedDemoServer
+}
+
+func (demoServer) Ping(ctx context.Context, req *demopackage.PingRequest) (*demopackage.PongResponse, error) {
+	if req.GetIn() == "fail" {
+		st, _ := status.New(codes.NotFound, "no pong for you").WithDetails(&errdetails.ErrorInfo{Reason: "NO_PONG"})
+		return nil, st.Err()
+	}
+	grpc.SetTrailer(ctx, metadata.Pairs("pong-count", "1"))
+	return &demopackage.PongResponse{Out: "pong " + req.GetIn()}, nil
+}
+
+func newTestServer(t *testing.T, options server.GRPCWebOptions) *httptest.Server {
+	srv := grpc.NewServer()
+	demopackage.RegisterDemoServer(srv, demoServer{})
+	grpc_health_v1.RegisterHealthServer(srv, health.NewServer())
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	testServer := httptest.NewServer(Wrap(srv, next, options))
+	t.Cleanup(testServer.Close)
+	return testServer
+}
+
+func post(t *testing.T, url, contentType string, body []byte, headers ...string) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func marshal(t *testing.T, message proto.Message) []byte {
+	data, err := proto.Marshal(message)
+	require.NoError(t, err)
+	return data
+}
+
+func readFrames(t *testing.T, body io.Reader) (flags []byte, payloads [][]byte) {
+	for {
+		flag, payload, err := readFrame(body, DefaultMaxRecvMsgSize)
+		if err == io.EOF {
+			return
+		}
+		require.NoError(t, err)
+		flags = append(flags, flag)
+		payloads = append(payloads, payload)
+	}
+}
+
+func TestGRPCWeb(t *testing.T) {
+	testServer := newTestServer(t, server.GRPCWebOptions{GRPCWeb: true})
+	resp := post(t, testServer.URL+"/demo.Demo/Ping", "application/grpc-web+proto",
+		envelope(0, marshal(t, &demopackage.PingRequest{In: "web"})), "X-Grpc-Web", "1")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/grpc-web+proto", resp.Header.Get("Content-Type"))
+	flags, payloads := readFrames(t, resp.Body)
+	require.Equal(t, []byte{0, trailerFlag}, flags)
+	pong := new(demopackage.PongResponse)
+	require.NoError(t, proto.Unmarshal(payloads[0], pong))
+	assert.Equal(t, "pong web", pong.GetOut())
+	assert.Contains(t, string(payloads[1]), "grpc-status: 0\r\n")
+	assert.Contains(t, string(payloads[1]), "pong-count: 1\r\n")
+}
+
+func TestGRPCWebText(t *testing.T) {
+	testServer := newTestServer(t, server.GRPCWebOptions{GRPCWeb: true})
+	body := base64.StdEncoding.EncodeToString(envelope(0, marshal(t, &demopackage.PingRequest{In: "fail"})))
+	resp := post(t, testServer.URL+"/demo.Demo/Ping", "application/grpc-web-text", []byte(body))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/grpc-web-text+proto", resp.Header.Get("Content-Type"))
+	encoded, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	decoded, err := decodeText(bytes.NewReader(encoded), DefaultMaxRecvMsgSize)
+	require.NoError(t, err)
+	flags, payloads := readFrames(t, bytes.NewReader(decoded))
+	require.Equal(t, []byte{trailerFlag}, flags)
+	assert.Contains(t, string(payloads[0]), "grpc-status: 5\r\n")
+	assert.Contains(t, string(payloads[0]), "grpc-message: no pong for you\r\n")
+}
+
+func TestConnectUnary(t *testing.T) {
+	testServer := newTestServer(t, server.GRPCWebOptions{Connect: true})
+	resp := post(t, testServer.URL+"/demo.Demo/Ping", "application/proto",
+		marshal(t, &demopackage.PingRequest{In: "proto"}), "Connect-Protocol-Version", "1", "Connect-Timeout-Ms", "1000")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Trailer-Pong-Count"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	pong := new(demopackage.PongResponse)
+	require.NoError(t, proto.Unmarshal(body, pong))
+	assert.Equal(t, "pong proto", pong.GetOut())
+
+	resp = post(t, testServer.URL+"/demo.Demo/Ping", "application/json", []byte(`{"in":"json"}`))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"out":"pong json"}`, string(body))
+}
+
+func TestConnectUnaryError(t *testing.T) {
+	testServer := newTestServer(t, server.GRPCWebOptions{Connect: true})
+	resp := post(t, testServer.URL+"/demo.Demo/Ping", "application/json", []byte(`{"in":"fail"}`))
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	var connectErr connectError
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&connectErr))
+	assert.Equal(t, "not_found", connectErr.Code)
+	assert.Equal(t, "no pong for you", connectErr.Message)
+	require.Len(t, connectErr.Details, 1)
+	assert.Equal(t, "google.rpc.ErrorInfo", connectErr.Details[0].Type)
+	value, err := base64.RawStdEncoding.DecodeString(connectErr.Details[0].Value)
+	require.NoError(t, err)
+	info := new(errdetails.ErrorInfo)
+	require.NoError(t, proto.Unmarshal(value, info))
+	assert.Equal(t, "NO_PONG", info.GetReason())
+
+	resp = post(t, testServer.URL+"/demo.Demo/Ping", "application/proto", nil, "Content-Encoding", "br")
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+}
+
+func TestConnectStream(t *testing.T) {
+	testServer := newTestServer(t, server.GRPCWebOptions{Connect: true})
+	// Watch never ends by itself, the timeout ends it and health server reports it as canceled
+	resp := post(t, testServer.URL+"/grpc.health.v1.Health/Watch", "application/connect+json",
+		envelope(0, []byte(`{"service":""}`)), "Connect-Timeout-Ms", "200")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/connect+json", resp.Header.Get("Content-Type"))
+	flags, payloads := readFrames(t, resp.Body)
+	require.Equal(t, []byte{0, endStreamFlag}, flags)
+	assert.JSONEq(t, `{"status":"SERVING"}`, string(payloads[0]))
+	var end connectEndStream
+	require.NoError(t, json.Unmarshal(payloads[1], &end))
+	require.NotNil(t, end.Error)
+	assert.Equal(t, "canceled", end.Error.Code)
+}
+
+func TestMessageSizeLimit(t *testing.T) {
+	testServer := newTestServer(t, server.GRPCWebOptions{GRPCWeb: true, Connect: true, MaxRecvMsgSize: 1024})
+	oversizedPrefix := []byte{0, 0xff, 0xff, 0xff, 0xff, '{', '}'}
+
+	resp := post(t, testServer.URL+"/grpc.health.v1.Health/Watch", "application/connect+json", oversizedPrefix)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	flags, payloads := readFrames(t, resp.Body)
+	require.Equal(t, []byte{endStreamFlag}, flags)
+	var end connectEndStream
+	require.NoError(t, json.Unmarshal(payloads[0], &end))
+	require.NotNil(t, end.Error)
+	assert.Equal(t, "resource_exhausted", end.Error.Code)
+
+	resp = post(t, testServer.URL+"/demo.Demo/Ping", "application/grpc-web+proto", oversizedPrefix)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	flags, payloads = readFrames(t, resp.Body)
+	require.Equal(t, []byte{trailerFlag}, flags)
+	assert.Contains(t, string(payloads[0]), "grpc-status: 8\r\n")
+
+	large := marshal(t, &demopackage.PingRequest{In: strings.Repeat("a", 2048)})
+	resp = post(t, testServer.URL+"/demo.Demo/Ping", "application/proto", large)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	var connectErr connectError
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&connectErr))
+	assert.Equal(t, "resource_exhausted", connectErr.Code)
+
+	resp = post(t, testServer.URL+"/demo.Demo/Ping", "application/grpc-web-text", []byte(base64.StdEncoding.EncodeToString(envelope(0, large))))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "8", resp.Header.Get("Grpc-Status"))
+}
+
+func TestDisabledProtocols(t *testing.T) {
+	testServer := newTestServer(t, server.GRPCWebOptions{GRPCWeb: true})
+	resp := post(t, testServer.URL+"/demo.Demo/Ping", "application/json", []byte(`{"in":"json"}`))
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+
+	testServer = newTestServer(t, server.GRPCWebOptions{Connect: true})
+	resp = post(t, testServer.URL+"/demo.Demo/Ping", "application/grpc-web+proto", nil)
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+	resp = post(t, testServer.URL+"/v1/demo/ping", "application/json", []byte(`{"in":"json"}`))
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode, "only gRPC methods are translated")
+}
+
+func TestCORS(t *testing.T) {
+	// answers preflight requests and allows a single origin, the same way the CORS interceptor of the gateway does
+	cors := func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowed := r.Header.Get("Origin") == "https://app.example.com"
+			if allowed {
+				w.Header().Set("Access-Control-Allow-Origin", r.Header.Get("Origin"))
+				w.Header().Set("Access-Control-Expose-Headers", "x-request-id")
+			}
+			if r.Method == http.MethodOptions && len(r.Header.Get("Access-Control-Request-Method")) > 0 {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			handler.ServeHTTP(w, r)
+		})
+	}
+	testServer := newTestServer(t, server.GRPCWebOptions{GRPCWeb: true, CORS: cors})
+	req, err := http.NewRequest(http.MethodOptions, testServer.URL+"/demo.Demo/Ping", nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode, "preflight of a gRPC method is answered by the interceptors")
+	assert.Equal(t, "https://app.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+
+	resp = post(t, testServer.URL+"/demo.Demo/Ping", "application/grpc-web+proto",
+		envelope(0, marshal(t, &demopackage.PingRequest{In: "web"})), "Origin", "https://evil.example.com")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, resp.Header.Values("Access-Control-Expose-Headers"))
+	resp = post(t, testServer.URL+"/demo.Demo/Ping", "application/grpc-web+proto",
+		envelope(0, marshal(t, &demopackage.PingRequest{In: "web"})), "Origin", "https://app.example.com")
+	assert.Equal(t, "https://app.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, []string{"x-request-id", exposedHeaders}, resp.Header.Values("Access-Control-Expose-Headers"))
+
+	req, err = http.NewRequest(http.MethodOptions, testServer.URL+"/v1/demo/ping", nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode, "other preflight requests are passed to next")
+}
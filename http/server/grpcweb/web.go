@@ -0,0 +1,117 @@
+package grpcweb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serveGRPCWeb gRPC-Web messages are the same as gRPC ones, trailers are sent as the last message
+func (h *handler) serveGRPCWeb(w http.ResponseWriter, r *http.Request, text bool) {
+	req := grpcRequest(r)
+	contentType := grpcWebContentType + "+proto"
+	if text {
+		body, err := decodeText(r.Body, h.maxRecvMsgSize()+frameHeaderLength)
+		if st, ok := status.FromError(err); ok && st.Code() == codes.ResourceExhausted {
+			writeGRPCWebFailure(w, grpcWebTextContentType+"+proto", st)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		contentType = grpcWebTextContentType + "+proto"
+	}
+	rw := newResponseWriter(&grpcWebProtocol{w: w, contentType: contentType, text: text})
+	h.srv.ServeHTTP(rw, req)
+	rw.finish()
+}
+
+type grpcWebProtocol struct {
+	w           http.ResponseWriter
+	contentType string
+	text        bool
+	pending     bytes.Buffer
+}
+
+func (g *grpcWebProtocol) writeHeader(header http.Header) {
+	for key, values := range header {
+		g.w.Header()[key] = values
+	}
+	g.w.Header().Set("Content-Type", g.contentType)
+	g.w.WriteHeader(http.StatusOK)
+}
+
+func (g *grpcWebProtocol) writeData(p []byte) {
+	if g.text { // base64 is encoded in chunks, each chunk is padded separately
+		g.pending.Write(p)
+		return
+	}
+	g.w.Write(p)
+}
+
+func (g *grpcWebProtocol) flush() {
+	if g.text && g.pending.Len() > 0 {
+		io.WriteString(g.w, base64.StdEncoding.EncodeToString(g.pending.Bytes()))
+		g.pending.Reset()
+	}
+	if flusher, ok := g.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (g *grpcWebProtocol) finish(trailer http.Header) {
+	var lines strings.Builder
+	for key, values := range trailer {
+		for _, value := range values {
+			fmt.Fprintf(&lines, "%s: %s\r\n", strings.ToLower(key), value)
+		}
+	}
+	g.writeData(envelope(trailerFlag, []byte(lines.String())))
+	g.flush()
+}
+
+// writeGRPCWebFailure writes a response that has only headers, gRPC status is one of them
+func writeGRPCWebFailure(w http.ResponseWriter, contentType string, st *status.Status) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set(grpcStatusHeader, strconv.Itoa(int(st.Code())))
+	w.Header().Set(grpcMessageHeader, url.PathEscape(st.Message()))
+	w.WriteHeader(http.StatusOK)
+}
+
+// decodeText decodes gRPC-Web text body, clients can send it as several padded base64 chunks.
+//
+// Bodies that decode to more than maxSize bytes are rejected with codes.ResourceExhausted
+func decodeText(body io.Reader, maxSize int) ([]byte, error) {
+	// padding of every chunk and whitespace between them can make a valid body longer than its encoded length
+	encoded, err := readAll(body, 2*base64.StdEncoding.EncodedLen(maxSize))
+	if err != nil {
+		return nil, err
+	}
+	encoded = bytes.Join(bytes.Fields(encoded), nil)
+	if len(encoded)%4 != 0 {
+		return nil, fmt.Errorf("invalid gRPC-Web text body length %d", len(encoded))
+	}
+	decoded := make([]byte, 0, base64.StdEncoding.DecodedLen(len(encoded)))
+	quantum := make([]byte, 3)
+	for start := 0; start < len(encoded); start += 4 {
+		n, err := base64.StdEncoding.Decode(quantum, encoded[start:start+4])
+		if err != nil {
+			return nil, fmt.Errorf("invalid gRPC-Web text body, %w", err)
+		}
+		decoded = append(decoded, quantum[:n]...)
+		if len(decoded) > maxSize {
+			return nil, tooLarge(int64(base64.StdEncoding.DecodedLen(len(encoded))), maxSize)
+		}
+	}
+	return decoded, nil
+}
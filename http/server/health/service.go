@@ -3,12 +3,20 @@ package health
 import (
 	"context"
 
+	healthInt "github.com/go-masonry/mortar/interfaces/health"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// NotReadyReason is the ErrorInfo reason of a failed readiness probe, metadata holds failed check names and errors
+const NotReadyReason = "NOT_READY"
+
 type healthService struct {
 	UnimplementedHealthServer
+	registry healthInt.Registry
 }
 
 // RegisterInternalGRPCGatewayHandler grpc-gateway health handler
@@ -21,11 +29,45 @@ func RegisterInternalHealthService(srv *grpc.Server) {
 	RegisterHealthServer(srv, ImplementedHealthService())
 }
 
+// RegisterInternalHealthServiceWithRegistry grpc server health api registration, Check runs the readiness probe of the registry
+func RegisterInternalHealthServiceWithRegistry(registry healthInt.Registry) func(srv *grpc.Server) {
+	return func(srv *grpc.Server) {
+		RegisterHealthServer(srv, ImplementedHealthServiceWithRegistry(registry))
+	}
+}
+
 // ImplementedHealthService internal health service
 func ImplementedHealthService() HealthServer {
 	return &healthService{}
 }
 
-func (*healthService) Check(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error) {
-	return new(HealthCheckResponse), nil
+// ImplementedHealthServiceWithRegistry internal health service that fails with codes.Unavailable if readiness probe fails.
+// Failed checks are reported as errdetails.ErrorInfo metadata
+func ImplementedHealthServiceWithRegistry(registry healthInt.Registry) HealthServer {
+	return &healthService{registry: registry}
+}
+
+func (h *healthService) Check(ctx context.Context, _ *HealthCheckRequest) (*HealthCheckResponse, error) {
+	if h.registry == nil {
+		return new(HealthCheckResponse), nil
+	}
+	report := h.registry.Run(ctx, healthInt.Readiness)
+	if report.Status != healthInt.Fail {
+		return new(HealthCheckResponse), nil
+	}
+	failed := make(map[string]string)
+	for _, check := range report.Checks {
+		if check.Status == healthInt.Fail {
+			failed[check.Name] = check.Error
+		}
+	}
+	st, err := status.New(codes.Unavailable, "service is not ready").WithDetails(&errdetails.ErrorInfo{
+		Reason:   NotReadyReason,
+		Domain:   "mortar.health.v1",
+		Metadata: failed,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, "service is not ready")
+	}
+	return nil, st.Err()
 }
@@ -0,0 +1,174 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	healthInt "github.com/go-masonry/mortar/interfaces/health"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpchealth "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultStandardHealthInterval is how often StandardHealth runs the health probes
+const DefaultStandardHealthInterval = 5 * time.Second
+
+// StandardHealth serves the standard grpc.health.v1 protocol, statuses are fed from a health.Registry:
+//   - "" (the server) and every registered gRPC service follow the readiness probe
+//   - "live", "ready" and "startup" follow their probes
+//   - every check name follows its own result
+//
+// Without a registry everything is SERVING until Shutdown.
+type StandardHealth struct {
+	sync.Mutex
+	server   *grpchealth.Server
+	registry healthInt.Registry
+	interval time.Duration
+	services []string
+	stop     chan struct{}
+	stopped  bool
+}
+
+// NewStandardHealth creates StandardHealth, registry can be nil
+func NewStandardHealth(registry healthInt.Registry, interval time.Duration) *StandardHealth {
+	if interval <= 0 {
+		interval = DefaultStandardHealthInterval
+	}
+	return &StandardHealth{
+		server:   grpchealth.NewServer(),
+		registry: registry,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Register registers grpc.health.v1 service, unless it was already registered by someone else
+func (s *StandardHealth) Register(srv *grpc.Server) {
+	services := srv.GetServiceInfo()
+	if _, registered := services[grpcHealthServiceName()]; registered {
+		return
+	}
+	grpc_health_v1.RegisterHealthServer(srv, &stoppableHealthServer{Server: s.server, stop: s.stop})
+	s.Lock()
+	defer s.Unlock()
+	for name := range srv.GetServiceInfo() {
+		s.services = append(s.services, name)
+	}
+}
+
+// Start updates statuses and keeps updating them every interval until Shutdown
+func (s *StandardHealth) Start(ctx context.Context) error {
+	s.Update(ctx)
+	if s.registry != nil {
+		go s.poll()
+	}
+	return nil
+}
+
+// Update runs the probes once and updates the statuses, watchers are notified only on changes
+func (s *StandardHealth) Update(ctx context.Context) {
+	serving := grpc_health_v1.HealthCheckResponse_SERVING
+	statuses := make(map[string]grpc_health_v1.HealthCheckResponse_ServingStatus)
+	if s.registry != nil { // probes can take a while, don't block Shutdown
+		for _, probe := range []healthInt.Probe{healthInt.Liveness, healthInt.Startup, healthInt.Readiness} {
+			report := s.registry.Run(ctx, probe)
+			for _, check := range report.Checks {
+				statuses[check.Name] = servingStatus(check.Status)
+			}
+			statuses[string(probe)] = servingStatus(report.Status)
+			if probe == healthInt.Readiness {
+				serving = servingStatus(report.Status)
+			}
+		}
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.stopped {
+		return
+	}
+	for name, status := range statuses {
+		s.server.SetServingStatus(name, status)
+	}
+	s.server.SetServingStatus("", serving)
+	for _, service := range s.services {
+		s.server.SetServingStatus(service, serving)
+	}
+}
+
+// Shutdown sets every status to NOT_SERVING and stops updating them, so load balancers stop sending new requests.
+// Watch streams are ended once NOT_SERVING is sent, otherwise they would keep a graceful stop waiting.
+func (s *StandardHealth) Shutdown(context.Context) {
+	s.Lock()
+	defer s.Unlock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.stop)
+		s.server.Shutdown()
+	}
+}
+
+func (s *StandardHealth) poll() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Update(context.Background())
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// stoppableHealthServer ends Watch streams once StandardHealth is shut down
+type stoppableHealthServer struct {
+	*grpchealth.Server
+	stop <-chan struct{}
+}
+
+func (h *stoppableHealthServer) Watch(in *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-h.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	err := h.Server.Watch(in, &watchStream{Health_WatchServer: stream, ctx: ctx})
+	select {
+	case <-h.stop:
+		// the last update might have been dropped when the stream was canceled
+		if sendErr := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING}); sendErr != nil {
+			return sendErr
+		}
+		return status.Error(codes.Unavailable, "health service is shutting down")
+	default:
+		return err
+	}
+}
+
+// watchStream replaces the stream context, so Watch can be canceled
+type watchStream struct {
+	grpc_health_v1.Health_WatchServer
+	ctx context.Context
+}
+
+func (w *watchStream) Context() context.Context {
+	return w.ctx
+}
+
+func servingStatus(status healthInt.Status) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if status == healthInt.Fail {
+		return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}
+	return grpc_health_v1.HealthCheckResponse_SERVING
+}
+
+func grpcHealthServiceName() string {
+	return grpc_health_v1.Health_ServiceDesc.ServiceName
+}
@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	healthImpl "github.com/go-masonry/mortar/health"
+	healthInt "github.com/go-masonry/mortar/interfaces/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestStandardHealth(t *testing.T) {
+	var failing atomic.Bool
+	registry := healthImpl.NewRegistry()
+	require.NoError(t, registry.Register(healthInt.Check{Name: "db", Critical: true, Check: func(context.Context) error {
+		if failing.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	}}))
+	standardHealth := NewStandardHealth(registry, time.Hour)
+	srv := grpc.NewServer()
+	RegisterInternalHealthService(srv)
+	standardHealth.Register(srv)
+	standardHealth.Register(srv) // registered only once
+	listener := bufconn.Listen(1 << 20)
+	go srv.Serve(listener)
+	defer srv.Stop()
+	require.NoError(t, standardHealth.Start(context.Background()))
+
+	conn, err := grpc.Dial("bufnet", grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return listener.Dial()
+	}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := grpc_health_v1.NewHealthClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, service := range []string{"", "ready", "db", "mortar.health.v1.Health"} {
+		response, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: service})
+		require.NoError(t, err, service)
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.GetStatus(), service)
+	}
+
+	watch, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: ""})
+	require.NoError(t, err)
+	response, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.GetStatus())
+	failing.Store(true)
+	standardHealth.Update(ctx)
+	response, err = watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, response.GetStatus())
+	response, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "live"})
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.GetStatus(), "db is not a liveness check")
+
+	failing.Store(false)
+	standardHealth.Update(ctx)
+	response, err = watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.GetStatus())
+	standardHealth.Shutdown(ctx)
+	response, err = watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, response.GetStatus())
+	standardHealth.Update(ctx) // ignored after shutdown
+	response, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "ready"})
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, response.GetStatus())
+}
+
+func TestStandardHealthShutdownEndsWatch(t *testing.T) {
+	standardHealth := NewStandardHealth(nil, time.Hour)
+	srv := grpc.NewServer()
+	standardHealth.Register(srv)
+	listener := bufconn.Listen(1 << 20)
+	go srv.Serve(listener)
+	defer srv.Stop()
+	require.NoError(t, standardHealth.Start(context.Background()))
+
+	conn, err := grpc.Dial("bufnet", grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return listener.Dial()
+	}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	watch, err := grpc_health_v1.NewHealthClient(conn).Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: ""})
+	require.NoError(t, err)
+	response, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, response.GetStatus())
+
+	standardHealth.Shutdown(ctx)
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(3 * time.Second):
+		t.Fatal("graceful stop is blocked by the watching client")
+	}
+	var last grpc_health_v1.HealthCheckResponse_ServingStatus
+	for {
+		response, err = watch.Recv()
+		if err != nil {
+			break
+		}
+		last = response.GetStatus()
+	}
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, last)
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+)
+
+// inFlight counts calls that are currently being served
+type inFlight struct {
+	grpc atomic.Int64
+	rest atomic.Int64
+}
+
+func (f *inFlight) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		f.grpc.Add(1)
+		defer f.grpc.Add(-1)
+		return handler(ctx, req)
+	}
+}
+
+func (f *inFlight) streamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		f.grpc.Add(1)
+		defer f.grpc.Add(-1)
+		return handler(srv, ss)
+	}
+}
+
+func (f *inFlight) httpHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.rest.Add(1)
+		defer f.rest.Add(-1)
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,117 @@
+package inherit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultHandoffTimeout is how long a process started by Handoff usually has to become ready
+const DefaultHandoffTimeout = time.Minute
+
+type fileListener interface {
+	File() (*os.File, error)
+}
+
+// Handoff starts a new instance of this executable with the same arguments and environment,
+// passing it every listener returned by Listen. Both processes accept calls on the same sockets until the new one
+// calls Ready, then Handoff returns and the caller should stop gracefully.
+//
+// If the new process exits before it's ready or ctx is done first, Handoff returns an error and the caller
+// should keep serving, a process that isn't ready by then is killed.
+func (l *Listeners) Handoff(ctx context.Context) (*os.Process, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	cmd, ready, err := l.handoffCommand(executable)
+	if err != nil {
+		return nil, err
+	}
+	defer ready.Close()
+	readyResult := make(chan error, 1)
+	go func() {
+		_, err := ready.Read(make([]byte, 1)) // EOF once the process exits or closes it without calling Ready
+		readyResult <- err
+	}()
+	select {
+	case err = <-readyResult:
+		if err == nil {
+			return cmd.Process, nil
+		}
+		exited := make(chan error, 1)
+		go func() {
+			exited <- cmd.Wait()
+		}()
+		select {
+		case err = <-exited:
+			return nil, fmt.Errorf("process %d exited before it was ready, %v", cmd.Process.Pid, err)
+		case <-ctx.Done():
+		}
+	case <-ctx.Done():
+		go cmd.Wait() // reaps the process once it's killed
+	}
+	cmd.Process.Kill()
+	return nil, fmt.Errorf("process %d wasn't ready in time, %w", cmd.Process.Pid, ctx.Err())
+}
+
+// handoffCommand starts the new process, it reports readiness to the returned pipe
+func (l *Listeners) handoffCommand(executable string) (*exec.Cmd, *os.File, error) {
+	l.Lock()
+	defer l.Unlock()
+	var files []*os.File
+	defer func() {
+		for _, file := range files {
+			file.Close() // the child has its own copies
+		}
+	}()
+	var names []string
+	for _, used := range l.used {
+		withFile, ok := used.listener.(fileListener)
+		if !ok {
+			return nil, nil, fmt.Errorf("listener [%s] can't be passed to another process", used.name)
+		}
+		file, err := withFile.File()
+		if err != nil {
+			return nil, nil, fmt.Errorf("listener [%s] can't be passed to another process, %w", used.name, err)
+		}
+		files = append(files, file)
+		names = append(names, used.name)
+	}
+	ready, readyWriter, err := os.Pipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	files = append(files, readyWriter)
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = files // file descriptors 3, 4, ...
+	cmd.Env = append(handoffEnvironment(),
+		EnvListenFDs+"="+strconv.Itoa(len(names)),
+		EnvListenFDNames+"="+strings.Join(names, ":"),
+		EnvHandoffReadyFD+"="+strconv.Itoa(listenFDsStart+len(names)),
+	)
+	if err = cmd.Start(); err != nil {
+		ready.Close()
+		return nil, nil, err
+	}
+	return cmd, ready, nil
+}
+
+// handoffEnvironment returns the environment of this process without the listen variables
+func handoffEnvironment() (output []string) {
+	for _, variable := range os.Environ() {
+		if strings.HasPrefix(variable, EnvListenFDs+"=") ||
+			strings.HasPrefix(variable, EnvListenPID+"=") ||
+			strings.HasPrefix(variable, EnvListenFDNames+"=") ||
+			strings.HasPrefix(variable, EnvHandoffReadyFD+"=") {
+			continue
+		}
+		output = append(output, variable)
+	}
+	return
+}
@@ -0,0 +1,186 @@
+// Package inherit lets a web service start from listeners it inherited and hand its listeners to a new process.
+//
+// Listeners are inherited using the systemd socket activation protocol, LISTEN_FDS listeners start at file descriptor 3
+// and can be named with LISTEN_FDNAMES. systemd also sets LISTEN_PID, a process started by Handoff doesn't have it.
+// A process started by Handoff reports it's ready by calling Ready, until then the previous process keeps serving.
+package inherit
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// EnvListenFDs number of inherited listeners
+	EnvListenFDs = "LISTEN_FDS"
+	// EnvListenPID pid of the process the listeners were passed to
+	EnvListenPID = "LISTEN_PID"
+	// EnvListenFDNames colon separated names of the inherited listeners
+	EnvListenFDNames = "LISTEN_FDNAMES"
+	// EnvHandoffReadyFD file descriptor a process started by Handoff writes to once it's ready, see Ready
+	EnvHandoffReadyFD = "MORTAR_HANDOFF_READY_FD"
+
+	listenFDsStart = 3
+)
+
+// Names of the listeners used by mortar, name your systemd sockets with FileDescriptorName accordingly
+const (
+	GRPCListenerName         = "grpc"
+	InternalGRPCListenerName = "grpc-internal"
+	ExternalRESTListenerName = "rest-external"
+	InternalRESTListenerName = "rest-internal"
+)
+
+type namedListener struct {
+	name     string
+	listener net.Listener
+}
+
+// Listeners holds inherited listeners and every listener created or taken by Listen, it's safe for concurrent use
+type Listeners struct {
+	sync.Mutex
+	inherited []namedListener
+	used      []namedListener
+	ready     *os.File
+}
+
+// FromEnvironment creates Listeners from the inherited ones, if there are any.
+// Environment variables are removed, so they will not be passed to child processes.
+func FromEnvironment() (*Listeners, error) {
+	defer func() {
+		os.Unsetenv(EnvListenFDs)
+		os.Unsetenv(EnvListenPID)
+		os.Unsetenv(EnvListenFDNames)
+		os.Unsetenv(EnvHandoffReadyFD)
+	}()
+	listeners := new(Listeners)
+	if fd, err := strconv.Atoi(os.Getenv(EnvHandoffReadyFD)); err == nil && fd >= listenFDsStart {
+		listeners.ready = os.NewFile(uintptr(fd), "handoff-ready")
+	}
+	count, err := inheritedCount()
+	if err != nil || count == 0 {
+		return listeners, err
+	}
+	names := strings.Split(os.Getenv(EnvListenFDNames), ":")
+	for i := 0; i < count; i++ {
+		name := "unknown"
+		if i < len(names) && len(names[i]) > 0 {
+			name = names[i]
+		}
+		file := os.NewFile(uintptr(listenFDsStart+i), name)
+		listener, err := net.FileListener(file) // duplicates the descriptor
+		file.Close()
+		if err != nil {
+			listeners.Close()
+			return nil, fmt.Errorf("inherited file descriptor %d [%s] is not a listener, %w", listenFDsStart+i, name, err)
+		}
+		listeners.inherited = append(listeners.inherited, namedListener{name: name, listener: listener})
+	}
+	return listeners, nil
+}
+
+func inheritedCount() (int, error) {
+	fds := os.Getenv(EnvListenFDs)
+	if len(fds) == 0 {
+		return 0, nil
+	}
+	if pid := os.Getenv(EnvListenPID); len(pid) > 0 && pid != strconv.Itoa(os.Getpid()) {
+		return 0, nil // meant for another process
+	}
+	count, err := strconv.Atoi(fds)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("%s=%s is not a valid number of listeners", EnvListenFDs, fds)
+	}
+	return count, nil
+}
+
+// Inherited returns the number of inherited listeners that weren't taken yet
+func (l *Listeners) Inherited() int {
+	l.Lock()
+	defer l.Unlock()
+	return len(l.inherited)
+}
+
+// Listen returns an inherited listener with this name or port of addr, otherwise it creates a new tcp listener.
+// Either way the listener is passed to the next process on Handoff.
+func (l *Listeners) Listen(name, addr string) (listener net.Listener, err error) {
+	l.Lock()
+	defer l.Unlock()
+	if listener = l.take(name, addr); listener == nil {
+		if listener, err = net.Listen("tcp", addr); err != nil {
+			return nil, err
+		}
+	}
+	l.used = append(l.used, namedListener{name: name, listener: listener})
+	return listener, nil
+}
+
+func (l *Listeners) take(name, addr string) net.Listener {
+	match := -1
+	for i, inherited := range l.inherited {
+		if inherited.name == name {
+			match = i
+			break
+		}
+		if port := extractPort(addr); port > 0 && port == extractPort(inherited.listener.Addr().String()) && match < 0 {
+			match = i
+		}
+	}
+	if match < 0 {
+		return nil
+	}
+	listener := l.inherited[match].listener
+	l.inherited = append(l.inherited[:match], l.inherited[match+1:]...)
+	return listener
+}
+
+// Ready tells the process that started this one with Handoff that it can stop, it's a no-op otherwise.
+// Call it once the service accepts calls on its listeners.
+func (l *Listeners) Ready() error {
+	l.Lock()
+	defer l.Unlock()
+	if l.ready == nil {
+		return nil
+	}
+	_, err := l.ready.Write([]byte{1})
+	if closeErr := l.ready.Close(); err == nil {
+		err = closeErr
+	}
+	l.ready = nil
+	return err
+}
+
+// Close closes inherited listeners that weren't taken, listeners returned by Listen are closed by their servers.
+// If Ready wasn't called, the process that started this one with Handoff keeps serving.
+func (l *Listeners) Close() error {
+	l.Lock()
+	defer l.Unlock()
+	var errs []string
+	if l.ready != nil {
+		l.ready.Close()
+		l.ready = nil
+	}
+	for _, inherited := range l.inherited {
+		if err := inherited.listener.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	l.inherited = nil
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to close inherited listeners, %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func extractPort(addr string) int {
+	if _, port, err := net.SplitHostPort(addr); err == nil {
+		if number, err := strconv.Atoi(port); err == nil {
+			return number
+		}
+	}
+	return 0
+}
@@ -0,0 +1,142 @@
+package inherit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	helperEnv = "INHERIT_TEST_HELPER"
+	// helperEnv values of processes that are never ready
+	helperFail = "fail"
+	helperHang = "hang"
+)
+
+func TestMain(m *testing.M) {
+	if output := os.Getenv(helperEnv); len(output) > 0 {
+		if err := inheritingProcess(output); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestListenWithoutInheritedListeners(t *testing.T) {
+	t.Setenv(EnvListenFDs, "")
+	listeners, err := FromEnvironment()
+	require.NoError(t, err)
+	assert.Zero(t, listeners.Inherited())
+	listener, err := listeners.Listen(GRPCListenerName, "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	assert.Len(t, listeners.used, 1)
+}
+
+func TestListenersOfAnotherProcess(t *testing.T) {
+	t.Setenv(EnvListenFDs, "1")
+	t.Setenv(EnvListenPID, "1")
+	listeners, err := FromEnvironment()
+	require.NoError(t, err)
+	assert.Zero(t, listeners.Inherited())
+	_, exists := os.LookupEnv(EnvListenFDs)
+	assert.False(t, exists, "environment should be cleaned")
+}
+
+func TestListenMatchesNameThenPort(t *testing.T) {
+	grpcListener := newLocalListener(t)
+	restListener := newLocalListener(t)
+	unusedListener := newLocalListener(t)
+	listeners := &Listeners{inherited: []namedListener{
+		{name: "unknown", listener: restListener},
+		{name: GRPCListenerName, listener: grpcListener},
+		{name: "unknown", listener: unusedListener},
+	}}
+	listener, err := listeners.Listen(GRPCListenerName, "localhost:0")
+	require.NoError(t, err)
+	assert.Same(t, grpcListener, listener)
+	listener, err = listeners.Listen(ExternalRESTListenerName, restListener.Addr().String())
+	require.NoError(t, err)
+	assert.Same(t, restListener, listener)
+	assert.Equal(t, 1, listeners.Inherited())
+	require.NoError(t, listeners.Close())
+	_, err = unusedListener.Accept()
+	assert.ErrorIs(t, err, net.ErrClosed)
+}
+
+func TestHandoff(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "output")
+	t.Setenv(helperEnv, output)
+	listener := newLocalListener(t)
+	listeners := &Listeners{used: []namedListener{{name: GRPCListenerName, listener: listener}}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	process, err := listeners.Handoff(ctx)
+	require.NoError(t, err)
+	state, err := process.Wait()
+	require.NoError(t, err)
+	require.True(t, state.Success(), "process that inherited the listener failed")
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, listener.Addr().String(), string(content))
+}
+
+func TestHandoffProcessFailed(t *testing.T) {
+	t.Setenv(helperEnv, helperFail)
+	listeners := &Listeners{used: []namedListener{{name: GRPCListenerName, listener: newLocalListener(t)}}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := listeners.Handoff(ctx)
+	assert.ErrorContains(t, err, "exited before it was ready, exit status 1")
+}
+
+func TestHandoffTimeout(t *testing.T) {
+	t.Setenv(helperEnv, helperHang)
+	listeners := &Listeners{used: []namedListener{{name: GRPCListenerName, listener: newLocalListener(t)}}}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := listeners.Handoff(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// inheritingProcess runs instead of the tests when this test binary was started by Handoff
+func inheritingProcess(output string) error {
+	listeners, err := FromEnvironment()
+	if err != nil {
+		return err
+	}
+	switch output {
+	case helperFail:
+		return fmt.Errorf("failed to start")
+	case helperHang:
+		time.Sleep(time.Minute)
+	}
+	if listeners.Inherited() != 1 {
+		return fmt.Errorf("expected a single inherited listener, got %d", listeners.Inherited())
+	}
+	listener, err := listeners.Listen(GRPCListenerName, "localhost:0")
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	if err = os.WriteFile(output, []byte(listener.Addr().String()), 0600); err != nil {
+		return err
+	}
+	return listeners.Ready()
+}
+
+func newLocalListener(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	return listener
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package inherit
+
+import "os"
+
+// HandoffSignal is nil, handoff is supported only on unix systems
+var HandoffSignal os.Signal
@@ -0,0 +1,11 @@
+//go:build unix
+
+package inherit
+
+import (
+	"os"
+	"syscall"
+)
+
+// HandoffSignal is the signal that triggers a handoff when it's enabled by configuration
+var HandoffSignal os.Signal = syscall.SIGUSR2
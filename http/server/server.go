@@ -6,7 +6,10 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/go-masonry/mortar/http/server/grpcweb"
 	"github.com/go-masonry/mortar/interfaces/http/server"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
@@ -15,15 +18,20 @@ import (
 type listenerMuxPair struct {
 	m mux
 	l net.Listener
+	t server.WebServerType
+	// grpcWeb REST server serves gRPC-Web and Connect calls with grpc.Server.ServeHTTP
+	grpcWeb bool
 }
 
 type webService struct {
 	sync.Mutex
-	serviceConfig   *webServiceConfig
-	grpcServer      *grpc.Server
-	grpcAddr        string
-	muxAndListeners []*listenerMuxPair
-	close           bool
+	serviceConfig      *webServiceConfig
+	grpcServer         *grpc.Server
+	grpcAddr           string
+	internalGRPCServer *grpc.Server
+	internalGRPCAddr   string
+	muxAndListeners    []*listenerMuxPair
+	close              bool
 }
 
 func newWebService(cfg *webServiceConfig) (instance server.WebService, err error) {
@@ -31,7 +39,9 @@ func newWebService(cfg *webServiceConfig) (instance server.WebService, err error
 		serviceConfig: cfg,
 	}
 	if err = ws.setupGRPC(ws.serviceConfig.grpc); err == nil {
-		err = ws.setupREST(ws.serviceConfig.rest)
+		if err = ws.setupInternalGRPC(ws.serviceConfig.internalGRPC); err == nil {
+			err = ws.setupREST(ws.serviceConfig.rest)
+		}
 	}
 	if err != nil { // make sure to clean, since there might still be open listeners
 		for _, pair := range ws.muxAndListeners {
@@ -62,6 +72,7 @@ func (ws *webService) Run(context.Context) (err error) {
 	return fmt.Errorf("server already closed")
 }
 
+// Stop shuts down all the servers in phases, see server.ShutdownPhase
 func (ws *webService) Stop(ctx context.Context) error {
 	ws.Lock()
 	defer ws.Unlock()
@@ -69,55 +80,134 @@ func (ws *webService) Stop(ctx context.Context) error {
 		return nil // already closed
 	}
 	ws.close = true
+	policy := ws.serviceConfig.shutdown
+	// Not ready
+	start := time.Now()
+	for _, hook := range ws.serviceConfig.stopHooks {
+		hook(ctx)
+	}
+	ws.phaseDone(ctx, server.NotReadyPhase, start)
+	// Drain
+	if policy.DrainDelay > 0 {
+		start = time.Now()
+		timer := time.NewTimer(policy.DrainDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+		ws.phaseDone(ctx, server.DrainPhase, start)
+	}
+	// Graceful
+	start = time.Now()
+	graceCtx := ctx
+	if policy.GracePeriod > 0 {
+		var cancel context.CancelFunc
+		graceCtx, cancel = context.WithTimeout(ctx, policy.GracePeriod)
+		defer cancel()
+	}
+	allClosed := ws.gracefulStop(graceCtx)
+	select {
+	case <-allClosed:
+	case <-graceCtx.Done():
+	}
+	ws.phaseDone(ctx, server.GracefulPhase, start)
+	if graceCtx.Err() == nil {
+		return nil
+	}
+	ws.serviceConfig.logger(ctx, "Graceful shutdown wasn't finished, %v", graceCtx.Err())
+	// Force
+	start = time.Now()
+	ws.forceStop()
+	<-allClosed
+	ws.phaseDone(ctx, server.ForcePhase, start)
+	return ctx.Err()
+}
+
+// gracefulStop stops accepting new calls, returned channel is closed once all in-flight calls are done
+func (ws *webService) gracefulStop(ctx context.Context) <-chan struct{} {
 	var wg sync.WaitGroup
+	// grpc.Server.GracefulStop panics while calls served by grpc.Server.ServeHTTP are running,
+	// gRPC servers wait for REST servers that serve gRPC-Web calls
+	var grpcWeb sync.WaitGroup
+	var grpcWebFailed atomic.Bool
+	for _, listenerAndMux := range ws.muxAndListeners {
+		if listenerAndMux.grpcWeb {
+			grpcWeb.Add(1)
+		}
+	}
 	for _, listenerAndMux := range ws.muxAndListeners {
 		switch s := listenerAndMux.m.(type) {
 		case grpcServerStopper:
 			wg.Add(1)
 			go func(stopper grpcServerStopper, listener net.Listener) {
 				defer wg.Done()
-				stopper.GracefulStop()
+				grpcWeb.Wait()
+				if !grpcWebFailed.Load() { // otherwise forceStop will stop it
+					stopper.GracefulStop()
+				}
 				listener.Close()
 			}(s, listenerAndMux.l)
 		case restServerShutdown:
 			wg.Add(1)
-			go func(stopper restServerShutdown, listener net.Listener) {
+			go func(stopper restServerShutdown, listener net.Listener, translatesGRPC bool) {
 				defer wg.Done()
-				stopper.Shutdown(ctx)
+				err := stopper.Shutdown(ctx)
 				listener.Close()
-			}(s, listenerAndMux.l)
+				if translatesGRPC {
+					if err != nil {
+						grpcWebFailed.Store(true)
+					}
+					grpcWeb.Done()
+				}
+			}(s, listenerAndMux.l, listenerAndMux.grpcWeb)
 		}
 	}
-	var allClosed = make(chan error)
+	allClosed := make(chan struct{})
 	go func() {
 		wg.Wait()
-		allClosed <- nil
+		close(allClosed)
 	}()
-	select {
-	case <-ctx.Done():
-		ws.serviceConfig.logger(ctx, "Graceful shutdown wasn't finished, %v", ctx.Err())
-		return ctx.Err()
-	case err := <-allClosed:
-		return err
+	return allClosed
+}
+
+// forceStop closes all the connections, in-flight calls are canceled
+func (ws *webService) forceStop() {
+	for _, listenerAndMux := range ws.muxAndListeners {
+		switch s := listenerAndMux.m.(type) {
+		case grpcServerStopper:
+			s.Stop()
+		case restServerShutdown:
+			s.Close()
+		}
+	}
+}
+
+func (ws *webService) phaseDone(ctx context.Context, phase server.ShutdownPhase, start time.Time) {
+	report := server.ShutdownPhaseReport{
+		Phase:        phase,
+		Duration:     time.Since(start),
+		GRPCInFlight: ws.serviceConfig.inFlight.grpc.Load(),
+		RESTInFlight: ws.serviceConfig.inFlight.rest.Load(),
+	}
+	ws.serviceConfig.logger(ctx, "Shutdown phase [%s] done after %s, in-flight gRPC calls: %d, in-flight REST calls: %d",
+		report.Phase, report.Duration, report.GRPCInFlight, report.RESTInFlight)
+	for _, observer := range ws.serviceConfig.observers {
+		observer(ctx, report)
 	}
 }
 
 func (ws *webService) Ports() (list []server.ListenInfo) {
 	grpcPort := extractPort(ws.grpcAddr)
-	list = append(list, server.ListenInfo{
-		Address: ws.grpcAddr,
-		Port:    grpcPort,
-		Type:    server.GRPCServer,
-	})
 	for _, pair := range ws.muxAndListeners {
-		port := extractPort(pair.l.Addr().String())
-		if port != grpcPort {
-			list = append(list, server.ListenInfo{
-				Address: pair.l.Addr().String(),
-				Port:    port,
-				Type:    server.RESTServer,
-			})
+		if pair.t == server.RESTServer && extractPort(pair.l.Addr().String()) == grpcPort {
+			continue // REST and gRPC share a listener, report it once
 		}
+		list = append(list, server.ListenInfo{
+			Address: pair.l.Addr().String(),
+			Port:    extractPort(pair.l.Addr().String()),
+			Type:    pair.t,
+		})
 	}
 	return
 }
@@ -126,28 +216,52 @@ func (ws *webService) setupGRPC(cfg *grpcConfig) (err error) {
 	if cfg.registerAPI == nil {
 		err = fmt.Errorf("no GRPC APIs registered, make sure to call 'RegisterGRPCAPIs' when building")
 	} else {
-		// Listener
-		grpcListener := cfg.listener
-		if grpcListener == nil {
-			if grpcListener, err = createListener("tcp", cfg.addr); err != nil {
-				return err
-			}
-		}
-		// Server
-		ws.grpcServer = cfg.server
-		if ws.grpcServer == nil {
-			ws.grpcServer = grpc.NewServer(cfg.options...)
-		}
-		for _, api := range cfg.registerAPI {
-			api(ws.grpcServer)
+		var grpcListener net.Listener
+		if ws.grpcServer, grpcListener, err = createGRPCServer(cfg); err != nil {
+			return err
 		}
 		// save, since this should run first we have no problem with previous values
-		ws.muxAndListeners = append(ws.muxAndListeners, &listenerMuxPair{l: grpcListener, m: ws.grpcServer})
+		ws.muxAndListeners = append(ws.muxAndListeners, &listenerMuxPair{l: grpcListener, m: ws.grpcServer, t: server.GRPCServer})
 		ws.grpcAddr = grpcListener.Addr().String() // we need this later for grpc gateway
 	}
 	return
 }
 
+func (ws *webService) setupInternalGRPC(cfg *grpcConfig) (err error) {
+	if cfg == nil {
+		return nil // internal gRPC server is optional
+	}
+	if cfg.registerAPI == nil {
+		return fmt.Errorf("no internal GRPC APIs registered, make sure to call 'RegisterGRPCAPIs' when building the internal part")
+	}
+	var grpcListener net.Listener
+	if ws.internalGRPCServer, grpcListener, err = createGRPCServer(cfg); err != nil {
+		return err
+	}
+	ws.muxAndListeners = append(ws.muxAndListeners, &listenerMuxPair{l: grpcListener, m: ws.internalGRPCServer, t: server.InternalGRPCServer})
+	ws.internalGRPCAddr = grpcListener.Addr().String() // for grpc gateway of internal REST servers
+	return
+}
+
+func createGRPCServer(cfg *grpcConfig) (grpcServer *grpc.Server, grpcListener net.Listener, err error) {
+	// Listener
+	grpcListener = cfg.listener
+	if grpcListener == nil {
+		if grpcListener, err = createListener("tcp", cfg.addr); err != nil {
+			return nil, nil, err
+		}
+	}
+	// Server
+	grpcServer = cfg.server
+	if grpcServer == nil {
+		grpcServer = grpc.NewServer(cfg.options...)
+	}
+	for _, api := range cfg.registerAPI {
+		api(grpcServer)
+	}
+	return
+}
+
 func (ws *webService) setupREST(restConfigs []*restConfig) (err error) {
 	for _, cfg := range restConfigs {
 		var emptyListener = true // indicate that we have some kind of handler here, grpcgateway or custom handler/handlerfunc
@@ -203,8 +317,12 @@ func (ws *webService) setupREST(restConfigs []*restConfig) (err error) {
 				gwMux = runtime.NewServeMux(cfg.grpcGatewayOptions...)
 			}
 			// register grpc gateway handlers
+			endpoint := ws.grpcAddr
+			if cfg.useInternalGRPC && len(ws.internalGRPCAddr) > 0 {
+				endpoint = ws.internalGRPCAddr
+			}
 			for _, gwHandler := range cfg.grpcGatewayHandlers {
-				if err = gwHandler(gwMux, ws.grpcAddr); err != nil {
+				if err = gwHandler(gwMux, endpoint); err != nil {
 					return err
 				}
 				emptyListener = false
@@ -219,12 +337,23 @@ func (ws *webService) setupREST(restConfigs []*restConfig) (err error) {
 				return fmt.Errorf("grpc Gateway handlers can't be registered, since the provided *http.Server can't handle them")
 			}
 		}
+		// gRPC-Web and Connect
+		if cfg.grpcWeb != nil {
+			grpcServer := ws.grpcServer
+			if cfg.useInternalGRPC && ws.internalGRPCServer != nil {
+				grpcServer = ws.internalGRPCServer
+			}
+			webSrv.Handler = grpcweb.Wrap(grpcServer, webSrv.Handler, *cfg.grpcWeb)
+			emptyListener = false
+		}
 		// check if we have configured anything
 		if emptyListener {
 			return fmt.Errorf("nothing to handle for this address: %s", restListener.Addr())
 		}
+		// count in-flight requests for graceful shutdown
+		webSrv.Handler = ws.serviceConfig.inFlight.httpHandler(webSrv.Handler)
 		// Save
-		ws.muxAndListeners = append(ws.muxAndListeners, &listenerMuxPair{l: restListener, m: webSrv})
+		ws.muxAndListeners = append(ws.muxAndListeners, &listenerMuxPair{l: restListener, m: webSrv, t: server.RESTServer, grpcWeb: cfg.grpcWeb != nil})
 	}
 	return
 }
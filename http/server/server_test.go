@@ -121,6 +121,86 @@ func TestInternalHealthService(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestInternalGRPCServer(t *testing.T) {
+	service, err := Builder().
+		ListenOn("localhost:8888").
+		RegisterGRPCAPIs(registerGrpcAPI).
+		AddInternalGRPCServerConfiguration().
+		ListenOn("localhost:8890").
+		RegisterGRPCAPIs(health.RegisterInternalHealthService).
+		BuildInternalGRPCPart().
+		AddRESTServerConfiguration().
+		ListenOn("localhost:8889").
+		UseInternalGRPCServer().
+		RegisterGRPCGatewayHandlers(health.RegisterInternalGRPCGatewayHandler).
+		BuildRESTPart().
+		Build()
+	require.NoError(t, err)
+	defer service.Stop(context.Background())
+	go service.Run(context.Background())
+	assert.ElementsMatch(t, []server.ListenInfo{
+		{Address: "127.0.0.1:8888", Port: 8888, Type: server.GRPCServer},
+		{Address: "127.0.0.1:8890", Port: 8890, Type: server.InternalGRPCServer},
+		{Address: "127.0.0.1:8889", Port: 8889, Type: server.RESTServer},
+	}, service.Ports())
+	// health is served only by the internal gRPC server
+	for port, expected := range map[string]codes.Code{"8888": codes.Unimplemented, "8890": codes.OK} {
+		conn, err := grpc.Dial("localhost:"+port, grpc.WithInsecure())
+		require.NoError(t, err)
+		_, err = health.NewHealthClient(conn).Check(context.Background(), &health.HealthCheckRequest{})
+		assert.Equal(t, expected, status.Code(err), port)
+		conn.Close()
+	}
+	// gateway of the internal REST server calls the internal gRPC server
+	resp, err := http.Get("http://localhost:8889/health")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestInternalGRPCServerWithoutAPIs(t *testing.T) {
+	_, err := Builder().
+		RegisterGRPCAPIs(registerGrpcAPI).
+		AddInternalGRPCServerConfiguration().
+		BuildInternalGRPCPart().
+		Build()
+	assert.EqualError(t, err, "no internal GRPC APIs registered, make sure to call 'RegisterGRPCAPIs' when building the internal part")
+}
+
+func TestGRPCWeb(t *testing.T) {
+	service, err := Builder().
+		ListenOn("localhost:8888").
+		RegisterGRPCAPIs(registerGrpcAPI).
+		AddRESTServerConfiguration().
+		ListenOn("localhost:8889").
+		EnableGRPCWeb(server.GRPCWebOptions{Connect: true, CORS: func(handler http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+				handler.ServeHTTP(w, r)
+			})
+		}}).
+		AddGRPCGatewayInterceptors(func(handler http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Gateway", "true")
+				handler.ServeHTTP(w, r)
+			})
+		}).
+		BuildRESTPart().
+		Build()
+	require.NoError(t, err)
+	defer service.Stop(context.Background())
+	go service.Run(context.Background())
+	resp, err := http.Post("http://localhost:8889/demo.Demo/Ping", "application/json", strings.NewReader(`{"in":"in"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"), "CORS wraps Connect calls")
+	assert.Empty(t, resp.Header.Get("X-Gateway"), "gateway interceptors don't wrap Connect calls")
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+	assert.Contains(t, string(body), `"code":"unimplemented"`)
+}
+
 func TestCustomGrpcServerOptions(t *testing.T) {
 	service, err := Builder().
 		ListenOn("localhost:8888").
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-masonry/mortar/interfaces/http/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type phaseRecorder struct {
+	sync.Mutex
+	reports []server.ShutdownPhaseReport
+}
+
+func (p *phaseRecorder) observe(_ context.Context, report server.ShutdownPhaseReport) {
+	p.Lock()
+	defer p.Unlock()
+	p.reports = append(p.reports, report)
+}
+
+func (p *phaseRecorder) phases() (output []server.ShutdownPhase) {
+	p.Lock()
+	defer p.Unlock()
+	for _, report := range p.reports {
+		output = append(output, report.Phase)
+	}
+	return
+}
+
+func TestShutdownPhasesForceStop(t *testing.T) {
+	recorder := new(phaseRecorder)
+	var stopHookCalled bool
+	started := make(chan struct{})
+	service, err := Builder().
+		RegisterGRPCAPIs(registerGrpcAPI).
+		SetShutdownPolicy(server.ShutdownPolicy{DrainDelay: 50 * time.Millisecond, GracePeriod: 100 * time.Millisecond}).
+		AddShutdownObservers(recorder.observe).
+		AddStopHooks(func(context.Context) { stopHookCalled = true }).
+		AddRESTServerConfiguration().
+		AddHandlerFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-r.Context().Done() // canceled only when the connection is closed
+		}).
+		BuildRESTPart().
+		Build()
+	require.NoError(t, err)
+	go service.Run(context.Background())
+	var restAddress string
+	for _, info := range service.Ports() {
+		if info.Type == server.RESTServer {
+			restAddress = info.Address
+		}
+	}
+	requestDone := make(chan error)
+	go func() {
+		resp, err := http.Get("http://" + restAddress + "/slow")
+		if err == nil {
+			resp.Body.Close()
+		}
+		requestDone <- err
+	}()
+	<-started
+	begin := time.Now()
+	assert.NoError(t, service.Stop(context.Background()))
+	assert.Less(t, time.Since(begin), 2*time.Second, "in-flight call should be forced to stop")
+	assert.Error(t, <-requestDone)
+	assert.True(t, stopHookCalled)
+	assert.Equal(t, []server.ShutdownPhase{server.NotReadyPhase, server.DrainPhase, server.GracefulPhase, server.ForcePhase}, recorder.phases())
+	drain := recorder.reports[1]
+	assert.GreaterOrEqual(t, drain.Duration, 50*time.Millisecond)
+	assert.Equal(t, int64(1), drain.RESTInFlight)
+	assert.Equal(t, int64(1), recorder.reports[2].RESTInFlight)
+}
+
+func TestShutdownPhasesGraceful(t *testing.T) {
+	recorder := new(phaseRecorder)
+	service, err := Builder().
+		RegisterGRPCAPIs(registerGrpcAPI).
+		SetShutdownPolicy(server.ShutdownPolicy{GracePeriod: time.Second}).
+		AddShutdownObservers(recorder.observe).
+		AddRESTServerConfiguration().
+		AddHandler("/notfound", http.NotFoundHandler()).
+		BuildRESTPart().
+		Build()
+	require.NoError(t, err)
+	go service.Run(context.Background())
+	assert.NoError(t, service.Stop(context.Background()))
+	assert.Equal(t, []server.ShutdownPhase{server.NotReadyPhase, server.GracefulPhase}, recorder.phases())
+	assert.Zero(t, recorder.reports[1].RESTInFlight)
+	assert.Zero(t, recorder.reports[1].GRPCInFlight)
+	assert.NoError(t, service.Stop(context.Background()), "second stop is ignored")
+	assert.Len(t, recorder.phases(), 2)
+}
+
+func TestShutdownWithGRPCWebCallInFlight(t *testing.T) {
+	recorder := new(phaseRecorder)
+	service, err := Builder().
+		RegisterGRPCAPIs(func(srv *grpc.Server) {
+			grpc_health_v1.RegisterHealthServer(srv, grpchealth.NewServer())
+		}).
+		SetShutdownPolicy(server.ShutdownPolicy{GracePeriod: 100 * time.Millisecond}).
+		AddShutdownObservers(recorder.observe).
+		AddRESTServerConfiguration().
+		EnableGRPCWeb(server.GRPCWebOptions{Connect: true}).
+		BuildRESTPart().
+		Build()
+	require.NoError(t, err)
+	go service.Run(context.Background())
+	var restAddress string
+	for _, info := range service.Ports() {
+		if info.Type == server.RESTServer {
+			restAddress = info.Address
+		}
+	}
+	// Watch is served by grpc.Server.ServeHTTP until the service is forced to stop
+	resp, err := http.Post("http://"+restAddress+"/grpc.health.v1.Health/Watch", "application/connect+json",
+		bytes.NewReader([]byte{0, 0, 0, 0, 2, '{', '}'}))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	_, err = resp.Body.Read(make([]byte, 1))
+	require.NoError(t, err)
+	assert.NoError(t, service.Stop(context.Background()))
+	assert.Equal(t, []server.ShutdownPhase{server.NotReadyPhase, server.GracefulPhase, server.ForcePhase}, recorder.phases())
+}
@@ -71,10 +71,34 @@ type Config interface {
 	Set(key string, value interface{})
 	// Map the entire configuration to a... map
 	Map() map[string]interface{}
+	/*
+		Sub returns a view of the configuration rooted at prefix, every key used with this view is relative to the prefix.
+
+		Example:
+
+				traceConfig := config.Sub("mortar.middleware.trace")
+				includeRequest := traceConfig.Get("grpc.client.request").Bool() // same as config.Get("mortar.middleware.trace.grpc.client.request")
+	*/
+	Sub(prefix string) Config
+	// Keys returns all the leaf keys (using '.' notation) found under prefix, an empty prefix will return all of them
+	Keys(prefix string) []string
+	// OnChange registers a callback that is called with the changed key every time a value is Set.
+	//
+	// Callbacks registered on a Sub view are only called for keys under its prefix, with keys relative to it.
+	OnChange(callback func(key string))
 	// Implementation returns the actual lib/struct that is responsible for the above logic
 	Implementation() interface{}
 }
 
+// ValueSources is an optional interface a Config implementation can implement to report
+// where each of its final values originated from
+type ValueSources interface {
+	// Sources returns a flat map of every known leaf key (using '.' notation) to the origin of its final value.
+	//
+	// Origin is usually a config file path, but it can also be "env", "set" and so on.
+	Sources() map[string]string
+}
+
 // Builder defines configuration builder options
 type Builder interface {
 	// SetConfigFile tells builder where to look for file with the configuration map
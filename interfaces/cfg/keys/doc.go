@@ -20,6 +20,10 @@ Expected configuration structure is a map, below is a complete example in the YA
 		# Application/Project name
 		# Type: string
 		name: "Application Name"
+		# Environment profile(s) to overlay on top of this file, each one loads a `config.<profile>.yaml` file.
+		# Can also be selected with the MORTAR_PROFILE environment variable or the --mortar.profile flag (see config.WithFlags).
+		# Type: string
+		profile: "staging"
 		# Web server related configuration
 		server:
 			# Host is the host on which the webserver will serve APIs
@@ -29,15 +33,91 @@ Expected configuration structure is a map, below is a complete example in the YA
 				# gRPC API External port
 				# Type: int
 				port: 5380
+				# gRPC API Internal port, health, reflection and channelz are served only here
+				# Type: int
+				internal:
+					port: 5383
+				keepalive:
+					# Type: duration
+					time: 2h
+					# Type: duration
+					timeout: 20s
+					# Type: duration
+					maxConnectionIdle: 15m
+					# Type: duration
+					maxConnectionAge: 30m
+					# Type: duration
+					maxConnectionAgeGrace: 1m
+					enforcement:
+						# Type: duration
+						minTime: 5m
+						# Type: bool
+						permitWithoutStream: false
+				# Bytes
+				# Type: int
+				maxSendMsgSize: 4194304
+				# Bytes
+				# Type: int
+				maxRecvMsgSize: 4194304
+				# Type: int
+				maxConcurrentStreams: 100
+				# Type: duration
+				connectionTimeout: 120s
+				# Bytes
+				# Type: int
+				initialWindowSize: 65536
+				# Bytes
+				# Type: int
+				initialConnWindowSize: 65536
+				# Register gRPC reflection service, it describes all the gRPC APIs
+				# Default: true only if there is an internal port, served on the internal port
+				# Type: bool
+				reflection: true
+				# Register gRPC channelz service
+				# Default: true only if there is an internal port, served on the internal port
+				# Type: bool
+				channelz: true
 			rest:
 				# RESTful API External port
 				# Type: int
 				external:
 					port: 5381
+					# gRPC-Web and Connect calls of browsers, translated to gRPC calls. CORS is configured under mortar.middleware.cors
+					browser:
+						# Type: bool
+						grpcWeb: true
+						# Type: bool
+						connect: true
 				# RESTful API Internal port
 				# Type: int
 				internal:
 					port: 5382
+				# Timeouts and limits of both external and internal RESTful APIs
+				# Type: duration
+				readTimeout: 30s
+				# Type: duration
+				readHeaderTimeout: 5s
+				# Type: duration
+				writeTimeout: 30s
+				# Type: duration
+				idleTimeout: 2m
+				# Bytes
+				# Type: int
+				maxHeaderBytes: 1048576
+			shutdown:
+				# Keep serving after the service was marked as not ready, so load balancers can notice
+				# Type: duration
+				drainDelay: 5s
+				# Wait for in-flight calls before forcing them to stop
+				# Type: duration
+				gracePeriod: 20s
+			listeners:
+				# Hand the listeners to a new instance of the service on SIGUSR2, then stop gracefully
+				# Type: bool
+				handoff: true
+				# Wait for the new instance to be up, otherwise kill it and keep serving
+				# Type: duration
+				handoffTimeout: 1m
 		# Default Logger related configuration
 		logger:
 			# Set the default log level for mortar logger
@@ -45,6 +125,11 @@ Expected configuration structure is a map, below is a complete example in the YA
 			#		trace, debug, info, warn, error
 			# Type: string
 			level: debug
+			# Set log levels of named loggers, logger name prefix to log level
+			# Type: map[string]string
+			levels:
+				middleware.grpc.server: info
+				http.client: debug
 			static:
 				# enables/disables adding a git commit SHA in every log entry
 				# Type: bool
@@ -61,6 +146,65 @@ Expected configuration structure is a map, below is a complete example in the YA
 			#		trace, debug, info, warn, error
 			# Type: string
 			startStop: info
+			# Log sampling and rate limiting, error entries are exempt unless includeErrors is set
+			sampling:
+				# First N entries of every message template are logged each tick
+				# Type: int
+				first: 100
+				# Every Mth entry of a message template is logged after the first N
+				# Type: int
+				thereafter: 100
+				# Sampling period
+				# Type: duration
+				tick: 1s
+				# Entries per second per log level
+				# Type: map[string]int
+				rateLimits:
+					info: 1000
+					debug: 100
+				# Type: bool
+				includeErrors: false
+				# Interval of summary entries that report how many entries were dropped
+				# Type: duration
+				summary: 1m
+			# Asynchronous logging, caller information is not available in this mode
+			async:
+				# Type: bool
+				enabled: false
+				# Type: int
+				queueSize: 1024
+				# What to do when the queue is full
+				# Possible values:
+				#		block, dropNewest, dropOldest
+				# Type: string
+				policy: block
+			# Keep the last N entries of every log level in memory, served by /self/logs on the internal port
+			recent:
+				# Type: int
+				size: 1000
+				# Overrides size for specific log levels
+				# Type: map[string]int
+				levels:
+					trace: 0
+					error: 5000
+			# Write logs to a rotating file instead of stderr, applies only to the default (naive) log builder
+			file:
+				# Type: string
+				path: /var/log/awesome/service.log
+				# Megabytes
+				# Type: int
+				maxSize: 100
+				# Type: duration
+				interval: 24h
+				# Type: int
+				maxBackups: 7
+				# Type: duration
+				maxAge: 168h
+				# Type: bool
+				compress: true
+				# Needed when the file is rotated by an external tool such as logrotate
+				# Type: bool
+				reopenOnSighup: false
 		# Metrics/Monitoring related configuration
 		monitor:
 			# sets the namespace/prefix of every metric. Depends on the Metrics implementation
@@ -83,6 +227,17 @@ Expected configuration structure is a map, below is a complete example in the YA
 					- "secret"
 					- "login"
 					- "user"
+			# OpenAPI documents and docs UI, see providers.OpenAPIHandlersFxOption
+			openapi:
+				# Type: string
+				path: "/openapi.json"
+				# Type: string
+				title: "Demo API"
+				ui:
+					# Type: bool
+					enabled: true
+					# Type: string
+					path: "/docs/"
 		# Interceptors/Extractors configuration
 		middleware:
 			# set the default log level of all the bundled middleware that writes to log
@@ -101,6 +256,19 @@ Expected configuration structure is a map, below is a complete example in the YA
 			# add gRPC response to every log entry
 			# Type: bool
 			logResponse: true
+			# obfuscate sensitive values of log fields and request/response bodies in logs and traces
+			# proto fields annotated with [debug_redact = true] are always obfuscated
+			redact:
+				# list of keywords that once contained within a field/JSON key will obfuscate the value
+				# Type: []string
+				keys:
+					- "password"
+					- "token"
+				# list of JSON paths within request/response bodies, `*` matches any key or index
+				# Type: []string
+				paths:
+					- "user.email"
+					- "cards.*.number"
 			# list of headers to be extracted from Incoming gRPC and added to every log entry
 			# Type: []string
 			logHeaders:
@@ -142,5 +310,30 @@ Expected configuration structure is a map, below is a complete example in the YA
 				# Type: []string
 				headers:
 					- "authorization"
+			# CORS of external HTTP handlers, gRPC gateway and gRPC-Web/Connect calls, see providers.CORSInterceptorFxOption
+			cors:
+				# Type: []string
+				allowedOrigins:
+					- "https://app.example.com"
+					- "https://*.example.com"
+				# Type: []string
+				allowedOriginPatterns:
+					- "^https://[a-z]+\\.example\\.(com|org)$"
+				# Type: []string
+				allowedMethods:
+					- "GET"
+					- "POST"
+				# Type: []string
+				allowedHeaders:
+					- "Authorization"
+					- "Content-Type"
+				# Type: []string
+				exposedHeaders:
+					- "X-Request-Id"
+				# Origins must be listed, `*` can't be used with credentials
+				# Type: bool
+				allowCredentials: true
+				# Type: duration
+				maxAge: 10m
 */
 package confkeys
@@ -10,6 +10,13 @@ const (
 	// Type: string
 	ApplicationName string = mortar + ".name"
 
+	// Profile selects the environment profile(s) to overlay on top of the main configuration file.
+	// Each profile `<name>` loads a `config.<name>.yaml` file found next to the main `config.yaml` file.
+	// Several profiles can be separated by a comma, they are applied in order.
+	//
+	// Type: string
+	Profile string = mortar + ".profile"
+
 	// Webserver specific configurations
 	server = mortar + ".server"
 	// Mortar Logger configuration
@@ -28,6 +35,14 @@ const (
 	gRPC = server + ".grpc"
 	// Webserver -> RESTful related configuration
 	rest = server + ".rest"
+	// Webserver -> shutdown related configuration
+	shutdown = server + ".shutdown"
+	// Webserver -> RESTful -> external -> browser protocols related configuration
+	restBrowser = rest + ".external.browser"
+	// Webserver -> gRPC -> keepalive related configuration
+	grpcKeepalive = gRPC + ".keepalive"
+	// Webserver -> gRPC -> keepalive -> enforcement policy related configuration
+	grpcKeepaliveEnforcement = grpcKeepalive + ".enforcement"
 
 	// Host is the host on which the webserver will serve APIs
 	//
@@ -39,6 +54,89 @@ const (
 	// Type: int
 	ExternalGRPCPort string = gRPC + ".port"
 
+	// GRPCKeepaliveTime pings an idle client after this duration to check that the connection is still alive
+	//
+	// Type: duration
+	GRPCKeepaliveTime string = grpcKeepalive + ".time"
+
+	// GRPCKeepaliveTimeout closes the connection if a keepalive ping is not acknowledged within this duration
+	//
+	// Type: duration
+	GRPCKeepaliveTimeout string = grpcKeepalive + ".timeout"
+
+	// GRPCKeepaliveMaxConnectionIdle closes connections that were idle for this duration
+	//
+	// Type: duration
+	GRPCKeepaliveMaxConnectionIdle string = grpcKeepalive + ".maxConnectionIdle"
+
+	// GRPCKeepaliveMaxConnectionAge gracefully closes connections older than this duration, useful to rebalance clients
+	//
+	// Type: duration
+	GRPCKeepaliveMaxConnectionAge string = grpcKeepalive + ".maxConnectionAge"
+
+	// GRPCKeepaliveMaxConnectionAgeGrace forcibly closes connections this duration after GRPCKeepaliveMaxConnectionAge
+	//
+	// Type: duration
+	GRPCKeepaliveMaxConnectionAgeGrace string = grpcKeepalive + ".maxConnectionAgeGrace"
+
+	// GRPCKeepaliveEnforcementMinTime is the minimum duration clients should wait between keepalive pings
+	//
+	// Type: duration
+	GRPCKeepaliveEnforcementMinTime string = grpcKeepaliveEnforcement + ".minTime"
+
+	// GRPCKeepaliveEnforcementPermitWithoutStream allows keepalive pings when there are no active streams
+	//
+	// Type: bool
+	GRPCKeepaliveEnforcementPermitWithoutStream string = grpcKeepaliveEnforcement + ".permitWithoutStream"
+
+	// GRPCMaxSendMsgSize is the max message size in bytes the webserver can send
+	//
+	// Type: int
+	GRPCMaxSendMsgSize string = gRPC + ".maxSendMsgSize"
+
+	// GRPCMaxRecvMsgSize is the max message size in bytes the webserver can receive, it limits gRPC-Web and Connect requests as well
+	//
+	// Type: int
+	GRPCMaxRecvMsgSize string = gRPC + ".maxRecvMsgSize"
+
+	// GRPCMaxConcurrentStreams limits the number of concurrent streams of each connection
+	//
+	// Type: int
+	GRPCMaxConcurrentStreams string = gRPC + ".maxConcurrentStreams"
+
+	// GRPCConnectionTimeout is the timeout of connection establishment, including the HTTP/2 handshake
+	//
+	// Type: duration
+	GRPCConnectionTimeout string = gRPC + ".connectionTimeout"
+
+	// GRPCInitialWindowSize is the initial window size of a stream in bytes
+	//
+	// Type: int
+	GRPCInitialWindowSize string = gRPC + ".initialWindowSize"
+
+	// GRPCInitialConnWindowSize is the initial window size of a connection in bytes
+	//
+	// Type: int
+	GRPCInitialConnWindowSize string = gRPC + ".initialConnWindowSize"
+
+	// InternalGRPCPort is the Port on which the webserver will serve it's internal/private gRPC API.
+	// When set, health, reflection and channelz services are served only on this port
+	//
+	// Type: int
+	InternalGRPCPort string = gRPC + ".internal.port"
+
+	// GRPCReflection registers the gRPC reflection service, so tools like grpcurl can describe APIs without their protos.
+	// Unless set, it's enabled only on the internal gRPC port if there is one, otherwise enabling it exposes the service on the external gRPC port
+	//
+	// Type: bool
+	GRPCReflection string = gRPC + ".reflection"
+
+	// GRPCChannelz registers the gRPC channelz service, which exposes runtime information about channels and sockets.
+	// Unless set, it's enabled only on the internal gRPC port if there is one, otherwise enabling it exposes the service on the external gRPC port
+	//
+	// Type: bool
+	GRPCChannelz string = gRPC + ".channelz"
+
 	// ExternalRESTPort is the Port on which the webserver will serve it's external/public RESTful API
 	//
 	// Type: int
@@ -48,10 +146,83 @@ const (
 	//
 	// Type: int
 	InternalRESTPort string = rest + ".internal.port"
+
+	// ExternalRESTGRPCWeb translates gRPC-Web calls (binary and text) made to the external RESTful API port to gRPC calls.
+	// CORS of gRPC-Web and Connect calls is handled by the CORS interceptor, see CORSAllowedOrigins
+	//
+	// Type: bool
+	ExternalRESTGRPCWeb string = restBrowser + ".grpcWeb"
+
+	// ExternalRESTConnect translates Connect protocol calls (proto and JSON) made to the external RESTful API port to gRPC calls
+	//
+	// Type: bool
+	ExternalRESTConnect string = restBrowser + ".connect"
+
+	// RESTReadTimeout is the maximum duration of reading an entire request, including the body.
+	// Applies to both external and internal RESTful APIs
+	//
+	// Type: duration
+	RESTReadTimeout string = rest + ".readTimeout"
+
+	// RESTReadHeaderTimeout is the maximum duration of reading request headers.
+	// Applies to both external and internal RESTful APIs
+	//
+	// Type: duration
+	RESTReadHeaderTimeout string = rest + ".readHeaderTimeout"
+
+	// RESTWriteTimeout is the maximum duration before timing out writes of the response.
+	// Applies to both external and internal RESTful APIs
+	//
+	// Type: duration
+	RESTWriteTimeout string = rest + ".writeTimeout"
+
+	// RESTIdleTimeout is the maximum duration to wait for the next request when keep-alives are enabled.
+	// Applies to both external and internal RESTful APIs
+	//
+	// Type: duration
+	RESTIdleTimeout string = rest + ".idleTimeout"
+
+	// RESTMaxHeaderBytes is the maximum size of request headers in bytes.
+	// Applies to both external and internal RESTful APIs
+	//
+	// Type: int
+	RESTMaxHeaderBytes string = rest + ".maxHeaderBytes"
+
+	// ShutdownDrainDelay is how long the webserver keeps serving after it was marked as not ready, default is 0
+	//
+	// Type: duration
+	ShutdownDrainDelay string = shutdown + ".drainDelay"
+
+	// ShutdownGracePeriod is how long the webserver waits for in-flight calls before forcing them to stop,
+	// default is 0 which waits until the stop timeout of the application
+	//
+	// Type: duration
+	ShutdownGracePeriod string = shutdown + ".gracePeriod"
+
+	// ListenersHandoff enables handing the webserver listeners to a new instance of the service on SIGUSR2,
+	// once the new instance is up this one stops gracefully
+	//
+	// Type: bool
+	ListenersHandoff string = server + ".listeners.handoff"
+
+	// ListenersHandoffTimeout is how long to wait for the new instance to be up, otherwise it's killed and this one
+	// keeps serving. Default is 1m
+	//
+	// Type: duration
+	ListenersHandoffTimeout string = server + ".listeners.handoffTimeout"
 )
 
 // Logger related keys
 const (
+	// Logger -> sampling related configuration
+	sampling = logger + ".sampling"
+	// Logger -> asynchronous logging related configuration
+	async = logger + ".async"
+	// Logger -> recent logs related configuration
+	recent = logger + ".recent"
+	// Logger -> log file related configuration
+	file = logger + ".file"
+
 	// LogLevel set the default log level for mortar logger
 	// Possible values:
 	//		trace, debug, info, warn, error
@@ -59,6 +230,12 @@ const (
 	// Type: string
 	LogLevel string = logger + ".level"
 
+	// LogLevels set log levels of named loggers, a map of logger name prefix to log level.
+	// The longest matching prefix wins, loggers without a match use LogLevel
+	//
+	// Type: map[string]string
+	LogLevels string = logger + ".levels"
+
 	// Log service start and stop events, custom by log level
 	// Possible values:
 	//		trace, debug, info, warn, error
@@ -78,6 +255,101 @@ const (
 	//
 	// Type: bool
 	LogIncludeName string = logger + ".static.name"
+
+	// LogSamplingFirst enables log sampling, first N entries of every message template are logged each tick
+	//
+	// Type: int
+	LogSamplingFirst string = sampling + ".first"
+
+	// LogSamplingThereafter every Mth entry of a message template is logged after the first N, 0 drops them all
+	//
+	// Type: int
+	LogSamplingThereafter string = sampling + ".thereafter"
+
+	// LogSamplingTick sampling period, default is 1s
+	//
+	// Type: duration
+	LogSamplingTick string = sampling + ".tick"
+
+	// LogSamplingRateLimits entries per second per log level, a token bucket with a burst that equals the rate
+	//
+	// Type: map[string]int
+	LogSamplingRateLimits string = sampling + ".rateLimits"
+
+	// LogSamplingIncludeErrors apply sampling and rate limits on error entries, they are exempt by default
+	//
+	// Type: bool
+	LogSamplingIncludeErrors string = sampling + ".includeErrors"
+
+	// LogSamplingSummaryInterval interval of summary entries that report how many entries were dropped, default is 1m
+	//
+	// Type: duration
+	LogSamplingSummaryInterval string = sampling + ".summary"
+
+	// LogAsyncEnabled enables asynchronous logging, entries are written by a background goroutine.
+	// Caller information is not available in this mode
+	//
+	// Type: bool
+	LogAsyncEnabled string = async + ".enabled"
+
+	// LogAsyncQueueSize size of the asynchronous logging queue, default is 1024
+	//
+	// Type: int
+	LogAsyncQueueSize string = async + ".queueSize"
+
+	// LogAsyncPolicy what to do when the asynchronous logging queue is full
+	// Possible values:
+	//		block, dropNewest, dropOldest
+	//
+	// Type: string
+	LogAsyncPolicy string = async + ".policy"
+
+	// LogRecentSize keeps the last N entries of every log level in memory, 0 disables it.
+	// Entries are available on the internal port, see handlers.RecentLogsHandlers
+	//
+	// Type: int
+	LogRecentSize string = recent + ".size"
+
+	// LogRecentLevels overrides LogRecentSize for specific log levels, a map of log level to size
+	//
+	// Type: map[string]int
+	LogRecentLevels string = recent + ".levels"
+
+	// LogFilePath writes logs to a rotating file instead of stderr, applies only to the default (naive) log builder
+	// when no writer was set on it, otherwise it is ignored with a warning
+	//
+	// Type: string
+	LogFilePath string = file + ".path"
+
+	// LogFileMaxSize rotates the log file before it gets bigger than N megabytes, 0 disables size based rotation
+	//
+	// Type: int
+	LogFileMaxSize string = file + ".maxSize"
+
+	// LogFileInterval rotates the log file every interval, aligned to the interval. 0 disables time based rotation
+	//
+	// Type: duration
+	LogFileInterval string = file + ".interval"
+
+	// LogFileMaxBackups number of rotated log files to keep, 0 keeps all of them
+	//
+	// Type: int
+	LogFileMaxBackups string = file + ".maxBackups"
+
+	// LogFileMaxAge removes rotated log files older than this, 0 keeps all of them
+	//
+	// Type: duration
+	LogFileMaxAge string = file + ".maxAge"
+
+	// LogFileCompress gzips rotated log files
+	//
+	// Type: bool
+	LogFileCompress string = file + ".compress"
+
+	// LogFileReopenOnSIGHUP reopens the log file on SIGHUP, needed when the file is rotated by an external tool such as logrotate
+	//
+	// Type: bool
+	LogFileReopenOnSIGHUP string = file + ".reopenOnSighup"
 )
 
 // Monitoring related keys
@@ -105,6 +377,26 @@ const (
 	//
 	// Type: []string
 	ConfigHandlerObfuscateKeys = handlers + ".config.obfuscate"
+
+	// OpenAPISpecPath is where the merged OpenAPI document of each port is served, default is "/openapi.json"
+	//
+	// Type: string
+	OpenAPISpecPath = handlers + ".openapi.path"
+
+	// OpenAPITitle replaces the title of the merged OpenAPI documents, default is the application name
+	//
+	// Type: string
+	OpenAPITitle = handlers + ".openapi.title"
+
+	// OpenAPIUIEnabled serves the bundled docs UI next to the OpenAPI document, default is true
+	//
+	// Type: bool
+	OpenAPIUIEnabled = handlers + ".openapi.ui.enabled"
+
+	// OpenAPIUIPath is where the bundled docs UI is served, default is "/docs/"
+	//
+	// Type: string
+	OpenAPIUIPath = handlers + ".openapi.ui.path"
 )
 
 // Middleware
@@ -133,6 +425,22 @@ const (
 	// Type: bool
 	MiddlewareLogIncludeResponse = middleware + ".logResponse"
 
+	// redaction related keys, applied on log fields and request/response bodies in logs and traces
+	redact = middleware + ".redact"
+
+	// RedactKeys defines a list of keywords that once contained within a field/JSON key will obfuscate the value
+	//
+	// Type: []string
+	RedactKeys = redact + ".keys"
+
+	// RedactPaths defines a list of JSON paths within request/response bodies to obfuscate, `*` matches any key or index
+	//
+	//	user.password
+	//	users.*.creditCard
+	//
+	// Type: []string
+	RedactPaths = redact + ".paths"
+
 	// trace related keys with http context
 	traceHTTP = middleware + ".trace.http"
 
@@ -185,4 +493,47 @@ const (
 	//
 	// Type: []string
 	LoggerIncomingGRPCMetadataHeadersExtractor = middleware + ".logHeaders"
+
+	// CORS related keys of external HTTP handlers, gRPC gateway and gRPC-Web/Connect calls
+	cors = middleware + ".cors"
+
+	// CORSAllowedOrigins is a list of origins allowed to make cross-origin requests, one `*` can match any part of an origin
+	//	['https://app.example.com', 'https://*.example.com', '*']
+	//
+	// Type: []string
+	CORSAllowedOrigins = cors + ".allowedOrigins"
+
+	// CORSAllowedOriginPatterns is a list of regular expressions of origins allowed to make cross-origin requests, each must match the entire origin
+	//	['^https://[a-z]+\.example\.(com|org)$']
+	//
+	// Type: []string
+	CORSAllowedOriginPatterns = cors + ".allowedOriginPatterns"
+
+	// CORSAllowedMethods is a list of methods allowed in cross-origin requests, default is GET, HEAD, POST, PUT, PATCH and DELETE
+	//
+	// Type: []string
+	CORSAllowedMethods = cors + ".allowedMethods"
+
+	// CORSAllowedHeaders is a list of request headers allowed in cross-origin requests, `*` allows every header.
+	// Default is Accept, Authorization, Content-Type and X-Requested-With. Headers of gRPC-Web (X-Grpc-Web, X-User-Agent, Grpc-Timeout)
+	// and Connect (Connect-Protocol-Version, Connect-Timeout-Ms) clients are added when the protocol is enabled, see ExternalRESTGRPCWeb
+	//
+	// Type: []string
+	CORSAllowedHeaders = cors + ".allowedHeaders"
+
+	// CORSExposedHeaders is a list of response headers browsers expose to cross-origin requests
+	//
+	// Type: []string
+	CORSExposedHeaders = cors + ".exposedHeaders"
+
+	// CORSAllowCredentials allows cross-origin requests with cookies and authorization headers,
+	// origins must be listed explicitly since `*` is rejected
+	//
+	// Type: bool
+	CORSAllowCredentials = cors + ".allowCredentials"
+
+	// CORSMaxAge is how long browsers can cache preflight results
+	//
+	// Type: duration
+	CORSMaxAge = cors + ".maxAge"
 )
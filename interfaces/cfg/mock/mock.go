@@ -352,6 +352,20 @@ func (mr *MockConfigMockRecorder) Implementation() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Implementation", reflect.TypeOf((*MockConfig)(nil).Implementation))
 }
 
+// Keys mocks base method.
+func (m *MockConfig) Keys(prefix string) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys", prefix)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockConfigMockRecorder) Keys(prefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockConfig)(nil).Keys), prefix)
+}
+
 // Map mocks base method.
 func (m *MockConfig) Map() map[string]interface{} {
 	m.ctrl.T.Helper()
@@ -366,6 +380,18 @@ func (mr *MockConfigMockRecorder) Map() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Map", reflect.TypeOf((*MockConfig)(nil).Map))
 }
 
+// OnChange mocks base method.
+func (m *MockConfig) OnChange(callback func(string)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnChange", callback)
+}
+
+// OnChange indicates an expected call of OnChange.
+func (mr *MockConfigMockRecorder) OnChange(callback interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnChange", reflect.TypeOf((*MockConfig)(nil).OnChange), callback)
+}
+
 // Set mocks base method.
 func (m *MockConfig) Set(key string, value interface{}) {
 	m.ctrl.T.Helper()
@@ -378,6 +404,57 @@ func (mr *MockConfigMockRecorder) Set(key, value interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockConfig)(nil).Set), key, value)
 }
 
+// Sub mocks base method.
+func (m *MockConfig) Sub(prefix string) cfg.Config {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sub", prefix)
+	ret0, _ := ret[0].(cfg.Config)
+	return ret0
+}
+
+// Sub indicates an expected call of Sub.
+func (mr *MockConfigMockRecorder) Sub(prefix interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sub", reflect.TypeOf((*MockConfig)(nil).Sub), prefix)
+}
+
+// MockValueSources is a mock of ValueSources interface.
+type MockValueSources struct {
+	ctrl     *gomock.Controller
+	recorder *MockValueSourcesMockRecorder
+}
+
+// MockValueSourcesMockRecorder is the mock recorder for MockValueSources.
+type MockValueSourcesMockRecorder struct {
+	mock *MockValueSources
+}
+
+// NewMockValueSources creates a new mock instance.
+func NewMockValueSources(ctrl *gomock.Controller) *MockValueSources {
+	mock := &MockValueSources{ctrl: ctrl}
+	mock.recorder = &MockValueSourcesMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockValueSources) EXPECT() *MockValueSourcesMockRecorder {
+	return m.recorder
+}
+
+// Sources mocks base method.
+func (m *MockValueSources) Sources() map[string]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sources")
+	ret0, _ := ret[0].(map[string]string)
+	return ret0
+}
+
+// Sources indicates an expected call of Sources.
+func (mr *MockValueSourcesMockRecorder) Sources() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sources", reflect.TypeOf((*MockValueSources)(nil).Sources))
+}
+
 // MockBuilder is a mock of Builder interface.
 type MockBuilder struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,93 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+//go:generate mockgen -source=interfaces.go -destination=mock/mock.go
+
+// Probe defines which question a health check answers
+type Probe string
+
+const (
+	// Liveness is the process alive, failing it usually means a restart
+	Liveness Probe = "live"
+	// Readiness can the service handle requests, failing it usually means no traffic
+	Readiness Probe = "ready"
+	// Startup did the service finish starting, orchestrators usually check liveness and readiness only after it passes
+	Startup Probe = "startup"
+)
+
+// Status of a check or an entire probe
+type Status string
+
+const (
+	// Pass everything is fine
+	Pass Status = "pass"
+	// Warn a non-critical check failed, the probe still passes
+	Warn Status = "warn"
+	// Fail a critical check failed
+	Fail Status = "fail"
+)
+
+// CheckFunc returns an error if the component is unhealthy, it should respect ctx deadline
+type CheckFunc func(ctx context.Context) error
+
+// Check is a named component health check
+type Check struct {
+	// Name must be unique
+	Name string
+	// Check is the check itself
+	Check CheckFunc
+	// Timeout of a single Check call, default is 5 seconds
+	Timeout time.Duration
+	// Critical checks fail their probes, failures of other checks are only reported
+	Critical bool
+	// CacheInterval reuses the last result for this long, 0 runs the check on every probe
+	CacheInterval time.Duration
+	// Probes that include this check, default is Readiness
+	Probes []Probe
+}
+
+// CheckResult is the result of a single Check
+type CheckResult struct {
+	Name      string
+	Status    Status
+	Critical  bool
+	Latency   time.Duration
+	Error     string
+	CheckedAt time.Time
+	Cached    bool
+}
+
+// MarshalJSON marshals Latency as a duration string
+func (c CheckResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name      string    `json:"name"`
+		Status    Status    `json:"status"`
+		Critical  bool      `json:"critical"`
+		Latency   string    `json:"latency"`
+		Error     string    `json:"error,omitempty"`
+		CheckedAt time.Time `json:"checked_at"`
+		Cached    bool      `json:"cached"`
+	}{c.Name, c.Status, c.Critical, c.Latency.String(), c.Error, c.CheckedAt, c.Cached})
+}
+
+// Report is the result of a Probe
+type Report struct {
+	Probe  Probe         `json:"probe"`
+	Status Status        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Registry holds health checks of all the components
+type Registry interface {
+	// Register adds a check, names must be unique
+	Register(check Check) error
+	// Run runs all the checks of a probe concurrently, a probe without checks passes
+	Run(ctx context.Context, probe Probe) Report
+	// Shutdown marks the service as shutting down, from now on Readiness probe fails regardless of its checks
+	Shutdown()
+}
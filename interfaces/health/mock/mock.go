@@ -0,0 +1,76 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interfaces.go
+
+// Package mock_health is a generated GoMock package.
+package mock_health
+
+import (
+	context "context"
+	reflect "reflect"
+
+	health "github.com/go-masonry/mortar/interfaces/health"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRegistry is a mock of Registry interface.
+type MockRegistry struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegistryMockRecorder
+}
+
+// MockRegistryMockRecorder is the mock recorder for MockRegistry.
+type MockRegistryMockRecorder struct {
+	mock *MockRegistry
+}
+
+// NewMockRegistry creates a new mock instance.
+func NewMockRegistry(ctrl *gomock.Controller) *MockRegistry {
+	mock := &MockRegistry{ctrl: ctrl}
+	mock.recorder = &MockRegistryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegistry) EXPECT() *MockRegistryMockRecorder {
+	return m.recorder
+}
+
+// Register mocks base method.
+func (m *MockRegistry) Register(check health.Check) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", check)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockRegistryMockRecorder) Register(check interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockRegistry)(nil).Register), check)
+}
+
+// Run mocks base method.
+func (m *MockRegistry) Run(ctx context.Context, probe health.Probe) health.Report {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx, probe)
+	ret0, _ := ret[0].(health.Report)
+	return ret0
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockRegistryMockRecorder) Run(ctx, probe interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockRegistry)(nil).Run), ctx, probe)
+}
+
+// Shutdown mocks base method.
+func (m *MockRegistry) Shutdown() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Shutdown")
+}
+
+// Shutdown indicates an expected call of Shutdown.
+func (mr *MockRegistryMockRecorder) Shutdown() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockRegistry)(nil).Shutdown))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
@@ -17,6 +18,8 @@ type WebServerType string
 const (
 	// GRPCServer type
 	GRPCServer WebServerType = "GRPC"
+	// InternalGRPCServer type, a separate gRPC server of internal APIs
+	InternalGRPCServer WebServerType = "INTERNAL_GRPC"
 	// RESTServer type
 	RESTServer WebServerType = "REST"
 )
@@ -35,6 +38,53 @@ type WebService interface {
 	Ports() []ListenInfo
 }
 
+// ShutdownPhase names a phase of WebService.Stop, phases run in the order they are declared
+type ShutdownPhase string
+
+const (
+	// NotReadyPhase calls stop hooks, usually marking the service as not ready
+	NotReadyPhase ShutdownPhase = "not_ready"
+	// DrainPhase keeps serving for ShutdownPolicy.DrainDelay, so load balancers notice the service is not ready
+	DrainPhase ShutdownPhase = "drain"
+	// GracefulPhase stops accepting new calls and waits for in-flight calls up to ShutdownPolicy.GracePeriod
+	GracefulPhase ShutdownPhase = "graceful"
+	// ForcePhase closes all remaining connections, it runs only if GracefulPhase wasn't finished in time
+	ForcePhase ShutdownPhase = "force"
+)
+
+// ShutdownPolicy defines how WebService.Stop shuts down the servers
+type ShutdownPolicy struct {
+	// DrainDelay to keep serving after stop hooks were called, 0 skips the drain phase
+	DrainDelay time.Duration
+	// GracePeriod to wait for in-flight calls before forcing them to stop, 0 waits until the Stop context is done
+	GracePeriod time.Duration
+}
+
+// ShutdownPhaseReport is reported at the end of every shutdown phase.
+// In-flight calls are counted only on servers created by the builder, custom gRPC servers are not counted.
+type ShutdownPhaseReport struct {
+	Phase        ShutdownPhase
+	Duration     time.Duration
+	GRPCInFlight int64
+	RESTInFlight int64
+}
+
+// ShutdownObserver is called at the end of every shutdown phase
+type ShutdownObserver func(ctx context.Context, report ShutdownPhaseReport)
+
+// GRPCWebOptions defines which browser friendly protocols a REST server translates to gRPC calls
+type GRPCWebOptions struct {
+	// GRPCWeb accepts gRPC-Web requests, both binary and text
+	GRPCWeb bool
+	// Connect accepts Connect protocol requests, both proto and JSON
+	Connect bool
+	// MaxRecvMsgSize limits the size of request messages and bodies the same way grpc.MaxRecvMsgSize does,
+	// 0 means the gRPC default of 4MB
+	MaxRecvMsgSize int
+	// CORS wraps calls and CORS preflight requests of gRPC methods, nil means CORS is not handled
+	CORS GRPCGatewayInterceptor
+}
+
 // GRPCServerAPI alias for gRPC API function registration
 type GRPCServerAPI func(server *grpc.Server)
 
@@ -47,10 +97,29 @@ type GRPCWebServiceBuilder interface {
 	AddGRPCServerOptions(options ...grpc.ServerOption) GRPCWebServiceBuilder
 	SetPanicHandler(handler func(interface{}) error) GRPCWebServiceBuilder
 	SetLogger(logger func(ctx context.Context, format string, args ...interface{})) GRPCWebServiceBuilder
+	// AddStopHooks adds hooks that are called at the start of WebService.Stop, before servers stop accepting calls
+	AddStopHooks(hooks ...func(ctx context.Context)) GRPCWebServiceBuilder
+	// SetShutdownPolicy sets drain delay and grace period of WebService.Stop, default policy has neither
+	SetShutdownPolicy(policy ShutdownPolicy) GRPCWebServiceBuilder
+	// AddShutdownObservers adds observers of the shutdown phases
+	AddShutdownObservers(observers ...ShutdownObserver) GRPCWebServiceBuilder
 	AddRESTServerConfiguration() RESTBuilder
+	// AddInternalGRPCServerConfiguration adds a separate gRPC server with its own listener, APIs and options.
+	// There can be only one, calling it again replaces the previous configuration
+	AddInternalGRPCServerConfiguration() InternalGRPCBuilder
 	Build() (WebService, error)
 }
 
+// InternalGRPCBuilder defines internal gRPC server builder options
+type InternalGRPCBuilder interface {
+	ListenOn(addr string) InternalGRPCBuilder
+	SetCustomGRPCServer(customServer *grpc.Server) InternalGRPCBuilder
+	SetCustomListener(listener net.Listener) InternalGRPCBuilder
+	RegisterGRPCAPIs(register ...GRPCServerAPI) InternalGRPCBuilder
+	AddGRPCServerOptions(options ...grpc.ServerOption) InternalGRPCBuilder
+	BuildInternalGRPCPart() GRPCWebServiceBuilder
+}
+
 // GRPCGatewayGeneratedHandlers alias for gRPC-gateway endpoint registrations
 type GRPCGatewayGeneratedHandlers func(mux *runtime.ServeMux, endpoint string) error
 
@@ -67,6 +136,12 @@ type RESTBuilder interface {
 	SetCustomGRPCGatewayMux(mux *runtime.ServeMux) RESTBuilder
 	RegisterGRPCGatewayHandlers(handlers ...GRPCGatewayGeneratedHandlers) RESTBuilder
 	AddGRPCGatewayOptions(options ...runtime.ServeMuxOption) RESTBuilder
+	// AddGRPCGatewayInterceptors wraps gRPC gateway, as well as gRPC-Web and Connect calls when they are enabled
 	AddGRPCGatewayInterceptors(interceptors ...GRPCGatewayInterceptor) RESTBuilder
+	// UseInternalGRPCServer makes gRPC gateway handlers call the internal gRPC server, if there is one
+	UseInternalGRPCServer() RESTBuilder
+	// EnableGRPCWeb translates gRPC-Web and Connect requests to calls of the gRPC server used by the gateway,
+	// other requests are served as usual. CORS of these calls is handled by GRPCWebOptions.CORS
+	EnableGRPCWeb(options GRPCWebOptions) RESTBuilder
 	BuildRESTPart() GRPCWebServiceBuilder
 }
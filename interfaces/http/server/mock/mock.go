@@ -122,6 +122,20 @@ func (mr *MockGRPCWebServiceBuilderMockRecorder) AddGRPCServerOptions(options ..
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGRPCServerOptions", reflect.TypeOf((*MockGRPCWebServiceBuilder)(nil).AddGRPCServerOptions), options...)
 }
 
+// AddInternalGRPCServerConfiguration mocks base method.
+func (m *MockGRPCWebServiceBuilder) AddInternalGRPCServerConfiguration() server.InternalGRPCBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddInternalGRPCServerConfiguration")
+	ret0, _ := ret[0].(server.InternalGRPCBuilder)
+	return ret0
+}
+
+// AddInternalGRPCServerConfiguration indicates an expected call of AddInternalGRPCServerConfiguration.
+func (mr *MockGRPCWebServiceBuilderMockRecorder) AddInternalGRPCServerConfiguration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddInternalGRPCServerConfiguration", reflect.TypeOf((*MockGRPCWebServiceBuilder)(nil).AddInternalGRPCServerConfiguration))
+}
+
 // AddRESTServerConfiguration mocks base method.
 func (m *MockGRPCWebServiceBuilder) AddRESTServerConfiguration() server.RESTBuilder {
 	m.ctrl.T.Helper()
@@ -136,6 +150,42 @@ func (mr *MockGRPCWebServiceBuilderMockRecorder) AddRESTServerConfiguration() *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRESTServerConfiguration", reflect.TypeOf((*MockGRPCWebServiceBuilder)(nil).AddRESTServerConfiguration))
 }
 
+// AddShutdownObservers mocks base method.
+func (m *MockGRPCWebServiceBuilder) AddShutdownObservers(observers ...server.ShutdownObserver) server.GRPCWebServiceBuilder {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range observers {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddShutdownObservers", varargs...)
+	ret0, _ := ret[0].(server.GRPCWebServiceBuilder)
+	return ret0
+}
+
+// AddShutdownObservers indicates an expected call of AddShutdownObservers.
+func (mr *MockGRPCWebServiceBuilderMockRecorder) AddShutdownObservers(observers ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddShutdownObservers", reflect.TypeOf((*MockGRPCWebServiceBuilder)(nil).AddShutdownObservers), observers...)
+}
+
+// AddStopHooks mocks base method.
+func (m *MockGRPCWebServiceBuilder) AddStopHooks(hooks ...func(context.Context)) server.GRPCWebServiceBuilder {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range hooks {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddStopHooks", varargs...)
+	ret0, _ := ret[0].(server.GRPCWebServiceBuilder)
+	return ret0
+}
+
+// AddStopHooks indicates an expected call of AddStopHooks.
+func (mr *MockGRPCWebServiceBuilderMockRecorder) AddStopHooks(hooks ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStopHooks", reflect.TypeOf((*MockGRPCWebServiceBuilder)(nil).AddStopHooks), hooks...)
+}
+
 // Build mocks base method.
 func (m *MockGRPCWebServiceBuilder) Build() (server.WebService, error) {
 	m.ctrl.T.Helper()
@@ -239,6 +289,135 @@ func (mr *MockGRPCWebServiceBuilderMockRecorder) SetPanicHandler(handler interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPanicHandler", reflect.TypeOf((*MockGRPCWebServiceBuilder)(nil).SetPanicHandler), handler)
 }
 
+// SetShutdownPolicy mocks base method.
+func (m *MockGRPCWebServiceBuilder) SetShutdownPolicy(policy server.ShutdownPolicy) server.GRPCWebServiceBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetShutdownPolicy", policy)
+	ret0, _ := ret[0].(server.GRPCWebServiceBuilder)
+	return ret0
+}
+
+// SetShutdownPolicy indicates an expected call of SetShutdownPolicy.
+func (mr *MockGRPCWebServiceBuilderMockRecorder) SetShutdownPolicy(policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetShutdownPolicy", reflect.TypeOf((*MockGRPCWebServiceBuilder)(nil).SetShutdownPolicy), policy)
+}
+
+// MockInternalGRPCBuilder is a mock of InternalGRPCBuilder interface.
+type MockInternalGRPCBuilder struct {
+	ctrl     *gomock.Controller
+	recorder *MockInternalGRPCBuilderMockRecorder
+}
+
+// MockInternalGRPCBuilderMockRecorder is the mock recorder for MockInternalGRPCBuilder.
+type MockInternalGRPCBuilderMockRecorder struct {
+	mock *MockInternalGRPCBuilder
+}
+
+// NewMockInternalGRPCBuilder creates a new mock instance.
+func NewMockInternalGRPCBuilder(ctrl *gomock.Controller) *MockInternalGRPCBuilder {
+	mock := &MockInternalGRPCBuilder{ctrl: ctrl}
+	mock.recorder = &MockInternalGRPCBuilderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInternalGRPCBuilder) EXPECT() *MockInternalGRPCBuilderMockRecorder {
+	return m.recorder
+}
+
+// AddGRPCServerOptions mocks base method.
+func (m *MockInternalGRPCBuilder) AddGRPCServerOptions(options ...grpc.ServerOption) server.InternalGRPCBuilder {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range options {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddGRPCServerOptions", varargs...)
+	ret0, _ := ret[0].(server.InternalGRPCBuilder)
+	return ret0
+}
+
+// AddGRPCServerOptions indicates an expected call of AddGRPCServerOptions.
+func (mr *MockInternalGRPCBuilderMockRecorder) AddGRPCServerOptions(options ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGRPCServerOptions", reflect.TypeOf((*MockInternalGRPCBuilder)(nil).AddGRPCServerOptions), options...)
+}
+
+// BuildInternalGRPCPart mocks base method.
+func (m *MockInternalGRPCBuilder) BuildInternalGRPCPart() server.GRPCWebServiceBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildInternalGRPCPart")
+	ret0, _ := ret[0].(server.GRPCWebServiceBuilder)
+	return ret0
+}
+
+// BuildInternalGRPCPart indicates an expected call of BuildInternalGRPCPart.
+func (mr *MockInternalGRPCBuilderMockRecorder) BuildInternalGRPCPart() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildInternalGRPCPart", reflect.TypeOf((*MockInternalGRPCBuilder)(nil).BuildInternalGRPCPart))
+}
+
+// ListenOn mocks base method.
+func (m *MockInternalGRPCBuilder) ListenOn(addr string) server.InternalGRPCBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListenOn", addr)
+	ret0, _ := ret[0].(server.InternalGRPCBuilder)
+	return ret0
+}
+
+// ListenOn indicates an expected call of ListenOn.
+func (mr *MockInternalGRPCBuilderMockRecorder) ListenOn(addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenOn", reflect.TypeOf((*MockInternalGRPCBuilder)(nil).ListenOn), addr)
+}
+
+// RegisterGRPCAPIs mocks base method.
+func (m *MockInternalGRPCBuilder) RegisterGRPCAPIs(register ...server.GRPCServerAPI) server.InternalGRPCBuilder {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range register {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RegisterGRPCAPIs", varargs...)
+	ret0, _ := ret[0].(server.InternalGRPCBuilder)
+	return ret0
+}
+
+// RegisterGRPCAPIs indicates an expected call of RegisterGRPCAPIs.
+func (mr *MockInternalGRPCBuilderMockRecorder) RegisterGRPCAPIs(register ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterGRPCAPIs", reflect.TypeOf((*MockInternalGRPCBuilder)(nil).RegisterGRPCAPIs), register...)
+}
+
+// SetCustomGRPCServer mocks base method.
+func (m *MockInternalGRPCBuilder) SetCustomGRPCServer(customServer *grpc.Server) server.InternalGRPCBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCustomGRPCServer", customServer)
+	ret0, _ := ret[0].(server.InternalGRPCBuilder)
+	return ret0
+}
+
+// SetCustomGRPCServer indicates an expected call of SetCustomGRPCServer.
+func (mr *MockInternalGRPCBuilderMockRecorder) SetCustomGRPCServer(customServer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCustomGRPCServer", reflect.TypeOf((*MockInternalGRPCBuilder)(nil).SetCustomGRPCServer), customServer)
+}
+
+// SetCustomListener mocks base method.
+func (m *MockInternalGRPCBuilder) SetCustomListener(listener net.Listener) server.InternalGRPCBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCustomListener", listener)
+	ret0, _ := ret[0].(server.InternalGRPCBuilder)
+	return ret0
+}
+
+// SetCustomListener indicates an expected call of SetCustomListener.
+func (mr *MockInternalGRPCBuilderMockRecorder) SetCustomListener(listener interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCustomListener", reflect.TypeOf((*MockInternalGRPCBuilder)(nil).SetCustomListener), listener)
+}
+
 // MockRESTBuilder is a mock of RESTBuilder interface.
 type MockRESTBuilder struct {
 	ctrl     *gomock.Controller
@@ -340,6 +519,20 @@ func (mr *MockRESTBuilderMockRecorder) BuildRESTPart() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildRESTPart", reflect.TypeOf((*MockRESTBuilder)(nil).BuildRESTPart))
 }
 
+// EnableGRPCWeb mocks base method.
+func (m *MockRESTBuilder) EnableGRPCWeb(options server.GRPCWebOptions) server.RESTBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableGRPCWeb", options)
+	ret0, _ := ret[0].(server.RESTBuilder)
+	return ret0
+}
+
+// EnableGRPCWeb indicates an expected call of EnableGRPCWeb.
+func (mr *MockRESTBuilderMockRecorder) EnableGRPCWeb(options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableGRPCWeb", reflect.TypeOf((*MockRESTBuilder)(nil).EnableGRPCWeb), options)
+}
+
 // ListenOn mocks base method.
 func (m *MockRESTBuilder) ListenOn(addr string) server.RESTBuilder {
 	m.ctrl.T.Helper()
@@ -413,3 +606,17 @@ func (mr *MockRESTBuilderMockRecorder) SetCustomServer(customServer interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCustomServer", reflect.TypeOf((*MockRESTBuilder)(nil).SetCustomServer), customServer)
 }
+
+// UseInternalGRPCServer mocks base method.
+func (m *MockRESTBuilder) UseInternalGRPCServer() server.RESTBuilder {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseInternalGRPCServer")
+	ret0, _ := ret[0].(server.RESTBuilder)
+	return ret0
+}
+
+// UseInternalGRPCServer indicates an expected call of UseInternalGRPCServer.
+func (mr *MockRESTBuilderMockRecorder) UseInternalGRPCServer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseInternalGRPCServer", reflect.TypeOf((*MockRESTBuilder)(nil).UseInternalGRPCServer))
+}
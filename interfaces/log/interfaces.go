@@ -38,6 +38,11 @@ func (l Level) String() string {
 	}
 }
 
+// MarshalText marshals level as its string representation
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
 // ParseLevel tries to parse level from string, if unable to parse a Trace level will be returned as a default
 func ParseLevel(str string) Level {
 	switch strings.ToLower(str) {
@@ -139,3 +144,20 @@ type Logger interface {
 	// Implementor returns the actual lib/struct that is responsible for the above logic
 	Configuration() LoggerConfiguration
 }
+
+// NamedLogger is an optional Logger capability, it allows to create named child loggers.
+//
+// Named loggers can have their own log level, mortar logger for example resolves it from `mortar.logger.levels`
+type NamedLogger interface {
+	Logger
+	// Named returns a child logger, names are joined with a dot: `parent.child`
+	Named(name string) Logger
+}
+
+// Named returns a named child logger if logger implements NamedLogger, otherwise logger itself is returned
+func Named(logger Logger, name string) Logger {
+	if named, ok := logger.(NamedLogger); ok {
+		return named.Named(name)
+	}
+	return logger
+}
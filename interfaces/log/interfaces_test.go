@@ -1,6 +1,7 @@
 package log
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,3 +33,9 @@ func TestParseLevel(t *testing.T) {
 		assert.Equal(t, lvl, ParseLevel(str))
 	}
 }
+
+func TestLevelMarshalJSON(t *testing.T) {
+	output, err := json.Marshal(map[string]Level{"level": WarnLevel})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"level":"warn"}`, string(output))
+}
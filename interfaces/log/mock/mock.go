@@ -572,3 +572,184 @@ func (mr *MockLoggerMockRecorder) WithField(name, value interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithField", reflect.TypeOf((*MockLogger)(nil).WithField), name, value)
 }
+
+// MockNamedLogger is a mock of NamedLogger interface.
+type MockNamedLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockNamedLoggerMockRecorder
+}
+
+// MockNamedLoggerMockRecorder is the mock recorder for MockNamedLogger.
+type MockNamedLoggerMockRecorder struct {
+	mock *MockNamedLogger
+}
+
+// NewMockNamedLogger creates a new mock instance.
+func NewMockNamedLogger(ctrl *gomock.Controller) *MockNamedLogger {
+	mock := &MockNamedLogger{ctrl: ctrl}
+	mock.recorder = &MockNamedLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNamedLogger) EXPECT() *MockNamedLoggerMockRecorder {
+	return m.recorder
+}
+
+// Configuration mocks base method.
+func (m *MockNamedLogger) Configuration() log.LoggerConfiguration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Configuration")
+	ret0, _ := ret[0].(log.LoggerConfiguration)
+	return ret0
+}
+
+// Configuration indicates an expected call of Configuration.
+func (mr *MockNamedLoggerMockRecorder) Configuration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Configuration", reflect.TypeOf((*MockNamedLogger)(nil).Configuration))
+}
+
+// Custom mocks base method.
+func (m *MockNamedLogger) Custom(ctx context.Context, level log.Level, skipAdditionalFrames int, format string, args ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, level, skipAdditionalFrames, format}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Custom", varargs...)
+}
+
+// Custom indicates an expected call of Custom.
+func (mr *MockNamedLoggerMockRecorder) Custom(ctx, level, skipAdditionalFrames, format interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, level, skipAdditionalFrames, format}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Custom", reflect.TypeOf((*MockNamedLogger)(nil).Custom), varargs...)
+}
+
+// Debug mocks base method.
+func (m *MockNamedLogger) Debug(ctx context.Context, format string, args ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, format}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Debug", varargs...)
+}
+
+// Debug indicates an expected call of Debug.
+func (mr *MockNamedLoggerMockRecorder) Debug(ctx, format interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, format}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Debug", reflect.TypeOf((*MockNamedLogger)(nil).Debug), varargs...)
+}
+
+// Error mocks base method.
+func (m *MockNamedLogger) Error(ctx context.Context, format string, args ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, format}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Error", varargs...)
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockNamedLoggerMockRecorder) Error(ctx, format interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, format}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockNamedLogger)(nil).Error), varargs...)
+}
+
+// Info mocks base method.
+func (m *MockNamedLogger) Info(ctx context.Context, format string, args ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, format}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Info", varargs...)
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockNamedLoggerMockRecorder) Info(ctx, format interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, format}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockNamedLogger)(nil).Info), varargs...)
+}
+
+// Named mocks base method.
+func (m *MockNamedLogger) Named(name string) log.Logger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Named", name)
+	ret0, _ := ret[0].(log.Logger)
+	return ret0
+}
+
+// Named indicates an expected call of Named.
+func (mr *MockNamedLoggerMockRecorder) Named(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Named", reflect.TypeOf((*MockNamedLogger)(nil).Named), name)
+}
+
+// Trace mocks base method.
+func (m *MockNamedLogger) Trace(ctx context.Context, format string, args ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, format}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Trace", varargs...)
+}
+
+// Trace indicates an expected call of Trace.
+func (mr *MockNamedLoggerMockRecorder) Trace(ctx, format interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, format}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trace", reflect.TypeOf((*MockNamedLogger)(nil).Trace), varargs...)
+}
+
+// Warn mocks base method.
+func (m *MockNamedLogger) Warn(ctx context.Context, format string, args ...interface{}) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, format}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Warn", varargs...)
+}
+
+// Warn indicates an expected call of Warn.
+func (mr *MockNamedLoggerMockRecorder) Warn(ctx, format interface{}, args ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, format}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warn", reflect.TypeOf((*MockNamedLogger)(nil).Warn), varargs...)
+}
+
+// WithError mocks base method.
+func (m *MockNamedLogger) WithError(err error) log.Fields {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithError", err)
+	ret0, _ := ret[0].(log.Fields)
+	return ret0
+}
+
+// WithError indicates an expected call of WithError.
+func (mr *MockNamedLoggerMockRecorder) WithError(err interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithError", reflect.TypeOf((*MockNamedLogger)(nil).WithError), err)
+}
+
+// WithField mocks base method.
+func (m *MockNamedLogger) WithField(name string, value interface{}) log.Fields {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithField", name, value)
+	ret0, _ := ret[0].(log.Fields)
+	return ret0
+}
+
+// WithField indicates an expected call of WithField.
+func (mr *MockNamedLoggerMockRecorder) WithField(name, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithField", reflect.TypeOf((*MockNamedLogger)(nil).WithField), name, value)
+}
@@ -0,0 +1,148 @@
+// Package async provides a log.Builder wrapper that writes log entries in the background.
+//
+// Log calls only enqueue entries to a bounded queue, a single goroutine writes them using the wrapped logger.
+// What happens when the queue is full is defined by Policy.
+//
+// **Important**
+//
+//	Entries are written from another goroutine, hence caller information (file:line) of the wrapped logger is meaningless.
+//	Messages are formatted when the entry is enqueued.
+package async
+
+import (
+	"container/list"
+	"strings"
+
+	logInt "github.com/go-masonry/mortar/interfaces/log"
+)
+
+// Policy defines what happens when the queue is full
+type Policy int8
+
+const (
+	// Block waits until there is room in the queue, nothing is dropped
+	Block Policy = iota
+	// DropNewest drops the entry that is being logged
+	DropNewest
+	// DropOldest drops the oldest entry in the queue to make room for the new one
+	DropOldest
+)
+
+const defaultQueueSize = 1024
+
+func (p Policy) String() string {
+	switch p {
+	case DropNewest:
+		return "dropNewest"
+	case DropOldest:
+		return "dropOldest"
+	default:
+		return "block"
+	}
+}
+
+// ParsePolicy tries to parse policy from string, if unable to parse Block will be returned as a default
+func ParsePolicy(str string) Policy {
+	switch strings.ToLower(str) {
+	case "dropnewest":
+		return DropNewest
+	case "dropoldest":
+		return DropOldest
+	default:
+		return Block
+	}
+}
+
+type asyncConfig struct {
+	queueSize int
+	policy    Policy
+	onBuild   []func(logger Logger)
+}
+
+// LogBuilder is a log.Builder that builds an asynchronous Logger
+type LogBuilder interface {
+	logInt.Builder
+	// SetQueueSize sets queue capacity, default is 1024
+	SetQueueSize(size int) LogBuilder
+	// SetPolicy sets what happens when the queue is full, default is Block
+	SetPolicy(policy Policy) LogBuilder
+	// OnBuild registers a callback that is called with the built Logger, useful when Build is called by someone else
+	// and Logger should be closed when the application stops
+	OnBuild(callback func(logger Logger)) LogBuilder
+	// BuildAsync is the same as Build, but returns the Logger to allow Flush and Close
+	BuildAsync() Logger
+}
+
+type asyncBuilder struct {
+	inner logInt.Builder
+	ll    *list.List
+}
+
+// Wrap wraps any log.Builder implementation, the built logger will log asynchronously.
+//
+// Logger must be closed to flush the queue.
+//
+//	logger := async.Wrap(naive.Builder()).SetPolicy(async.DropOldest).BuildAsync()
+//	defer logger.Close(ctx)
+func Wrap(builder logInt.Builder) LogBuilder {
+	return &asyncBuilder{
+		inner: builder,
+		ll:    list.New(),
+	}
+}
+
+func (a *asyncBuilder) SetQueueSize(size int) LogBuilder {
+	a.ll.PushBack(func(cfg *asyncConfig) {
+		cfg.queueSize = size
+	})
+	return a
+}
+
+func (a *asyncBuilder) SetPolicy(policy Policy) LogBuilder {
+	a.ll.PushBack(func(cfg *asyncConfig) {
+		cfg.policy = policy
+	})
+	return a
+}
+
+func (a *asyncBuilder) OnBuild(callback func(logger Logger)) LogBuilder {
+	a.ll.PushBack(func(cfg *asyncConfig) {
+		cfg.onBuild = append(cfg.onBuild, callback)
+	})
+	return a
+}
+
+func (a *asyncBuilder) IncrementSkipFrames(addition int) logInt.Builder {
+	a.inner = a.inner.IncrementSkipFrames(addition)
+	return a
+}
+
+func (a *asyncBuilder) SetLevel(level logInt.Level) logInt.Builder {
+	a.inner = a.inner.SetLevel(level)
+	return a
+}
+
+func (a *asyncBuilder) Build() logInt.Logger {
+	return a.BuildAsync()
+}
+
+func (a *asyncBuilder) BuildAsync() Logger {
+	cfg := &asyncConfig{
+		queueSize: defaultQueueSize,
+		policy:    Block,
+	}
+	for e := a.ll.Front(); e != nil; e = e.Next() {
+		f := e.Value.(func(cfg *asyncConfig))
+		f(cfg)
+	}
+	if cfg.queueSize <= 0 {
+		cfg.queueSize = defaultQueueSize
+	}
+	logger := newAsyncLogger(cfg, a.inner.Build())
+	for _, callback := range cfg.onBuild {
+		callback(logger)
+	}
+	return logger
+}
+
+var _ LogBuilder = (*asyncBuilder)(nil)
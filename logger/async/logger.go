@@ -0,0 +1,319 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	logInt "github.com/go-masonry/mortar/interfaces/log"
+)
+
+// Logger is an asynchronous log.Logger
+type Logger interface {
+	logInt.Logger
+	// Flush blocks until every entry that was enqueued before calling it is written, or ctx is done
+	Flush(ctx context.Context) error
+	// Close flushes the queue and stops the background goroutine, entries logged after Close are written synchronously
+	Close(ctx context.Context) error
+	// Dropped returns the number of entries that were dropped due to a full queue
+	Dropped() uint64
+}
+
+// maxSnapshotDepth limits how deep field values are copied, it also protects from cyclic values
+const maxSnapshotDepth = 8
+
+type entry struct {
+	ctx     context.Context
+	level   logInt.Level
+	message string
+	fields  []field
+	err     error
+	flushed chan struct{} // flush marker
+}
+
+type field struct {
+	name  string
+	value interface{}
+}
+
+type asyncLogger struct {
+	sync.RWMutex
+	inner   logInt.Logger
+	policy  Policy
+	queue   chan entry
+	done    chan struct{}
+	closed  bool
+	dropped uint64
+}
+
+func newAsyncLogger(cfg *asyncConfig, inner logInt.Logger) *asyncLogger {
+	logger := &asyncLogger{
+		inner:  inner,
+		policy: cfg.policy,
+		queue:  make(chan entry, cfg.queueSize),
+		done:   make(chan struct{}),
+	}
+	go logger.run()
+	return logger
+}
+
+func (a *asyncLogger) run() {
+	defer close(a.done)
+	for e := range a.queue {
+		if e.flushed != nil {
+			close(e.flushed)
+			continue
+		}
+		a.write(e)
+	}
+}
+
+func (a *asyncLogger) write(e entry) {
+	var logger logInt.Fields = a.inner
+	for _, f := range e.fields {
+		logger = logger.WithField(f.name, f.value)
+	}
+	if e.err != nil {
+		logger = logger.WithError(e.err)
+	}
+	logger.Custom(e.ctx, e.level, 0, "%s", e.message)
+}
+
+func (a *asyncLogger) enqueue(e entry) {
+	a.RLock()
+	defer a.RUnlock()
+	if a.closed {
+		a.write(e)
+		return
+	}
+	switch a.policy {
+	case DropNewest:
+		select {
+		case a.queue <- e:
+		default:
+			atomic.AddUint64(&a.dropped, 1)
+		}
+	case DropOldest:
+		for {
+			select {
+			case a.queue <- e:
+				return
+			default:
+				select {
+				case oldest := <-a.queue:
+					if oldest.flushed != nil { // never drop flush markers
+						close(oldest.flushed)
+					} else {
+						atomic.AddUint64(&a.dropped, 1)
+					}
+				default:
+				}
+			}
+		}
+	default:
+		a.queue <- e
+	}
+}
+
+func (a *asyncLogger) Flush(ctx context.Context) error {
+	a.RLock()
+	defer a.RUnlock()
+	if a.closed {
+		return nil
+	}
+	marker := entry{flushed: make(chan struct{})}
+	select {
+	case a.queue <- marker:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-marker.flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (a *asyncLogger) Close(ctx context.Context) error {
+	a.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.Unlock()
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (a *asyncLogger) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+func (a *asyncLogger) Trace(ctx context.Context, format string, args ...interface{}) {
+	a.Custom(ctx, logInt.TraceLevel, 0, format, args...)
+}
+
+func (a *asyncLogger) Debug(ctx context.Context, format string, args ...interface{}) {
+	a.Custom(ctx, logInt.DebugLevel, 0, format, args...)
+}
+
+func (a *asyncLogger) Info(ctx context.Context, format string, args ...interface{}) {
+	a.Custom(ctx, logInt.InfoLevel, 0, format, args...)
+}
+
+func (a *asyncLogger) Warn(ctx context.Context, format string, args ...interface{}) {
+	a.Custom(ctx, logInt.WarnLevel, 0, format, args...)
+}
+
+func (a *asyncLogger) Error(ctx context.Context, format string, args ...interface{}) {
+	a.Custom(ctx, logInt.ErrorLevel, 0, format, args...)
+}
+
+func (a *asyncLogger) Custom(ctx context.Context, level logInt.Level, _ int, format string, args ...interface{}) {
+	(&asyncEntry{logger: a}).Custom(ctx, level, 0, format, args...)
+}
+
+func (a *asyncLogger) WithError(err error) logInt.Fields {
+	return (&asyncEntry{logger: a}).WithError(err)
+}
+
+func (a *asyncLogger) WithField(name string, value interface{}) logInt.Fields {
+	return (&asyncEntry{logger: a}).WithField(name, value)
+}
+
+func (a *asyncLogger) Configuration() logInt.LoggerConfiguration {
+	return a.inner.Configuration()
+}
+
+// asyncEntry accumulates fields until one of the log methods is called
+type asyncEntry struct {
+	logger *asyncLogger
+	fields []field
+	err    error
+}
+
+func (e *asyncEntry) Trace(ctx context.Context, format string, args ...interface{}) {
+	e.Custom(ctx, logInt.TraceLevel, 0, format, args...)
+}
+
+func (e *asyncEntry) Debug(ctx context.Context, format string, args ...interface{}) {
+	e.Custom(ctx, logInt.DebugLevel, 0, format, args...)
+}
+
+func (e *asyncEntry) Info(ctx context.Context, format string, args ...interface{}) {
+	e.Custom(ctx, logInt.InfoLevel, 0, format, args...)
+}
+
+func (e *asyncEntry) Warn(ctx context.Context, format string, args ...interface{}) {
+	e.Custom(ctx, logInt.WarnLevel, 0, format, args...)
+}
+
+func (e *asyncEntry) Error(ctx context.Context, format string, args ...interface{}) {
+	e.Custom(ctx, logInt.ErrorLevel, 0, format, args...)
+}
+
+func (e *asyncEntry) Custom(ctx context.Context, level logInt.Level, _ int, format string, args ...interface{}) {
+	if level < e.logger.inner.Configuration().Level() {
+		return
+	}
+	message := format
+	if len(args) > 0 {
+		message = fmt.Sprintf(format, args...)
+	}
+	fields := make([]field, len(e.fields))
+	for i, f := range e.fields {
+		fields[i] = field{name: f.name, value: snapshot(f.value)}
+	}
+	e.logger.enqueue(entry{
+		ctx:     ctx,
+		level:   level,
+		message: message,
+		fields:  fields,
+		err:     e.err,
+	})
+}
+
+func (e *asyncEntry) WithError(err error) logInt.Fields {
+	e.err = err
+	return e
+}
+
+func (e *asyncEntry) WithField(name string, value interface{}) logInt.Fields {
+	e.fields = append(e.fields, field{name: name, value: value})
+	return e
+}
+
+// snapshot deep copies maps, slices and pointers, so the caller can change them once the entry is enqueued.
+//
+// Unexported struct fields and values nested deeper than maxSnapshotDepth are still shared
+func snapshot(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return snapshotValue(reflect.ValueOf(value), 0).Interface()
+}
+
+func snapshotValue(value reflect.Value, depth int) reflect.Value {
+	if depth >= maxSnapshotDepth {
+		return value
+	}
+	switch value.Kind() {
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		output := reflect.MakeMapWithSize(value.Type(), value.Len())
+		for iter := value.MapRange(); iter.Next(); {
+			output.SetMapIndex(iter.Key(), snapshotValue(iter.Value(), depth+1))
+		}
+		return output
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		output := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			output.Index(i).Set(snapshotValue(value.Index(i), depth+1))
+		}
+		return output
+	case reflect.Array:
+		output := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.Len(); i++ {
+			output.Index(i).Set(snapshotValue(value.Index(i), depth+1))
+		}
+		return output
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+		output := reflect.New(value.Type().Elem())
+		output.Elem().Set(snapshotValue(value.Elem(), depth+1))
+		return output
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		output := reflect.New(value.Type()).Elem()
+		output.Set(snapshotValue(value.Elem(), depth+1))
+		return output
+	case reflect.Struct:
+		output := reflect.New(value.Type()).Elem()
+		output.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if output.Field(i).CanSet() {
+				output.Field(i).Set(snapshotValue(value.Field(i), depth+1))
+			}
+		}
+		return output
+	}
+	return value
+}
+
+var _ Logger = (*asyncLogger)(nil)
@@ -0,0 +1,135 @@
+package async
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logInt "github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/logger/naive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedWriter blocks every write until the gate is opened
+type gatedWriter struct {
+	sync.Mutex
+	gate   chan struct{}
+	buffer bytes.Buffer
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{gate: make(chan struct{})}
+}
+
+func (g *gatedWriter) Write(p []byte) (int, error) {
+	<-g.gate
+	g.Lock()
+	defer g.Unlock()
+	return g.buffer.Write(p)
+}
+
+func (g *gatedWriter) String() string {
+	g.Lock()
+	defer g.Unlock()
+	return g.buffer.String()
+}
+
+func TestAsyncFieldsAndFlush(t *testing.T) {
+	writer := newGatedWriter()
+	close(writer.gate)
+	logger := Wrap(naive.Builder().SetWriter(writer).JSON().SetLevel(logInt.InfoLevel)).BuildAsync()
+	logger.WithField("field", "value").WithError(errors.New("an error")).Info(nil, "info %d", 1)
+	logger.Debug(context.Background(), "not printed")
+	require.NoError(t, logger.Flush(context.Background()))
+	assert.Contains(t, writer.String(), `"message":"info 1","error":"an error","field":"value"`)
+	assert.NotContains(t, writer.String(), "not printed")
+	require.NoError(t, logger.Close(context.Background()))
+	logger.Info(nil, "after close")
+	assert.Contains(t, writer.String(), "after close", "written synchronously")
+}
+
+func TestAsyncSnapshotsFields(t *testing.T) {
+	writer := newGatedWriter()
+	logger := Wrap(naive.Builder().SetWriter(writer).JSON()).BuildAsync()
+	values := map[string]interface{}{"key": "before", "list": []string{"before"}}
+	counter := 1
+	logger.WithField("values", values).WithField("counter", &counter).Info(nil, "snapshot")
+	values["key"] = "after"
+	values["list"].([]string)[0] = "after"
+	counter = 2
+	close(writer.gate)
+	require.NoError(t, logger.Flush(context.Background()))
+	assert.Contains(t, writer.String(), `"values":{"key":"before","list":["before"]}`)
+	assert.NotContains(t, writer.String(), "after")
+}
+
+func TestAsyncDisabledLevelNotQueued(t *testing.T) {
+	writer := newGatedWriter()
+	logger := Wrap(naive.Builder().SetWriter(writer).SetLevel(logInt.InfoLevel)).SetQueueSize(1).SetPolicy(DropNewest).BuildAsync()
+	for i := 0; i < 10; i++ {
+		logger.Debug(nil, "debug %d", i)
+	}
+	assert.Empty(t, logger.(*asyncLogger).queue)
+	assert.Zero(t, logger.Dropped())
+	close(writer.gate)
+	require.NoError(t, logger.Close(context.Background()))
+}
+
+func TestAsyncDropNewest(t *testing.T) {
+	writer := newGatedWriter()
+	logger := Wrap(naive.Builder().SetWriter(writer)).SetQueueSize(2).SetPolicy(DropNewest).BuildAsync()
+	logger.Info(nil, "first") // taken by the background goroutine, blocked on write
+	assert.Eventually(t, func() bool { return len(logger.(*asyncLogger).queue) == 0 }, time.Second, time.Millisecond)
+	for _, message := range []string{"second", "third", "fourth", "fifth"} {
+		logger.Info(nil, message)
+	}
+	assert.EqualValues(t, 2, logger.Dropped())
+	close(writer.gate)
+	require.NoError(t, logger.Close(context.Background()))
+	output := writer.String()
+	assert.Contains(t, output, "third")
+	assert.NotContains(t, output, "fourth")
+	assert.NotContains(t, output, "fifth")
+}
+
+func TestAsyncDropOldest(t *testing.T) {
+	writer := newGatedWriter()
+	logger := Wrap(naive.Builder().SetWriter(writer)).SetQueueSize(2).SetPolicy(DropOldest).BuildAsync()
+	logger.Info(nil, "first")
+	assert.Eventually(t, func() bool { return len(logger.(*asyncLogger).queue) == 0 }, time.Second, time.Millisecond)
+	for _, message := range []string{"second", "third", "fourth", "fifth"} {
+		logger.Info(nil, message)
+	}
+	assert.EqualValues(t, 2, logger.Dropped())
+	close(writer.gate)
+	require.NoError(t, logger.Close(context.Background()))
+	output := writer.String()
+	assert.NotContains(t, output, "second")
+	assert.NotContains(t, output, "third")
+	assert.True(t, strings.Index(output, "fourth") < strings.Index(output, "fifth"))
+}
+
+func TestAsyncBlockFlushTimeout(t *testing.T) {
+	writer := newGatedWriter()
+	logger := Wrap(naive.Builder().SetWriter(writer).SetLevel(logInt.TraceLevel)).BuildAsync()
+	logger.Trace(nil, "blocked")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, logger.Flush(ctx), context.DeadlineExceeded)
+	close(writer.gate)
+	require.NoError(t, logger.Close(context.Background()))
+	assert.Contains(t, writer.String(), "blocked")
+	assert.Zero(t, logger.Dropped())
+}
+
+func TestParsePolicy(t *testing.T) {
+	for _, policy := range []Policy{Block, DropNewest, DropOldest} {
+		assert.Equal(t, policy, ParsePolicy(policy.String()))
+	}
+	assert.Equal(t, Block, ParsePolicy("whatever"))
+}
@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"container/list"
+
+	"github.com/go-masonry/mortar/interfaces/log"
+)
+
+type wrapperConfig struct {
+	extractors []log.ContextExtractor
+	level      *log.Level
+	named      map[string]log.Level
+	sampling   *SamplingPolicy
+	redact     FieldRedactor
+	recent     *RecentLogsPolicy
+}
+
+// FieldRedactor returns a value that is safe to log, see utils.Redactor.Value
+type FieldRedactor func(name string, value interface{}) interface{}
+
+// WrapperBuilder is a helper builder to define internal Mortar logger wrapper
+type WrapperBuilder interface {
+	// Build builds mortar log.Logger
+	//
+	// **Important**
+	//
+	//	Build will call builder.IncrementSkipFrames to peel additional layer of itself.
+	Build(builder log.Builder) log.Logger
+	// AddExtractors adds ContextExtractors that will enrich every log entry
+	AddExtractors(extractors ...log.ContextExtractor) WrapperBuilder
+	// SetLevel sets an initial log level that can be changed at runtime, see AtomicLevel.
+	//
+	// When set, the provided log.Builder is set to log.TraceLevel and filtering is done by the wrapper.
+	SetLevel(level log.Level) WrapperBuilder
+	// SetNamedLevels sets log levels of named loggers, name prefix to log level, see log.Named.
+	//
+	// The longest matching prefix wins, `http` matches `http.client` but not `https`.
+	// Named levels are not affected by runtime level changes.
+	//
+	// **Note**
+	//
+	//	Without SetLevel the provided log.Builder level is kept, so named levels can't go below it.
+	SetNamedLevels(levels map[string]log.Level) WrapperBuilder
+	// SetSampling enables log sampling and rate limiting, see SamplingPolicy
+	SetSampling(policy SamplingPolicy) WrapperBuilder
+	// SetFieldRedactor sets a redactor that is applied on every field value, including values of ContextExtractors
+	SetFieldRedactor(redact FieldRedactor) WrapperBuilder
+	// SetRecentLogs keeps the last log entries in memory, see RecentLogsPolicy and Recorded
+	SetRecentLogs(policy RecentLogsPolicy) WrapperBuilder
+}
+
+type wrapperBuilder struct {
+	ll *list.List
+}
+
+// Builder creates a WrapperBuilder
+func Builder() WrapperBuilder {
+	return &wrapperBuilder{
+		ll: list.New(),
+	}
+}
+
+func (b *wrapperBuilder) AddExtractors(extractors ...log.ContextExtractor) WrapperBuilder {
+	b.ll.PushBack(func(cfg *wrapperConfig) {
+		cfg.extractors = append(cfg.extractors, extractors...)
+	})
+	return b
+}
+
+func (b *wrapperBuilder) SetLevel(level log.Level) WrapperBuilder {
+	b.ll.PushBack(func(cfg *wrapperConfig) {
+		cfg.level = &level
+	})
+	return b
+}
+
+func (b *wrapperBuilder) SetNamedLevels(levels map[string]log.Level) WrapperBuilder {
+	b.ll.PushBack(func(cfg *wrapperConfig) {
+		if cfg.named == nil {
+			cfg.named = make(map[string]log.Level, len(levels))
+		}
+		for name, level := range levels {
+			cfg.named[name] = level
+		}
+	})
+	return b
+}
+
+func (b *wrapperBuilder) SetSampling(policy SamplingPolicy) WrapperBuilder {
+	b.ll.PushBack(func(cfg *wrapperConfig) {
+		cfg.sampling = &policy
+	})
+	return b
+}
+
+func (b *wrapperBuilder) SetFieldRedactor(redact FieldRedactor) WrapperBuilder {
+	b.ll.PushBack(func(cfg *wrapperConfig) {
+		cfg.redact = redact
+	})
+	return b
+}
+
+func (b *wrapperBuilder) SetRecentLogs(policy RecentLogsPolicy) WrapperBuilder {
+	b.ll.PushBack(func(cfg *wrapperConfig) {
+		cfg.recent = &policy
+	})
+	return b
+}
+
+func (b *wrapperBuilder) Build(builder log.Builder) log.Logger {
+	cfg := new(wrapperConfig)
+	for e := b.ll.Front(); e != nil; e = e.Next() {
+		f := e.Value.(func(*wrapperConfig))
+		f(cfg)
+	}
+	if cfg.level != nil {
+		builder = builder.SetLevel(log.TraceLevel)
+	}
+	return newLoggerWrapper(cfg, builder)
+}
+
+var _ WrapperBuilder = (*wrapperBuilder)(nil)
@@ -12,16 +12,26 @@ const (
 
 type contextAwareLogEntry struct {
 	contextExtractors []log.ContextExtractor
+	level             *atomicLevel
+	sampler           *sampler
+	redact            FieldRedactor
+	recent            *recentLogs
+	name              string
 	innerLogger       log.Fields
 	fields            map[string]interface{}
 	err               error
 	withFields        bool
 }
 
-func newEntry(contextExtractors []log.ContextExtractor, logger log.Fields, withFields bool) log.Fields {
+func newEntry(wrapper *loggerWrapper, withFields bool) log.Fields {
 	return &contextAwareLogEntry{
-		contextExtractors: contextExtractors,
-		innerLogger:       logger,
+		contextExtractors: wrapper.contextExtractors,
+		level:             wrapper.level,
+		sampler:           wrapper.sampler,
+		redact:            wrapper.redact,
+		recent:            wrapper.recent,
+		name:              wrapper.name,
+		innerLogger:       wrapper.logger,
 		fields:            make(map[string]interface{}),
 		err:               nil,
 		withFields:        withFields,
@@ -63,23 +73,48 @@ func (c *contextAwareLogEntry) WithField(name string, value interface{}) log.Fie
 }
 
 func (c *contextAwareLogEntry) log(ctx context.Context, level log.Level, skipAdditionalFrames int, format string, args ...interface{}) {
+	if !c.level.enabled(c.name, level) {
+		return
+	}
+	if c.sampler != nil && !c.sampler.allow(level, format) {
+		return
+	}
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	logger := c.enrich(ctx)
+	var recorded map[string]interface{} // fields of recent log entry
+	if c.recent != nil && c.recent.enabled(level) && c.level.written(c.name, level) {
+		recorded = make(map[string]interface{})
+	}
+	logger := c.enrich(ctx, recorded)
+	if len(c.name) > 0 {
+		logger = logger.WithField(NameField, c.name)
+		c.record(recorded, NameField, c.name)
+	}
 	for k, v := range c.fields {
-		logger = logger.WithField(k, v)
+		value := c.redactValue(k, v)
+		logger = logger.WithField(k, value)
+		c.record(recorded, k, value)
 	}
 	if c.err != nil {
 		logger = logger.WithError(c.err)
 	}
+	if recorded != nil {
+		c.recent.record(level, c.err, recorded, format, args...)
+	}
 	if !c.withFields { // if no fields, we have one less layer to peel
 		skipAdditionalFrames++
 	}
 	logger.Custom(ctx, level, skipAdditionalFrames, format, args...)
 }
 
-func (c *contextAwareLogEntry) enrich(ctx context.Context) (logger log.Fields) {
+func (c *contextAwareLogEntry) record(recorded map[string]interface{}, name string, value interface{}) {
+	if recorded != nil {
+		recorded[name] = value
+	}
+}
+
+func (c *contextAwareLogEntry) enrich(ctx context.Context, recorded map[string]interface{}) (logger log.Fields) {
 	defer func() {
 		if r := recover(); r != nil {
 			c.innerLogger.WithField("__panic__", r).Error(ctx, "one of the context extractors panicked")
@@ -89,8 +124,17 @@ func (c *contextAwareLogEntry) enrich(ctx context.Context) (logger log.Fields) {
 	logger = c.innerLogger
 	for _, extractor := range c.contextExtractors {
 		for k, v := range extractor(ctx) {
-			logger = logger.WithField(k, v)
+			value := c.redactValue(k, v)
+			logger = logger.WithField(k, value)
+			c.record(recorded, k, value)
 		}
 	}
 	return
 }
+
+func (c *contextAwareLogEntry) redactValue(name string, value interface{}) interface{} {
+	if c.redact == nil {
+		return value
+	}
+	return c.redact(name, value)
+}
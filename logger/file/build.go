@@ -0,0 +1,120 @@
+// Package file provides a rotating file io.Writer that can be used as a log output, for example
+//
+//	writer, err := file.Builder().SetPath("/var/log/app.log").SetMaxSize(100 << 20).SetMaxBackups(5).Compress().Build()
+//	logger := naive.Builder().SetWriter(writer).Build()
+//
+// Rotated files are renamed to `<name>-<timestamp><ext>` in the same directory, `app-2006-01-02T15-04-05.000.log`.
+package file
+
+import (
+	"container/list"
+	"fmt"
+	"os"
+	"time"
+)
+
+type fileConfig struct {
+	path       string
+	maxSize    int64
+	interval   time.Duration
+	maxBackups int
+	maxAge     time.Duration
+	compress   bool
+	signals    []os.Signal
+}
+
+// WriterBuilder is a helper builder to configure a rotating Writer
+type WriterBuilder interface {
+	// SetPath sets the file path, it's created with its directories if missing. Mandatory
+	SetPath(path string) WriterBuilder
+	// SetMaxSize rotates the file before it gets bigger than size bytes, 0 disables size based rotation
+	SetMaxSize(size int64) WriterBuilder
+	// SetInterval rotates the file every interval, aligned to the interval (an hour rotates on every round hour).
+	// 0 disables time based rotation
+	SetInterval(interval time.Duration) WriterBuilder
+	// SetMaxBackups removes old rotated files if there are more than count, 0 keeps all of them
+	SetMaxBackups(count int) WriterBuilder
+	// SetMaxAge removes rotated files that are older than age, 0 keeps all of them
+	SetMaxAge(age time.Duration) WriterBuilder
+	// Compress gzips rotated files
+	Compress() WriterBuilder
+	// ReopenOn reopens the file when one of the signals is received, usually syscall.SIGHUP
+	// which is sent by external rotation tools such as logrotate
+	ReopenOn(signals ...os.Signal) WriterBuilder
+	// Build opens the file and returns a Writer
+	Build() (Writer, error)
+}
+
+type writerBuilder struct {
+	ll *list.List
+}
+
+// Builder creates a WriterBuilder
+func Builder() WriterBuilder {
+	return &writerBuilder{
+		ll: list.New(),
+	}
+}
+
+func (b *writerBuilder) SetPath(path string) WriterBuilder {
+	b.ll.PushBack(func(cfg *fileConfig) {
+		cfg.path = path
+	})
+	return b
+}
+
+func (b *writerBuilder) SetMaxSize(size int64) WriterBuilder {
+	b.ll.PushBack(func(cfg *fileConfig) {
+		cfg.maxSize = size
+	})
+	return b
+}
+
+func (b *writerBuilder) SetInterval(interval time.Duration) WriterBuilder {
+	b.ll.PushBack(func(cfg *fileConfig) {
+		cfg.interval = interval
+	})
+	return b
+}
+
+func (b *writerBuilder) SetMaxBackups(count int) WriterBuilder {
+	b.ll.PushBack(func(cfg *fileConfig) {
+		cfg.maxBackups = count
+	})
+	return b
+}
+
+func (b *writerBuilder) SetMaxAge(age time.Duration) WriterBuilder {
+	b.ll.PushBack(func(cfg *fileConfig) {
+		cfg.maxAge = age
+	})
+	return b
+}
+
+func (b *writerBuilder) Compress() WriterBuilder {
+	b.ll.PushBack(func(cfg *fileConfig) {
+		cfg.compress = true
+	})
+	return b
+}
+
+func (b *writerBuilder) ReopenOn(signals ...os.Signal) WriterBuilder {
+	b.ll.PushBack(func(cfg *fileConfig) {
+		cfg.signals = append(cfg.signals, signals...)
+	})
+	return b
+}
+
+func (b *writerBuilder) Build() (Writer, error) {
+	cfg := new(fileConfig)
+	for e := b.ll.Front(); e != nil; e = e.Next() {
+		f := e.Value.(func(cfg *fileConfig))
+		f(cfg)
+	}
+	if len(cfg.path) == 0 {
+		return nil, fmt.Errorf("log file path is not set")
+	}
+	return newRotatingWriter(cfg, time.Now)
+}
+
+var _ WriterBuilder = (*writerBuilder)(nil)
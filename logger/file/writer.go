@@ -0,0 +1,352 @@
+package file
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	compressSuffix   = ".gz"
+)
+
+// Writer is a rotating file io.Writer, it's safe for concurrent use.
+//
+// If the file can't be opened again after rotation or Reopen, writes go to stderr and opening is retried on every write.
+type Writer interface {
+	io.WriteCloser
+	// Rotate renames the current file to a backup and opens a new one
+	Rotate() error
+	// Reopen closes the current file and opens it again, useful when the file was moved by an external tool
+	Reopen() error
+}
+
+type backup struct {
+	path      string
+	timestamp time.Time
+	sequence  int // backups rotated within the same millisecond
+}
+
+type rotatingWriter struct {
+	sync.Mutex
+	cfg          *fileConfig
+	now          func() time.Time
+	file         *os.File  // nil while the file can't be opened, opening is retried on every write
+	fallback     io.Writer // receives writes while file is nil
+	closed       bool
+	size         int64
+	nextRotation time.Time
+	mill         chan struct{}
+	signals      chan os.Signal
+	stop         chan struct{}
+	wg           sync.WaitGroup
+}
+
+func newRotatingWriter(cfg *fileConfig, now func() time.Time) (*rotatingWriter, error) {
+	w := &rotatingWriter{
+		cfg:      cfg,
+		now:      now,
+		fallback: os.Stderr,
+		mill:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	w.wg.Add(1)
+	go w.runMill()
+	if len(cfg.signals) > 0 {
+		w.signals = make(chan os.Signal, 1)
+		signal.Notify(w.signals, cfg.signals...)
+		w.wg.Add(1)
+		go w.reopenOnSignal()
+	}
+	w.millAsync() // there can be leftovers from a previous run
+	return w, nil
+}
+
+func (w *rotatingWriter) Write(p []byte) (n int, err error) {
+	w.Lock()
+	defer w.Unlock()
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	if w.file != nil && w.shouldRotate(len(p)) {
+		if err = w.rotate(); err != nil {
+			fmt.Fprintf(os.Stderr, "[Mortar] WARNING \t%v\n", err)
+		}
+	}
+	if w.file == nil {
+		if err = w.open(); err != nil {
+			return w.fallback.Write(p) // don't lose entries while the file can't be opened
+		}
+	}
+	n, err = w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *rotatingWriter) Rotate() error {
+	w.Lock()
+	defer w.Unlock()
+	if w.closed {
+		return os.ErrClosed
+	}
+	return w.rotate()
+}
+
+func (w *rotatingWriter) Reopen() error {
+	w.Lock()
+	defer w.Unlock()
+	if w.closed {
+		return os.ErrClosed
+	}
+	if err := w.closeFile(); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+func (w *rotatingWriter) Close() (err error) {
+	w.Lock()
+	if w.closed {
+		w.Unlock()
+		return nil
+	}
+	w.closed = true
+	if w.signals != nil {
+		signal.Stop(w.signals)
+	}
+	close(w.stop)
+	err = w.closeFile()
+	w.Unlock()
+	w.wg.Wait()
+	return err
+}
+
+// closeFile closes the current file if there is one, it's never used again even if closing failed
+func (w *rotatingWriter) closeFile() (err error) {
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	return
+}
+
+func (w *rotatingWriter) shouldRotate(size int) bool {
+	if w.cfg.maxSize > 0 && w.size > 0 && w.size+int64(size) > w.cfg.maxSize {
+		return true
+	}
+	return w.cfg.interval > 0 && !w.now().Before(w.nextRotation)
+}
+
+func (w *rotatingWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.cfg.path), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory, %w", err)
+	}
+	file, err := os.OpenFile(w.cfg.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file, %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open log file, %w", err)
+	}
+	w.file = file
+	w.size = info.Size()
+	if w.cfg.interval > 0 {
+		w.nextRotation = w.now().Truncate(w.cfg.interval).Add(w.cfg.interval)
+	}
+	return nil
+}
+
+// rotate leaves file nil if any of the steps fail, so it will be opened again by the next write
+func (w *rotatingWriter) rotate() error {
+	if err := w.closeFile(); err != nil {
+		return fmt.Errorf("failed to rotate log file, %w", err)
+	}
+	if err := os.Rename(w.cfg.path, w.backupPath(w.now())); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate log file, %w", err)
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	w.millAsync()
+	return nil
+}
+
+// backupPath of a backup created at timestamp, a sequence suffix is added if there is already one with that name
+func (w *rotatingWriter) backupPath(timestamp time.Time) string {
+	dir, prefix, ext := w.backupParts()
+	name := prefix + timestamp.UTC().Format(backupTimeFormat)
+	for sequence := 0; ; sequence++ {
+		path := filepath.Join(dir, name+ext)
+		if sequence > 0 {
+			path = filepath.Join(dir, name+"."+strconv.Itoa(sequence)+ext)
+		}
+		if !exists(path) && !exists(path+compressSuffix) {
+			return path
+		}
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return !os.IsNotExist(err)
+}
+
+func (w *rotatingWriter) backupParts() (dir, prefix, ext string) {
+	dir, name := filepath.Split(w.cfg.path)
+	ext = filepath.Ext(name)
+	return dir, strings.TrimSuffix(name, ext) + "-", ext
+}
+
+func (w *rotatingWriter) reopenOnSignal() {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-w.signals:
+			if err := w.Reopen(); err != nil {
+				fmt.Fprintf(os.Stderr, "[Mortar] WARNING \tfailed to reopen log file, %v\n", err)
+			}
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// millAsync schedules removal and compression of backups
+func (w *rotatingWriter) millAsync() {
+	select {
+	case w.mill <- struct{}{}:
+	default: // already scheduled
+	}
+}
+
+func (w *rotatingWriter) runMill() {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-w.mill:
+			w.millBackups()
+		case <-w.stop:
+			select {
+			case <-w.mill: // don't leave uncompressed backups behind
+				w.millBackups()
+			default:
+			}
+			return
+		}
+	}
+}
+
+func (w *rotatingWriter) millBackups() {
+	backups, err := w.backups()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[Mortar] WARNING \tfailed to list log file backups, %v\n", err)
+		return
+	}
+	for i, b := range backups {
+		expired := w.cfg.maxAge > 0 && b.timestamp.Before(w.now().Add(-w.cfg.maxAge))
+		if expired || (w.cfg.maxBackups > 0 && i >= w.cfg.maxBackups) {
+			err = os.Remove(b.path)
+		} else if w.cfg.compress && !strings.HasSuffix(b.path, compressSuffix) {
+			err = compress(b.path)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[Mortar] WARNING \tfailed to handle log file backup %s, %v\n", b.path, err)
+		}
+	}
+}
+
+// backups returns rotated files, newest first
+func (w *rotatingWriter) backups() ([]backup, error) {
+	dir, prefix, ext := w.backupParts()
+	if len(dir) == 0 {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if b, ok := parseBackup(strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(name, prefix), compressSuffix), ext)); ok {
+			b.path = filepath.Join(dir, name)
+			backups = append(backups, b)
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].timestamp.Equal(backups[j].timestamp) {
+			return backups[i].sequence > backups[j].sequence
+		}
+		return backups[i].timestamp.After(backups[j].timestamp)
+	})
+	return backups, nil
+}
+
+// parseBackup parses `<timestamp>[.<sequence>]` part of a backup name
+func parseBackup(name string) (b backup, ok bool) {
+	if len(name) < len(backupTimeFormat) {
+		return b, false
+	}
+	var err error
+	if b.timestamp, err = time.Parse(backupTimeFormat, name[:len(backupTimeFormat)]); err != nil {
+		return b, false
+	}
+	if sequence := name[len(backupTimeFormat):]; len(sequence) > 0 {
+		if b.sequence, err = strconv.Atoi(strings.TrimPrefix(sequence, ".")); err != nil || sequence[0] != '.' || b.sequence <= 0 {
+			return b, false
+		}
+	}
+	return b, true
+}
+
+// compress gzips src and removes it, a partially written file is removed on failure
+func compress(src string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	dst := src + compressSuffix
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(dst)
+		}
+	}()
+	gz := gzip.NewWriter(out)
+	if _, err = io.Copy(gz, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	in.Close()
+	return os.Remove(src)
+}
+
+var _ Writer = (*rotatingWriter)(nil)
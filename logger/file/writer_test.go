@@ -0,0 +1,195 @@
+package file
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	sync.Mutex
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.Lock()
+	defer f.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Add(d time.Duration) {
+	f.Lock()
+	defer f.Unlock()
+	f.now = f.now.Add(d)
+}
+
+type lockedBuffer struct {
+	sync.Mutex
+	buffer []byte
+}
+
+func (l *lockedBuffer) Write(p []byte) (int, error) {
+	l.Lock()
+	defer l.Unlock()
+	l.buffer = append(l.buffer, p...)
+	return len(p), nil
+}
+
+func (l *lockedBuffer) String() string {
+	l.Lock()
+	defer l.Unlock()
+	return string(l.buffer)
+}
+
+func newTestWriter(t *testing.T, clock *fakeClock, cfg *fileConfig) *rotatingWriter {
+	cfg.path = filepath.Join(t.TempDir(), "logs", "app.log")
+	writer, err := newRotatingWriter(cfg, clock.Now)
+	require.NoError(t, err)
+	t.Cleanup(func() { writer.Close() })
+	return writer
+}
+
+func listDir(t *testing.T, dir string) (names []string) {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return
+}
+
+func readFile(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestRotateBySize(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)}
+	writer := newTestWriter(t, clock, &fileConfig{maxSize: 10})
+	_, err := writer.Write([]byte("0123456789"))
+	require.NoError(t, err)
+	clock.Add(time.Second)
+	_, err = writer.Write([]byte("next"))
+	require.NoError(t, err)
+	dir := filepath.Dir(writer.cfg.path)
+	assert.Equal(t, []string{"app-2021-01-02T03-04-06.000.log", "app.log"}, listDir(t, dir))
+	assert.Equal(t, "0123456789", readFile(t, filepath.Join(dir, "app-2021-01-02T03-04-06.000.log")))
+	assert.Equal(t, "next", readFile(t, writer.cfg.path))
+}
+
+func TestRotateByInterval(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2021, 1, 2, 3, 59, 0, 0, time.UTC)}
+	writer := newTestWriter(t, clock, &fileConfig{interval: time.Hour})
+	_, err := writer.Write([]byte("first"))
+	require.NoError(t, err)
+	clock.Add(time.Minute)
+	_, err = writer.Write([]byte("second"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app-2021-01-02T04-00-00.000.log", "app.log"}, listDir(t, filepath.Dir(writer.cfg.path)))
+	assert.Equal(t, "second", readFile(t, writer.cfg.path))
+}
+
+func TestRotateWithinSameMillisecond(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)}
+	writer := newTestWriter(t, clock, &fileConfig{maxBackups: 2})
+	for _, content := range []string{"one", "two", "three"} {
+		_, err := writer.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, writer.Rotate())
+	}
+	require.NoError(t, writer.Close())
+	dir := filepath.Dir(writer.cfg.path)
+	assert.Equal(t, []string{
+		"app-2021-01-02T03-04-05.000.1.log",
+		"app-2021-01-02T03-04-05.000.2.log",
+		"app.log",
+	}, listDir(t, dir), "oldest backup is removed")
+	assert.Equal(t, "three", readFile(t, filepath.Join(dir, "app-2021-01-02T03-04-05.000.2.log")))
+}
+
+func TestReopenFailure(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	writer := newTestWriter(t, clock, &fileConfig{})
+	var fallback lockedBuffer
+	writer.fallback = &fallback
+	dir := filepath.Dir(writer.cfg.path)
+	require.NoError(t, os.RemoveAll(dir))
+	require.NoError(t, os.WriteFile(dir, nil, 0644)) // log directory can't be created
+	assert.Error(t, writer.Reopen())
+	_, err := writer.Write([]byte("while broken"))
+	require.NoError(t, err)
+	assert.Equal(t, "while broken", fallback.String())
+
+	require.NoError(t, os.Remove(dir))
+	_, err = writer.Write([]byte("recovered"))
+	require.NoError(t, err)
+	assert.Equal(t, "recovered", readFile(t, writer.cfg.path))
+}
+
+func TestBackupsRemovedAndCompressed(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)}
+	writer := newTestWriter(t, clock, &fileConfig{maxBackups: 2, maxAge: time.Hour, compress: true})
+	dir := filepath.Dir(writer.cfg.path)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app-2021-01-01T00-00-00.000.log"), []byte("too old"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "unrelated.log"), []byte("keep"), 0644))
+	for _, content := range []string{"one", "two", "three"} {
+		_, err := writer.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, writer.Rotate())
+		clock.Add(time.Second)
+	}
+	require.NoError(t, writer.Close()) // waits for pending backups handling
+	assert.Equal(t, []string{
+		"app-2021-01-02T03-04-06.000.log.gz",
+		"app-2021-01-02T03-04-07.000.log.gz",
+		"app.log",
+		"unrelated.log",
+	}, listDir(t, dir))
+	gzFile, err := os.Open(filepath.Join(dir, "app-2021-01-02T03-04-07.000.log.gz"))
+	require.NoError(t, err)
+	defer gzFile.Close()
+	reader, err := gzip.NewReader(gzFile)
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "three", string(content))
+}
+
+func TestReopenOnSignal(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	writer := newTestWriter(t, clock, &fileConfig{signals: []os.Signal{syscall.SIGHUP}})
+	_, err := writer.Write([]byte("before"))
+	require.NoError(t, err)
+	moved := writer.cfg.path + ".1"
+	require.NoError(t, os.Rename(writer.cfg.path, moved)) // logrotate
+	writer.signals <- syscall.SIGHUP
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(writer.cfg.path)
+		return err == nil
+	}, time.Second, time.Millisecond)
+	_, err = writer.Write([]byte("after"))
+	require.NoError(t, err)
+	assert.Equal(t, "before", readFile(t, moved))
+	assert.Equal(t, "after", readFile(t, writer.cfg.path))
+}
+
+func TestBuildWithoutPath(t *testing.T) {
+	_, err := Builder().SetMaxSize(1).Build()
+	assert.Error(t, err)
+	writer, err := Builder().SetPath(filepath.Join(t.TempDir(), "app.log")).Build()
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	_, err = writer.Write([]byte("closed"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-masonry/mortar/interfaces/log"
+)
+
+const (
+	maxLevelChanges = 100
+	// ChangedByTTL marks level changes that were made automatically once TTL expired
+	ChangedByTTL = "ttl"
+	levelNotSet  = -1
+)
+
+// LevelChange records a runtime log level change
+type LevelChange struct {
+	From      log.Level `json:"from"`
+	To        log.Level `json:"to"`
+	ChangedBy string    `json:"changed_by"`
+	// ClaimedBy is who the requester claims to be, it's never verified and shouldn't be trusted
+	ClaimedBy string    `json:"claimed_by,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+	// RevertAt is set when this change has a TTL
+	RevertAt *time.Time `json:"revert_at,omitempty"`
+}
+
+// AtomicLevel is implemented by the mortar logger configuration, it allows to change the log level at runtime
+//
+//	if level, ok := logger.Configuration().(AtomicLevel); ok {
+//		level.SetLevel(log.DebugLevel, 10*time.Minute, "10.0.0.1:5555", "john")
+//	}
+type AtomicLevel interface {
+	log.LoggerConfiguration
+	// SetLevel changes log level, if ttl > 0 the level will revert to its previous value once ttl expires.
+	//
+	// Any previously scheduled revert is canceled.
+	//
+	// changedBy should identify the caller in a way it can't forge, such as its address, while claimedBy
+	// is whatever identity the caller reported about itself and can be empty.
+	SetLevel(level log.Level, ttl time.Duration, changedBy, claimedBy string) LevelChange
+	// Changes returns the latest level changes, oldest first
+	Changes() []LevelChange
+}
+
+type atomicLevel struct {
+	sync.Mutex
+	level   int32
+	named   map[string]log.Level
+	inner   log.Logger
+	changes []LevelChange
+	revert  *time.Timer
+}
+
+func newAtomicLevel(inner log.Logger, named map[string]log.Level) *atomicLevel {
+	lowered := make(map[string]log.Level, len(named))
+	for name, level := range named {
+		lowered[strings.ToLower(name)] = level
+	}
+	return &atomicLevel{
+		level: levelNotSet,
+		named: lowered,
+		inner: inner,
+	}
+}
+
+// enabled is called for every log entry, named logger level takes precedence over the current level.
+// If level was never set it's up to the inner logger to decide
+func (a *atomicLevel) enabled(name string, level log.Level) bool {
+	if namedLevel, ok := a.namedLevel(name); ok {
+		return namedLevel <= level
+	}
+	current := atomic.LoadInt32(&a.level)
+	return current == levelNotSet || log.Level(current) <= level
+}
+
+// written is the same as enabled, but also considers the inner logger level when the level was never set
+func (a *atomicLevel) written(name string, level log.Level) bool {
+	if atomic.LoadInt32(&a.level) == levelNotSet && a.inner.Configuration().Level() > level {
+		return false
+	}
+	return a.enabled(name, level)
+}
+
+// namedLevel finds the longest name prefix (on dot boundaries) that has a level
+func (a *atomicLevel) namedLevel(name string) (log.Level, bool) {
+	for prefix := name; len(a.named) > 0 && len(prefix) > 0; {
+		if level, ok := a.named[prefix]; ok {
+			return level, true
+		}
+		index := strings.LastIndexByte(prefix, '.')
+		if index < 0 {
+			break
+		}
+		prefix = prefix[:index]
+	}
+	return log.TraceLevel, false
+}
+
+func (a *atomicLevel) Level() log.Level {
+	if current := atomic.LoadInt32(&a.level); current != levelNotSet {
+		return log.Level(current)
+	}
+	return a.inner.Configuration().Level()
+}
+
+func (a *atomicLevel) Implementation() interface{} {
+	return a.inner.Configuration().Implementation()
+}
+
+func (a *atomicLevel) SetLevel(level log.Level, ttl time.Duration, changedBy, claimedBy string) LevelChange {
+	a.Lock()
+	defer a.Unlock()
+	if a.revert != nil {
+		a.revert.Stop()
+		a.revert = nil
+	}
+	change := a.set(level, changedBy, claimedBy)
+	if ttl > 0 {
+		revertAt := change.ChangedAt.Add(ttl)
+		change.RevertAt = &revertAt
+		a.changes[len(a.changes)-1] = change
+		previous := change.From
+		var timer *time.Timer
+		timer = time.AfterFunc(ttl, func() {
+			a.Lock()
+			defer a.Unlock()
+			if a.revert == timer { // make sure it wasn't replaced
+				a.set(previous, ChangedByTTL, "")
+				a.revert = nil
+			}
+		})
+		a.revert = timer
+	}
+	return change
+}
+
+func (a *atomicLevel) Changes() []LevelChange {
+	a.Lock()
+	defer a.Unlock()
+	return append([]LevelChange(nil), a.changes...)
+}
+
+// set must be called while holding the lock
+func (a *atomicLevel) set(level log.Level, changedBy, claimedBy string) LevelChange {
+	change := LevelChange{
+		From:      a.Level(),
+		To:        level,
+		ChangedBy: changedBy,
+		ClaimedBy: claimedBy,
+		ChangedAt: time.Now(),
+	}
+	atomic.StoreInt32(&a.level, int32(level))
+	a.changes = append(a.changes, change)
+	if len(a.changes) > maxLevelChanges {
+		a.changes = a.changes[len(a.changes)-maxLevelChanges:]
+	}
+	return change
+}
+
+var _ AtomicLevel = (*atomicLevel)(nil)
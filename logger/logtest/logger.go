@@ -0,0 +1,136 @@
+package logtest
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"time"
+
+	logInt "github.com/go-masonry/mortar/interfaces/log"
+)
+
+type recordingConfig struct {
+	level logInt.Level
+}
+
+type recordingBuilder struct {
+	recorder *Recorder
+	ll       *list.List
+}
+
+func (b *recordingBuilder) SetLevel(level logInt.Level) logInt.Builder {
+	b.ll.PushBack(func(cfg *recordingConfig) {
+		cfg.level = level
+	})
+	return b
+}
+
+// IncrementSkipFrames is ignored, caller is not recorded
+func (b *recordingBuilder) IncrementSkipFrames(int) logInt.Builder {
+	return b
+}
+
+func (b *recordingBuilder) Build() logInt.Logger {
+	cfg := &recordingConfig{
+		level: logInt.TraceLevel,
+	}
+	for e := b.ll.Front(); e != nil; e = e.Next() {
+		f := e.Value.(func(cfg *recordingConfig))
+		f(cfg)
+	}
+	return &recordingLogger{
+		cfg:      cfg,
+		recorder: b.recorder,
+	}
+}
+
+type recordingLogger struct {
+	cfg      *recordingConfig
+	recorder *Recorder
+	fields   map[string]interface{}
+	err      error
+}
+
+func (l *recordingLogger) Trace(ctx context.Context, format string, args ...interface{}) {
+	l.Custom(ctx, logInt.TraceLevel, 0, format, args...)
+}
+
+func (l *recordingLogger) Debug(ctx context.Context, format string, args ...interface{}) {
+	l.Custom(ctx, logInt.DebugLevel, 0, format, args...)
+}
+
+func (l *recordingLogger) Info(ctx context.Context, format string, args ...interface{}) {
+	l.Custom(ctx, logInt.InfoLevel, 0, format, args...)
+}
+
+func (l *recordingLogger) Warn(ctx context.Context, format string, args ...interface{}) {
+	l.Custom(ctx, logInt.WarnLevel, 0, format, args...)
+}
+
+func (l *recordingLogger) Error(ctx context.Context, format string, args ...interface{}) {
+	l.Custom(ctx, logInt.ErrorLevel, 0, format, args...)
+}
+
+func (l *recordingLogger) Custom(ctx context.Context, level logInt.Level, _ int, format string, args ...interface{}) {
+	if level < l.cfg.level {
+		return
+	}
+	message := format
+	if len(args) > 0 {
+		message = fmt.Sprintf(format, args...)
+	}
+	fields := make(map[string]interface{}, len(l.fields))
+	for name, value := range l.fields {
+		fields[name] = value
+	}
+	l.recorder.record(Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: message,
+		Fields:  fields,
+		Err:     l.err,
+		Ctx:     ctx,
+	})
+}
+
+func (l *recordingLogger) WithError(err error) logInt.Fields {
+	entry := l.clone()
+	entry.err = err
+	return entry
+}
+
+func (l *recordingLogger) WithField(name string, value interface{}) logInt.Fields {
+	entry := l.clone()
+	entry.fields[name] = value
+	return entry
+}
+
+func (l *recordingLogger) Configuration() logInt.LoggerConfiguration {
+	return l
+}
+
+func (l *recordingLogger) Level() logInt.Level {
+	return l.cfg.level
+}
+
+// Implementation returns the Recorder
+func (l *recordingLogger) Implementation() interface{} {
+	return l.recorder
+}
+
+// clone returns a copy of this logger, so fields of one entry will not leak into another
+func (l *recordingLogger) clone() *recordingLogger {
+	fields := make(map[string]interface{}, len(l.fields)+1)
+	for name, value := range l.fields {
+		fields[name] = value
+	}
+	return &recordingLogger{
+		cfg:      l.cfg,
+		recorder: l.recorder,
+		fields:   fields,
+		err:      l.err,
+	}
+}
+
+var _ logInt.Logger = (*recordingLogger)(nil)
+var _ logInt.LoggerConfiguration = (*recordingLogger)(nil)
@@ -0,0 +1,188 @@
+// Package logtest provides a log.Builder that records every log entry, so tests can assert on them
+//
+//	recorder := logtest.New().Echo(t)
+//	logger := logger.CreateMortarLogger(recorder.Builder(), extractors...)
+//	...
+//	recorder.AssertLogged(t, log.ErrorLevel, "failed to", map[string]interface{}{"user": "john"})
+//
+// Fields of ContextExtractors are recorded as well when the builder is wrapped with the mortar logger. To record them
+// separately in Entry.ContextFields, add them to the Recorder instead:
+//
+//	recorder := logtest.New().AddExtractors(extractors...)
+//	logger := logger.CreateMortarLogger(recorder.Builder())
+package logtest
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logInt "github.com/go-masonry/mortar/interfaces/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// Entry is a recorded log entry
+type Entry struct {
+	Time    time.Time
+	Level   logInt.Level
+	Message string
+	Fields  map[string]interface{}
+	// ContextFields are the fields returned by the ContextExtractors added to the Recorder
+	ContextFields map[string]interface{}
+	Err           error
+	Ctx           context.Context
+}
+
+func (e Entry) String() string {
+	output := fmt.Sprintf("[%s] %s", e.Level, e.Message)
+	if e.Err != nil {
+		output += fmt.Sprintf(" error=%v", e.Err)
+	}
+	if len(e.Fields) > 0 {
+		output += fmt.Sprintf(" %v", e.Fields)
+	}
+	if len(e.ContextFields) > 0 {
+		output += fmt.Sprintf(" context=%v", e.ContextFields)
+	}
+	return output
+}
+
+// Recorder records entries of every logger built by its Builder, it's safe for concurrent use
+type Recorder struct {
+	sync.Mutex
+	entries    []Entry
+	echo       testing.TB
+	extractors []logInt.ContextExtractor
+}
+
+// New creates a Recorder
+func New() *Recorder {
+	return new(Recorder)
+}
+
+// Echo writes every recorded entry with t.Log, useful when a test fails. It stops once the test completes,
+// since t.Log panics when called after that
+func (r *Recorder) Echo(t testing.TB) *Recorder {
+	r.Lock()
+	defer r.Unlock()
+	r.echo = t
+	t.Cleanup(func() {
+		r.Lock()
+		defer r.Unlock()
+		if r.echo == t {
+			r.echo = nil
+		}
+	})
+	return r
+}
+
+// AddExtractors adds ContextExtractors that are called for every recorded entry, their output is recorded in Entry.ContextFields.
+//
+// Don't pass them to the mortar logger as well, otherwise they are recorded in Entry.Fields too.
+func (r *Recorder) AddExtractors(extractors ...logInt.ContextExtractor) *Recorder {
+	r.Lock()
+	defer r.Unlock()
+	r.extractors = append(r.extractors, extractors...)
+	return r
+}
+
+// Builder creates a log.Builder of loggers that record to this Recorder, default level is log.TraceLevel
+func (r *Recorder) Builder() logInt.Builder {
+	return &recordingBuilder{
+		recorder: r,
+		ll:       list.New(),
+	}
+}
+
+// Entries returns all recorded entries
+func (r *Recorder) Entries() []Entry {
+	r.Lock()
+	defer r.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+// Reset removes all recorded entries
+func (r *Recorder) Reset() {
+	r.Lock()
+	defer r.Unlock()
+	r.entries = nil
+}
+
+// Find returns entries of the level that contain msgSubstring and have all the fields, nil fields match everything.
+// Fields are looked up in Entry.Fields and then in Entry.ContextFields.
+// Field values are compared with assert.ObjectsAreEqualValues, so int and int64 are the same.
+func (r *Recorder) Find(level logInt.Level, msgSubstring string, fields map[string]interface{}) (output []Entry) {
+	for _, entry := range r.Entries() {
+		if entry.Level == level && strings.Contains(entry.Message, msgSubstring) && hasFields(entry, fields) {
+			output = append(output, entry)
+		}
+	}
+	return
+}
+
+// AssertLogged asserts that at least one entry matches, see Find
+func (r *Recorder) AssertLogged(t testing.TB, level logInt.Level, msgSubstring string, fields map[string]interface{}) bool {
+	t.Helper()
+	if len(r.Find(level, msgSubstring, fields)) > 0 {
+		return true
+	}
+	return assert.Fail(t, fmt.Sprintf("no [%s] entry with message containing %q and fields %v", level, msgSubstring, fields), r.dump())
+}
+
+// AssertNotLogged asserts that no entry matches, see Find
+func (r *Recorder) AssertNotLogged(t testing.TB, level logInt.Level, msgSubstring string, fields map[string]interface{}) bool {
+	t.Helper()
+	found := r.Find(level, msgSubstring, fields)
+	if len(found) == 0 {
+		return true
+	}
+	return assert.Fail(t, fmt.Sprintf("unexpected [%s] entry with message containing %q and fields %v", level, msgSubstring, fields), fmt.Sprint(found))
+}
+
+func (r *Recorder) record(entry Entry) {
+	r.Lock()
+	defer r.Unlock()
+	if len(r.extractors) > 0 {
+		ctx := entry.Ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		entry.ContextFields = make(map[string]interface{})
+		for _, extractor := range r.extractors {
+			for name, value := range extractor(ctx) {
+				entry.ContextFields[name] = value
+			}
+		}
+	}
+	r.entries = append(r.entries, entry)
+	if r.echo != nil {
+		r.echo.Log(entry.String())
+	}
+}
+
+func (r *Recorder) dump() string {
+	entries := r.Entries()
+	lines := make([]string, 0, len(entries)+1)
+	lines = append(lines, fmt.Sprintf("recorded %d entries:", len(entries)))
+	for _, entry := range entries {
+		lines = append(lines, entry.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func hasFields(entry Entry, fields map[string]interface{}) bool {
+	for name, expected := range fields {
+		value, ok := entry.Fields[name]
+		if !ok {
+			value, ok = entry.ContextFields[name]
+		}
+		if !ok || !assert.ObjectsAreEqualValues(expected, value) {
+			return false
+		}
+	}
+	return true
+}
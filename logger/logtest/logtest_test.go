@@ -0,0 +1,87 @@
+package logtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	logInt "github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKey struct{}
+
+// failureTB records failures instead of failing the test
+type failureTB struct {
+	testing.TB
+	failures []string
+}
+
+func (f *failureTB) Helper() {}
+
+func (f *failureTB) Errorf(format string, args ...interface{}) {
+	f.failures = append(f.failures, fmt.Sprintf(format, args...))
+}
+
+func TestRecorderWithMortarLogger(t *testing.T) {
+	recorder := New().Echo(t)
+	log := logger.CreateMortarLogger(recorder.Builder().SetLevel(logInt.DebugLevel), func(ctx context.Context) map[string]interface{} {
+		return map[string]interface{}{"request": ctx.Value(ctxKey{})}
+	})
+	ctx := context.WithValue(context.Background(), ctxKey{}, "abc")
+	log.Trace(ctx, "filtered")
+	log.WithField("user", "john").WithField("attempt", 2).WithError(errors.New("timeout")).Error(ctx, "failed to %s", "login")
+	log.Info(ctx, "second")
+
+	entries := recorder.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "failed to login", entries[0].Message)
+	assert.EqualError(t, entries[0].Err, "timeout")
+	assert.Equal(t, ctx, entries[0].Ctx)
+	assert.Equal(t, map[string]interface{}{"request": "abc", "user": "john", "attempt": 2}, entries[0].Fields)
+	recorder.AssertLogged(t, logInt.ErrorLevel, "failed", map[string]interface{}{"request": "abc", "attempt": int64(2)})
+	recorder.AssertNotLogged(t, logInt.TraceLevel, "filtered", nil)
+	assert.Len(t, recorder.Find(logInt.InfoLevel, "", nil), 1)
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Entries())
+}
+
+func TestRecorderContextFields(t *testing.T) {
+	recorder := New().AddExtractors(func(ctx context.Context) map[string]interface{} {
+		return map[string]interface{}{"request": ctx.Value(ctxKey{})}
+	})
+	log := logger.CreateMortarLogger(recorder.Builder())
+	log.WithField("user", "john").Info(context.WithValue(context.Background(), ctxKey{}, "abc"), "info")
+
+	entries := recorder.Entries()
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]interface{}{"user": "john"}, entries[0].Fields)
+	assert.Equal(t, map[string]interface{}{"request": "abc"}, entries[0].ContextFields)
+	recorder.AssertLogged(t, logInt.InfoLevel, "info", map[string]interface{}{"request": "abc", "user": "john"})
+}
+
+func TestRecorderEchoStopsWithTest(t *testing.T) {
+	recorder := New()
+	t.Run("echo", func(t *testing.T) {
+		recorder.Echo(t)
+	})
+	assert.NotPanics(t, func() {
+		recorder.Builder().Build().Info(nil, "after the test completed")
+	})
+	assert.Len(t, recorder.Entries(), 1)
+}
+
+func TestRecorderAssertionFailures(t *testing.T) {
+	recorder := New()
+	recorder.Builder().Build().WithField("user", "john").Warn(nil, "warning")
+	tb := &failureTB{TB: t}
+	assert.False(t, recorder.AssertLogged(tb, logInt.WarnLevel, "warning", map[string]interface{}{"user": "jane"}))
+	assert.False(t, recorder.AssertLogged(tb, logInt.ErrorLevel, "warning", nil))
+	assert.False(t, recorder.AssertNotLogged(tb, logInt.WarnLevel, "warn", nil))
+	require.Len(t, tb.failures, 3)
+	assert.Contains(t, tb.failures[0], "[warn] warning map[user:john]")
+}
@@ -16,10 +16,12 @@ type defaultConfig struct {
 	depth         int
 	excludeTime   bool
 	includeCaller bool
+	json          bool
 }
 
 type defaultBuilder struct {
-	ll *list.List
+	ll        *list.List
+	hasWriter bool
 }
 
 // NativeLogBuilder is a helper interface to configure native log.Logger instance.
@@ -27,10 +29,17 @@ type NativeLogBuilder interface {
 	logInt.Builder
 	// SetWriter set where output should be printed
 	SetWriter(writer io.Writer) NativeLogBuilder
+	// HasWriter tells if SetWriter was called, otherwise output is printed to stderr
+	HasWriter() bool
 	// ExcludeTime configures standard Logger to exclude any time field
 	ExcludeTime() NativeLogBuilder
 	// IncludeCaller adds caller:line to the output
 	IncludeCaller() NativeLogBuilder
+	// JSON configures logger to output every entry as a JSON object, unlike the default format
+	// it supports fields and errors.
+	//
+	//	{"time":"2006-01-02T15:04:05.999999999Z07:00","level":"info","caller":"/path/file.go:12","message":"text","error":"an error","field":"value"}
+	JSON() NativeLogBuilder
 }
 
 // Builder creates a fresh default Logger builder, this will eventually build a std logger wrapper without structured logging
@@ -41,12 +50,17 @@ func Builder() NativeLogBuilder {
 }
 
 func (d *defaultBuilder) SetWriter(writer io.Writer) NativeLogBuilder {
+	d.hasWriter = true
 	d.ll.PushBack(func(cfg *defaultConfig) {
 		cfg.writer = writer
 	})
 	return d
 }
 
+func (d *defaultBuilder) HasWriter() bool {
+	return d.hasWriter
+}
+
 func (d *defaultBuilder) ExcludeTime() NativeLogBuilder {
 	d.ll.PushBack(func(cfg *defaultConfig) {
 		cfg.excludeTime = true
@@ -61,6 +75,13 @@ func (d *defaultBuilder) IncludeCaller() NativeLogBuilder {
 	return d
 }
 
+func (d *defaultBuilder) JSON() NativeLogBuilder {
+	d.ll.PushBack(func(cfg *defaultConfig) {
+		cfg.json = true
+	})
+	return d
+}
+
 func (d *defaultBuilder) IncrementSkipFrames(inc int) logInt.Builder {
 	d.ll.PushBack(func(cfg *defaultConfig) {
 		cfg.depth += inc
@@ -82,6 +103,7 @@ func (d *defaultBuilder) Build() logInt.Logger {
 		depth:         defaultSkipDepth, // 2 is used within the log package
 		excludeTime:   false,
 		includeCaller: false,
+		json:          false,
 	}
 	for e := d.ll.Front(); e != nil; e = e.Next() {
 		f := e.Value.(func(config *defaultConfig))
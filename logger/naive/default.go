@@ -15,6 +15,8 @@ const (
 type defaultLogger struct {
 	cfg    *defaultConfig
 	logger *log.Logger
+	fields map[string]interface{}
+	err    error
 }
 
 func (d *defaultLogger) Level() logInt.Level {
@@ -47,18 +49,32 @@ func (d *defaultLogger) Error(ctx context.Context, format string, args ...interf
 
 func (d *defaultLogger) Custom(ctx context.Context, level logInt.Level, skipAdditionalFrames int, format string, args ...interface{}) {
 	if d.cfg.level <= level {
-		d.log(skipAdditionalFrames, format, args...)
+		if d.cfg.json {
+			d.logJSON(level, skipAdditionalFrames, format, args...)
+		} else {
+			d.log(skipAdditionalFrames, format, args...)
+		}
 	}
 }
 
-// WithError not supported
+// WithError supported only in JSON format
 func (d *defaultLogger) WithError(err error) logInt.Fields {
-	return d
+	if !d.cfg.json {
+		return d
+	}
+	entry := d.clone()
+	entry.err = err
+	return entry
 }
 
-// WithField not supported
+// WithField supported only in JSON format
 func (d *defaultLogger) WithField(name string, value interface{}) logInt.Fields {
-	return d
+	if !d.cfg.json {
+		return d
+	}
+	entry := d.clone()
+	entry.fields[name] = value
+	return entry
 }
 
 func (d *defaultLogger) Configuration() logInt.LoggerConfiguration {
@@ -74,6 +90,20 @@ func (d *defaultLogger) log(skipAdditionalFrames int, format string, args ...int
 	}
 }
 
+// clone returns a copy of this logger, so fields of one entry will not leak into another
+func (d *defaultLogger) clone() *defaultLogger {
+	fields := make(map[string]interface{}, len(d.fields)+1)
+	for k, v := range d.fields {
+		fields[k] = v
+	}
+	return &defaultLogger{
+		cfg:    d.cfg,
+		logger: d.logger,
+		fields: fields,
+		err:    d.err,
+	}
+}
+
 func newDefaultLogger(cfg *defaultConfig) logInt.Logger {
 	flags := log.LstdFlags
 	if cfg.excludeTime {
@@ -82,6 +112,9 @@ func newDefaultLogger(cfg *defaultConfig) logInt.Logger {
 	if cfg.includeCaller {
 		flags |= log.Llongfile
 	}
+	if cfg.json {
+		flags = 0 // time and caller are part of the JSON object
+	}
 	logger := log.New(cfg.writer, "", flags)
 	return &defaultLogger{
 		logger: logger,
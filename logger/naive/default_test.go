@@ -2,6 +2,7 @@ package naive
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -9,6 +10,7 @@ import (
 
 	logInt "github.com/go-masonry/mortar/interfaces/log"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDefaultLogger(t *testing.T) {
@@ -64,3 +66,36 @@ func TestNotSupported(t *testing.T) {
 	assert.Contains(t, buf.String(), "warning line")
 	assert.Contains(t, buf.String(), `naive/default_test.go`)
 }
+
+func TestJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := Builder().SetWriter(&buf).JSON().IncludeCaller().Build()
+	logger.
+		WithError(fmt.Errorf("an error")).
+		WithField("one", 1).
+		WithField("body", []byte(`{"name": "value"}`)).
+		WithField("message", "reserved").
+		Info(nil, "json %s", "line")
+	var output map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &output))
+	assert.Equal(t, "info", output["level"])
+	assert.Equal(t, "json line", output["message"])
+	assert.Equal(t, "an error", output["error"])
+	assert.Equal(t, float64(1), output["one"])
+	assert.Equal(t, map[string]interface{}{"name": "value"}, output["body"])
+	assert.Equal(t, "reserved", output["fields.message"])
+	assert.Contains(t, output["caller"], "naive/default_test.go")
+	assert.Contains(t, output, "time")
+}
+
+func TestJSONFieldsNotShared(t *testing.T) {
+	var buf bytes.Buffer
+	logger := Builder().SetWriter(&buf).JSON().ExcludeTime().Build()
+	logger.WithField("first", "value").Info(nil, "first line")
+	logger.WithField("second", func() {}).Info(nil, "second line")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, `{"level":"info","message":"first line","first":"value"}`, lines[0])
+	assert.NotContains(t, lines[1], "first")
+	assert.Contains(t, lines[1], `"second":"0x`)
+}
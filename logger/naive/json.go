@@ -0,0 +1,97 @@
+package naive
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"time"
+
+	logInt "github.com/go-masonry/mortar/interfaces/log"
+)
+
+const (
+	timeKey    = "time"
+	levelKey   = "level"
+	callerKey  = "caller"
+	messageKey = "message"
+	errorKey   = "error"
+	// fields with reserved names will be prefixed
+	reservedPrefix = "fields."
+)
+
+var reservedKeys = map[string]bool{
+	timeKey:    true,
+	levelKey:   true,
+	callerKey:  true,
+	messageKey: true,
+	errorKey:   true,
+}
+
+// logJSON must be called from the same stack depth as log, since it calculates caller the same way
+func (d *defaultLogger) logJSON(level logInt.Level, skipAdditionalFrames int, format string, args ...interface{}) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	if !d.cfg.excludeTime {
+		writeJSONField(&buf, timeKey, time.Now().Format(time.RFC3339Nano))
+	}
+	writeJSONField(&buf, levelKey, level.String())
+	if d.cfg.includeCaller {
+		// log.Logger.Output is one frame deeper than this function
+		if _, file, line, ok := runtime.Caller(d.cfg.depth + skipAdditionalFrames - 1); ok {
+			writeJSONField(&buf, callerKey, fmt.Sprintf("%s:%d", file, line))
+		}
+	}
+	message := format
+	if len(args) > 0 {
+		message = fmt.Sprintf(format, args...)
+	}
+	writeJSONField(&buf, messageKey, message)
+	if d.err != nil {
+		writeJSONField(&buf, errorKey, d.err.Error())
+	}
+	names := make([]string, 0, len(d.fields))
+	for name := range d.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		key := name
+		if reservedKeys[name] {
+			key = reservedPrefix + name
+		}
+		writeJSONField(&buf, key, d.fields[name])
+	}
+	buf.WriteByte('}')
+	d.logger.Output(0, buf.String()) // no flags are set, depth is not relevant
+}
+
+func writeJSONField(buf *bytes.Buffer, key string, value interface{}) {
+	if buf.Len() > 1 {
+		buf.WriteByte(',')
+	}
+	keyBytes, _ := json.Marshal(key)
+	buf.Write(keyBytes)
+	buf.WriteByte(':')
+	buf.Write(jsonValue(value))
+}
+
+// jsonValue marshals value into a JSON value, if value can't be marshaled its string representation is used
+func jsonValue(value interface{}) []byte {
+	switch v := value.(type) {
+	case []byte: // usually a marshaled message body
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, v); err == nil {
+			return compact.Bytes()
+		}
+		value = string(v)
+	case error:
+		value = v.Error()
+	}
+	output, err := json.Marshal(value)
+	if err != nil {
+		output, _ = json.Marshal(fmt.Sprintf("%v", value))
+	}
+	return output
+}
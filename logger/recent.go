@@ -0,0 +1,199 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-masonry/mortar/interfaces/log"
+)
+
+// RecentLogsPolicy defines how many of the last log entries are kept in memory by the mortar logger wrapper
+type RecentLogsPolicy struct {
+	// Size is the number of entries kept per level
+	Size int
+	// LevelSizes overrides Size for specific levels, 0 disables recording of that level
+	LevelSizes map[log.Level]int
+}
+
+// RecentEntry is a log entry kept in memory.
+//
+// Field values are snapshots taken when the entry was recorded, errors and fmt.Stringer are kept as strings,
+// []byte as a string or raw JSON and other complex values as their JSON representation (json.RawMessage).
+type RecentEntry struct {
+	Time    time.Time              `json:"time"`
+	Level   log.Level              `json:"level"`
+	Message string                 `json:"message"`
+	Error   string                 `json:"error,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+// RecentFilter selects recent entries, zero values match everything
+type RecentFilter struct {
+	// Level is the minimal level
+	Level log.Level
+	// From and To define a time range, both inclusive
+	From, To time.Time
+	// Fields must be equal to their string representation
+	Fields map[string]string
+	// Contains is a substring of either the message or the error
+	Contains string
+	// Limit returns only the newest entries
+	Limit int
+}
+
+// RecentLogs is exposed by mortar logger when RecentLogsPolicy is set
+type RecentLogs interface {
+	// Query returns entries that match the filter ordered by time, oldest first
+	Query(filter RecentFilter) []RecentEntry
+}
+
+// Recorded is implemented by mortar logger, RecentLogs() returns nil if recording is disabled
+type Recorded interface {
+	RecentLogs() RecentLogs
+}
+
+type ring struct {
+	entries []RecentEntry
+	next    int
+	full    bool
+}
+
+func (r *ring) add(entry RecentEntry) {
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	r.full = r.full || r.next == 0
+}
+
+func (r *ring) all() []RecentEntry {
+	if !r.full {
+		return r.entries[:r.next]
+	}
+	return append(r.entries[r.next:len(r.entries):len(r.entries)], r.entries[:r.next]...)
+}
+
+type recentLogs struct {
+	sync.Mutex
+	rings map[log.Level]*ring
+	now   func() time.Time
+}
+
+func newRecentLogs(policy RecentLogsPolicy) *recentLogs {
+	rings := make(map[log.Level]*ring)
+	for _, level := range []log.Level{log.TraceLevel, log.DebugLevel, log.InfoLevel, log.WarnLevel, log.ErrorLevel} {
+		size := policy.Size
+		if levelSize, ok := policy.LevelSizes[level]; ok {
+			size = levelSize
+		}
+		if size > 0 {
+			rings[level] = &ring{entries: make([]RecentEntry, size)}
+		}
+	}
+	return &recentLogs{
+		rings: rings,
+		now:   time.Now,
+	}
+}
+
+func (r *recentLogs) enabled(level log.Level) bool {
+	return r.rings[level] != nil // read only after creation
+}
+
+func (r *recentLogs) record(level log.Level, err error, fields map[string]interface{}, format string, args ...interface{}) {
+	entry := RecentEntry{
+		Time:    r.now(),
+		Level:   level,
+		Message: format,
+	}
+	if len(args) > 0 {
+		entry.Message = fmt.Sprintf(format, args...)
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if len(fields) > 0 {
+		// entries are read long after they were logged, values can't be shared with the caller
+		entry.Fields = make(map[string]interface{}, len(fields))
+		for k, v := range fields {
+			entry.Fields[k] = snapshot(v)
+		}
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.rings[level].add(entry)
+}
+
+// snapshot returns an immutable copy of value that can be encoded as JSON
+func snapshot(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case error: // errors are marshaled as empty objects
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	case []byte: // marshaled as base64 otherwise
+		if json.Valid(v) {
+			return json.RawMessage(append([]byte(nil), v...))
+		}
+		return string(v)
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Ptr, reflect.Interface:
+		if data, err := json.Marshal(value); err == nil {
+			return json.RawMessage(data)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+func (r *recentLogs) Query(filter RecentFilter) []RecentEntry {
+	output := make([]RecentEntry, 0)
+	r.Lock()
+	for level, levelRing := range r.rings {
+		if level < filter.Level {
+			continue
+		}
+		for _, entry := range levelRing.all() {
+			if filter.match(entry) {
+				output = append(output, entry)
+			}
+		}
+	}
+	r.Unlock()
+	sort.SliceStable(output, func(i, j int) bool {
+		return output[i].Time.Before(output[j].Time)
+	})
+	if filter.Limit > 0 && len(output) > filter.Limit {
+		output = output[len(output)-filter.Limit:]
+	}
+	return output
+}
+
+func (f RecentFilter) match(entry RecentEntry) bool {
+	if !f.From.IsZero() && entry.Time.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && entry.Time.After(f.To) {
+		return false
+	}
+	if len(f.Contains) > 0 && !strings.Contains(entry.Message, f.Contains) && !strings.Contains(entry.Error, f.Contains) {
+		return false
+	}
+	for name, expected := range f.Fields {
+		value, ok := entry.Fields[name]
+		if raw, isRaw := value.(json.RawMessage); isRaw {
+			value = string(raw)
+		}
+		if !ok || fmt.Sprint(value) != expected {
+			return false
+		}
+	}
+	return true
+}
+
+var _ RecentLogs = (*recentLogs)(nil)
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	logInt "github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/logger/naive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentLogsRing(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	recent := newRecentLogs(RecentLogsPolicy{Size: 2, LevelSizes: map[logInt.Level]int{logInt.ErrorLevel: 3, logInt.TraceLevel: 0}})
+	recent.now = clock.Now
+	assert.False(t, recent.enabled(logInt.TraceLevel))
+	for i, level := range []logInt.Level{logInt.InfoLevel, logInt.ErrorLevel, logInt.InfoLevel, logInt.InfoLevel, logInt.ErrorLevel} {
+		clock.now = clock.now.Add(time.Second)
+		recent.record(level, nil, nil, "entry %d", i)
+	}
+	messages := func(entries []RecentEntry) (output []string) {
+		for _, entry := range entries {
+			output = append(output, entry.Message)
+		}
+		return
+	}
+	assert.Equal(t, []string{"entry 1", "entry 2", "entry 3", "entry 4"}, messages(recent.Query(RecentFilter{})))
+	assert.Equal(t, []string{"entry 1", "entry 4"}, messages(recent.Query(RecentFilter{Level: logInt.WarnLevel})))
+	assert.Equal(t, []string{"entry 3", "entry 4"}, messages(recent.Query(RecentFilter{Limit: 2})))
+	assert.Equal(t, []string{"entry 2", "entry 3"}, messages(recent.Query(RecentFilter{From: clock.now.Add(-2 * time.Second), To: clock.now.Add(-time.Second)})))
+}
+
+func TestRecentLogsLogger(t *testing.T) {
+	var output lockedBuffer
+	logger := Builder().
+		SetRecentLogs(RecentLogsPolicy{Size: 10}).
+		Build(naive.Builder().SetWriter(&output).JSON().SetLevel(logInt.InfoLevel))
+	logInt.Named(logger, "db").WithField("table", "users").WithError(errors.New("timeout")).Error(nil, "query failed")
+	logger.WithField("table", "orders").Info(nil, "query %s", "succeeded")
+	logger.Debug(nil, "not recorded")
+	recorded, ok := logger.(Recorded)
+	require.True(t, ok)
+	require.NotNil(t, recorded.RecentLogs())
+	assert.Len(t, recorded.RecentLogs().Query(RecentFilter{}), 2)
+	entries := recorded.RecentLogs().Query(RecentFilter{Fields: map[string]string{NameField: "db"}, Contains: "timeout"})
+	require.Len(t, entries, 1)
+	assert.Equal(t, "query failed", entries[0].Message)
+	assert.Equal(t, "timeout", entries[0].Error)
+	assert.Equal(t, map[string]interface{}{NameField: "db", "table": "users"}, entries[0].Fields)
+	assert.Equal(t, "query succeeded", recorded.RecentLogs().Query(RecentFilter{Fields: map[string]string{"table": "orders"}})[0].Message)
+	assert.Nil(t, Builder().Build(naive.Builder()).(Recorded).RecentLogs())
+}
+
+func TestRecentLogsFieldsSnapshot(t *testing.T) {
+	logger := Builder().SetRecentLogs(RecentLogsPolicy{Size: 10}).Build(naive.Builder().SetWriter(io.Discard).SetLevel(logInt.InfoLevel))
+	recorded := logger.(Recorded).RecentLogs()
+	request := map[string]interface{}{"attempt": 0}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			logger.WithField("request", request).WithField("body", []byte("plain text")).Info(nil, "sent")
+			request["attempt"] = i // callers are free to reuse their values
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_, err := json.Marshal(recorded.Query(RecentFilter{})) // same as the /self/logs handler
+		require.NoError(t, err)
+	}
+	<-done
+	entries := recorded.Query(RecentFilter{Limit: 1})
+	require.Len(t, entries, 1)
+	encoded, err := json.Marshal(entries[0].Fields)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"request":{"attempt":98},"body":"plain text"}`, string(encoded))
+}
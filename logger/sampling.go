@@ -0,0 +1,187 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-masonry/mortar/interfaces/log"
+)
+
+const (
+	defaultSamplingTick = time.Second
+	// DroppedField holds dropped entries count per level in the sampling summary entry
+	DroppedField = "dropped"
+)
+
+// SamplingPolicy defines which log entries are dropped by the mortar logger wrapper.
+//
+// Sampling is done per message template (format) and level, Error entries are exempt unless IncludeErrors is set.
+type SamplingPolicy struct {
+	// First entries of every message template are logged during each Tick
+	First int
+	// Thereafter every Mth entry of a message template is logged during the rest of the Tick, 0 drops all of them
+	Thereafter int
+	// Tick is the sampling period, default is a second
+	Tick time.Duration
+	// RateLimits are token bucket limits, entries per second per level. Burst equals the rate
+	RateLimits map[log.Level]int
+	// IncludeErrors applies this policy on Error entries as well
+	IncludeErrors bool
+	// SummaryInterval is the interval of summary entries that report how many entries were dropped, 0 disables them
+	SummaryInterval time.Duration
+}
+
+// Sampling is exposed by mortar logger when SamplingPolicy is set
+//
+//	if sampled, ok := logger.(Sampled); ok && sampled.Sampling() != nil {
+//		sampled.Sampling().OnDrop(func(level log.Level) {...})
+//	}
+type Sampling interface {
+	// Dropped returns the total number of dropped entries
+	Dropped() uint64
+	// OnDrop sets a callback that will be called for every dropped entry, it must return fast
+	OnDrop(callback func(level log.Level))
+}
+
+// Sampled is implemented by mortar logger, Sampling() returns nil if sampling is disabled
+type Sampled interface {
+	Sampling() Sampling
+}
+
+type samplingKey struct {
+	level    log.Level
+	template string
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type sampler struct {
+	sync.Mutex
+	policy    SamplingPolicy
+	logger    log.Logger
+	now       func() time.Time
+	tickStart time.Time
+	counts    map[samplingKey]int
+	buckets   map[log.Level]*tokenBucket
+	dropped   map[log.Level]uint64 // since last summary
+	scheduled bool
+	total     uint64
+	onDrop    atomic.Value
+}
+
+func newSampler(policy SamplingPolicy, logger log.Logger) *sampler {
+	if policy.Tick <= 0 {
+		policy.Tick = defaultSamplingTick
+	}
+	return &sampler{
+		policy:  policy,
+		logger:  logger,
+		now:     time.Now,
+		counts:  make(map[samplingKey]int),
+		buckets: make(map[log.Level]*tokenBucket),
+		dropped: make(map[log.Level]uint64),
+	}
+}
+
+func (s *sampler) Dropped() uint64 {
+	return atomic.LoadUint64(&s.total)
+}
+
+func (s *sampler) OnDrop(callback func(level log.Level)) {
+	s.onDrop.Store(callback)
+}
+
+// allow is called for every enabled log entry
+func (s *sampler) allow(level log.Level, template string) bool {
+	if level >= log.ErrorLevel && !s.policy.IncludeErrors {
+		return true
+	}
+	s.Lock()
+	allowed := s.sample(level, template) && s.limit(level)
+	if !allowed {
+		s.drop(level)
+	}
+	s.Unlock()
+	if !allowed {
+		atomic.AddUint64(&s.total, 1)
+		if callback, ok := s.onDrop.Load().(func(log.Level)); ok && callback != nil {
+			callback(level)
+		}
+	}
+	return allowed
+}
+
+// sample must be called while holding the lock
+func (s *sampler) sample(level log.Level, template string) bool {
+	if s.policy.First <= 0 {
+		return true
+	}
+	now := s.now()
+	if now.Sub(s.tickStart) >= s.policy.Tick {
+		s.tickStart = now
+		s.counts = make(map[samplingKey]int, len(s.counts))
+	}
+	key := samplingKey{level: level, template: template}
+	s.counts[key]++
+	count := s.counts[key]
+	if count <= s.policy.First {
+		return true
+	}
+	return s.policy.Thereafter > 0 && (count-s.policy.First)%s.policy.Thereafter == 0
+}
+
+// limit must be called while holding the lock
+func (s *sampler) limit(level log.Level) bool {
+	rate, ok := s.policy.RateLimits[level]
+	if !ok {
+		return true
+	}
+	now := s.now()
+	bucket, ok := s.buckets[level]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(rate), last: now}
+		s.buckets[level] = bucket
+	}
+	bucket.tokens += now.Sub(bucket.last).Seconds() * float64(rate)
+	if bucket.tokens > float64(rate) {
+		bucket.tokens = float64(rate)
+	}
+	bucket.last = now
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// drop must be called while holding the lock, summary is scheduled only when something was dropped
+func (s *sampler) drop(level log.Level) {
+	s.dropped[level]++
+	if s.policy.SummaryInterval > 0 && !s.scheduled {
+		s.scheduled = true
+		time.AfterFunc(s.policy.SummaryInterval, s.summary)
+	}
+}
+
+func (s *sampler) summary() {
+	s.Lock()
+	dropped := s.dropped
+	s.dropped = make(map[log.Level]uint64)
+	s.scheduled = false
+	s.Unlock()
+	var total uint64
+	perLevel := make(map[string]uint64, len(dropped))
+	for level, count := range dropped {
+		perLevel[level.String()] = count
+		total += count
+	}
+	s.logger.WithField(DroppedField, perLevel).
+		Warn(context.Background(), "log sampling dropped %d entries during the last %s", total, s.policy.SummaryInterval)
+}
+
+var _ Sampling = (*sampler)(nil)
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logInt "github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/logger/naive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+// lockedBuffer is written by the summary timer goroutine
+type lockedBuffer struct {
+	sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (l *lockedBuffer) Write(p []byte) (int, error) {
+	l.Lock()
+	defer l.Unlock()
+	return l.buffer.Write(p)
+}
+
+func (l *lockedBuffer) String() string {
+	l.Lock()
+	defer l.Unlock()
+	return l.buffer.String()
+}
+
+func TestSamplingFirstThereafter(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	s := newSampler(SamplingPolicy{First: 2, Thereafter: 3}, nil)
+	s.now = clock.Now
+	var allowed []bool
+	for i := 0; i < 8; i++ {
+		allowed = append(allowed, s.allow(logInt.InfoLevel, "template"))
+	}
+	assert.Equal(t, []bool{true, true, false, false, true, false, false, true}, allowed)
+	assert.True(t, s.allow(logInt.InfoLevel, "another template"))
+	assert.True(t, s.allow(logInt.DebugLevel, "template"), "levels are sampled separately")
+	assert.True(t, s.allow(logInt.ErrorLevel, "template"), "errors are exempt")
+	assert.EqualValues(t, 4, s.Dropped())
+
+	clock.now = clock.now.Add(time.Second)
+	assert.True(t, s.allow(logInt.InfoLevel, "template"), "new tick")
+}
+
+func TestSamplingRateLimit(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	s := newSampler(SamplingPolicy{RateLimits: map[logInt.Level]int{logInt.ErrorLevel: 2}, IncludeErrors: true}, nil)
+	s.now = clock.Now
+	var droppedLevels []logInt.Level
+	s.OnDrop(func(level logInt.Level) {
+		droppedLevels = append(droppedLevels, level)
+	})
+	assert.True(t, s.allow(logInt.ErrorLevel, "one"))
+	assert.True(t, s.allow(logInt.ErrorLevel, "two"))
+	assert.False(t, s.allow(logInt.ErrorLevel, "three"))
+	assert.True(t, s.allow(logInt.InfoLevel, "no limit"))
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	assert.True(t, s.allow(logInt.ErrorLevel, "refilled"))
+	assert.False(t, s.allow(logInt.ErrorLevel, "empty again"))
+	assert.Equal(t, []logInt.Level{logInt.ErrorLevel, logInt.ErrorLevel}, droppedLevels)
+}
+
+func TestSamplingSummary(t *testing.T) {
+	var output lockedBuffer
+	logger := Builder().
+		SetSampling(SamplingPolicy{First: 1, SummaryInterval: 10 * time.Millisecond}).
+		Build(naive.Builder().SetWriter(&output).JSON())
+	for i := 0; i < 3; i++ {
+		logger.Info(nil, "hot path %d", i)
+	}
+	assert.Contains(t, output.String(), "hot path 0")
+	assert.NotContains(t, output.String(), "hot path 1")
+	sampling := logger.(Sampled).Sampling()
+	require.NotNil(t, sampling)
+	assert.EqualValues(t, 2, sampling.Dropped())
+	assert.Eventually(t, func() bool {
+		return strings.Contains(output.String(), `"dropped":{"info":2}`)
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestSamplingDisabled(t *testing.T) {
+	logger := CreateMortarLogger(naive.Builder())
+	assert.Nil(t, logger.(Sampled).Sampling())
+}
@@ -0,0 +1,78 @@
+package slogbridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	logInt "github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/logger"
+	"github.com/go-masonry/mortar/logger/naive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKey struct{}
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	var output map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &output))
+	buf.Reset()
+	return output
+}
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	mortarLogger := logger.CreateMortarLogger(
+		naive.Builder().SetWriter(&buf).JSON().ExcludeTime().SetLevel(logInt.InfoLevel),
+		func(ctx context.Context) map[string]interface{} {
+			return map[string]interface{}{"request": ctx.Value(ctxKey{})}
+		},
+	)
+	slogger := slog.New(NewHandler(mortarLogger)).With("service", "users").WithGroup("http")
+	ctx := context.WithValue(context.Background(), ctxKey{}, "abc")
+	slogger.DebugContext(ctx, "filtered")
+	assert.Empty(t, buf.String())
+	slogger.With("method", "GET").WarnContext(ctx, "100% done", "status", 200, slog.Group("user", "id", 7), "err", errors.New("failure"))
+	assert.Equal(t, map[string]interface{}{
+		"level":   "warn",
+		"message": "100% done",
+		"request": "abc",
+		"service": "users",
+		"http": map[string]interface{}{
+			"method": "GET",
+			"status": float64(200),
+			"user":   map[string]interface{}{"id": float64(7)},
+			"err":    "failure",
+		},
+	}, decode(t, &buf))
+	slogger.InfoContext(ctx, "empty group")
+	assert.NotContains(t, decode(t, &buf), "http")
+}
+
+func TestBuilder(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true, Level: ToSlogLevel(logInt.TraceLevel)})
+	slogLogger := Builder(handler).SetLevel(logInt.DebugLevel).Build()
+	slogLogger.Trace(nil, "filtered")
+	assert.Empty(t, buf.String())
+	slogLogger.WithField("field", "value").WithError(errors.New("an error")).Info(nil, "info %d", 1)
+	output := decode(t, &buf)
+	assert.Equal(t, "INFO", output["level"])
+	assert.Equal(t, "info 1", output["msg"])
+	assert.Equal(t, "value", output["field"])
+	assert.Equal(t, "an error", output[ErrorKey])
+	assert.Contains(t, output["source"].(map[string]interface{})["file"], "bridge_test.go")
+	assert.Equal(t, handler, slogLogger.Configuration().Implementation())
+
+	mortarLogger := logger.CreateMortarLogger(Builder(handler))
+	mortarLogger.Custom(nil, logInt.TraceLevel, 0, "trace")
+	output = decode(t, &buf)
+	assert.Equal(t, "DEBUG-4", output["level"])
+	assert.Contains(t, output["source"].(map[string]interface{})["file"], "bridge_test.go")
+	mortarLogger.WithField("one", 1).Error(nil, "with fields")
+	assert.Contains(t, decode(t, &buf)["source"].(map[string]interface{})["file"], "bridge_test.go")
+}
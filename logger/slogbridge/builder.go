@@ -0,0 +1,149 @@
+package slogbridge
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/go-masonry/mortar/interfaces/log"
+)
+
+// ErrorKey is the attribute key of errors added with WithError
+const ErrorKey = "error"
+
+type slogConfig struct {
+	handler slog.Handler
+	level   log.Level
+	depth   int
+}
+
+type slogBuilder struct {
+	ll *list.List
+}
+
+// Builder creates a log.Builder backed by an slog.Handler, default log level is log.TraceLevel.
+//
+// Fields become attributes and errors are added as an ErrorKey attribute, the handler decides what is written.
+func Builder(handler slog.Handler) log.Builder {
+	b := &slogBuilder{
+		ll: list.New(),
+	}
+	b.ll.PushBack(func(cfg *slogConfig) {
+		cfg.handler = handler
+	})
+	return b
+}
+
+func (b *slogBuilder) SetLevel(level log.Level) log.Builder {
+	b.ll.PushBack(func(cfg *slogConfig) {
+		cfg.level = level
+	})
+	return b
+}
+
+func (b *slogBuilder) IncrementSkipFrames(addition int) log.Builder {
+	b.ll.PushBack(func(cfg *slogConfig) {
+		cfg.depth += addition
+	})
+	return b
+}
+
+func (b *slogBuilder) Build() log.Logger {
+	cfg := &slogConfig{
+		level: log.TraceLevel,
+	}
+	for e := b.ll.Front(); e != nil; e = e.Next() {
+		f := e.Value.(func(cfg *slogConfig))
+		f(cfg)
+	}
+	return &slogLogger{cfg: cfg}
+}
+
+type slogLogger struct {
+	cfg   *slogConfig
+	attrs []slog.Attr
+}
+
+func (s *slogLogger) Trace(ctx context.Context, format string, args ...interface{}) {
+	s.Custom(ctx, log.TraceLevel, 0, format, args...)
+}
+
+func (s *slogLogger) Debug(ctx context.Context, format string, args ...interface{}) {
+	s.Custom(ctx, log.DebugLevel, 0, format, args...)
+}
+
+func (s *slogLogger) Info(ctx context.Context, format string, args ...interface{}) {
+	s.Custom(ctx, log.InfoLevel, 0, format, args...)
+}
+
+func (s *slogLogger) Warn(ctx context.Context, format string, args ...interface{}) {
+	s.Custom(ctx, log.WarnLevel, 0, format, args...)
+}
+
+func (s *slogLogger) Error(ctx context.Context, format string, args ...interface{}) {
+	s.Custom(ctx, log.ErrorLevel, 0, format, args...)
+}
+
+func (s *slogLogger) Custom(ctx context.Context, level log.Level, skipAdditionalFrames int, format string, args ...interface{}) {
+	s.log(ctx, level, skipAdditionalFrames, format, args...)
+}
+
+func (s *slogLogger) WithError(err error) log.Fields {
+	if err == nil {
+		return s
+	}
+	return s.with(slog.String(ErrorKey, err.Error()))
+}
+
+func (s *slogLogger) WithField(name string, value interface{}) log.Fields {
+	return s.with(slog.Any(name, value))
+}
+
+func (s *slogLogger) Configuration() log.LoggerConfiguration {
+	return s
+}
+
+func (s *slogLogger) Level() log.Level {
+	return s.cfg.level
+}
+
+// Implementation returns the slog.Handler
+func (s *slogLogger) Implementation() interface{} {
+	return s.cfg.handler
+}
+
+func (s *slogLogger) with(attr slog.Attr) *slogLogger {
+	return &slogLogger{
+		cfg:   s.cfg,
+		attrs: append(s.attrs[:len(s.attrs):len(s.attrs)], attr),
+	}
+}
+
+// log must be called directly by Custom, since it calculates the caller
+func (s *slogLogger) log(ctx context.Context, level log.Level, skipAdditionalFrames int, format string, args ...interface{}) {
+	if level < s.cfg.level {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	slogLevel := ToSlogLevel(level)
+	if !s.cfg.handler.Enabled(ctx, slogLevel) {
+		return
+	}
+	message := format
+	if len(args) > 0 {
+		message = fmt.Sprintf(format, args...)
+	}
+	var pcs [1]uintptr
+	runtime.Callers(4+s.cfg.depth+skipAdditionalFrames, pcs[:]) // runtime.Callers, log, Custom, Info/Debug/...
+	record := slog.NewRecord(time.Now(), slogLevel, message, pcs[0])
+	record.AddAttrs(s.attrs...)
+	_ = s.cfg.handler.Handle(ctx, record)
+}
+
+var _ log.Logger = (*slogLogger)(nil)
+var _ log.LoggerConfiguration = (*slogLogger)(nil)
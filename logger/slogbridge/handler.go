@@ -0,0 +1,179 @@
+// Package slogbridge bridges log/slog and mortar log.Logger in both directions.
+//
+// NewHandler creates an slog.Handler that writes to a mortar log.Logger, libraries that use slog will log through mortar
+//
+//	slog.SetDefault(slog.New(slogbridge.NewHandler(logger)))
+//
+// Builder creates a mortar log.Builder that writes to any slog.Handler, mortar will log through an slog based backend
+//
+//	builder := slogbridge.Builder(slog.NewJSONHandler(os.Stderr, nil))
+package slogbridge
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/go-masonry/mortar/interfaces/log"
+)
+
+type slogHandler struct {
+	logger log.Logger
+	fields map[string]interface{} // groups are nested maps
+	groups []string
+}
+
+// NewHandler creates an slog.Handler backed by a mortar log.Logger.
+//
+// Attributes become fields, groups become nested maps and the record context is passed to the logger,
+// hence mortar ContextExtractors are called with it.
+func NewHandler(logger log.Logger) slog.Handler {
+	return &slogHandler{
+		logger: logger,
+		fields: make(map[string]interface{}),
+	}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	configuration := h.logger.Configuration()
+	return configuration == nil || configuration.Level() <= FromSlogLevel(level)
+}
+
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := h.fields
+	if record.NumAttrs() > 0 {
+		fields = cloneFields(h.fields)
+		group := openGroup(fields, h.groups)
+		record.Attrs(func(attr slog.Attr) bool {
+			addAttr(group, attr)
+			return true
+		})
+	}
+	var entry log.Fields = h.logger
+	for name, value := range fields {
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) == 0 {
+			continue // empty groups are omitted
+		}
+		entry = entry.WithField(name, value)
+	}
+	format := record.Message
+	if strings.ContainsRune(format, '%') {
+		entry.Custom(ctx, FromSlogLevel(record.Level), 0, "%s", record.Message)
+		return nil
+	}
+	entry.Custom(ctx, FromSlogLevel(record.Level), 0, format)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	fields := cloneFields(h.fields)
+	group := openGroup(fields, h.groups)
+	for _, attr := range attrs {
+		addAttr(group, attr)
+	}
+	return &slogHandler{
+		logger: h.logger,
+		fields: fields,
+		groups: h.groups,
+	}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+	return &slogHandler{
+		logger: h.logger,
+		fields: h.fields,
+		groups: append(h.groups[:len(h.groups):len(h.groups)], name),
+	}
+}
+
+// FromSlogLevel converts slog level to mortar log level, levels below slog.LevelDebug are log.TraceLevel
+func FromSlogLevel(level slog.Level) log.Level {
+	switch {
+	case level < slog.LevelDebug:
+		return log.TraceLevel
+	case level < slog.LevelInfo:
+		return log.DebugLevel
+	case level < slog.LevelWarn:
+		return log.InfoLevel
+	case level < slog.LevelError:
+		return log.WarnLevel
+	default:
+		return log.ErrorLevel
+	}
+}
+
+// ToSlogLevel converts mortar log level to slog level, log.TraceLevel is slog.LevelDebug-4
+func ToSlogLevel(level log.Level) slog.Level {
+	switch level {
+	case log.TraceLevel:
+		return slog.LevelDebug - 4
+	case log.DebugLevel:
+		return slog.LevelDebug
+	case log.InfoLevel:
+		return slog.LevelInfo
+	case log.WarnLevel:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// openGroup returns the map of the innermost group, creating missing ones
+func openGroup(fields map[string]interface{}, groups []string) map[string]interface{} {
+	for _, name := range groups {
+		group, ok := fields[name].(map[string]interface{})
+		if !ok {
+			group = make(map[string]interface{})
+			fields[name] = group
+		}
+		fields = group
+	}
+	return fields
+}
+
+func addAttr(fields map[string]interface{}, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return // empty attributes are ignored
+	}
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		groupAttrs := attr.Value.Group()
+		if len(groupAttrs) == 0 {
+			return
+		}
+		group := fields
+		if len(attr.Key) > 0 { // attributes of a group without a key are inlined
+			group = openGroup(fields, []string{attr.Key})
+		}
+		for _, groupAttr := range groupAttrs {
+			addAttr(group, groupAttr)
+		}
+	default:
+		value := attr.Value.Any()
+		if err, ok := value.(error); ok {
+			value = err.Error() // errors are usually marshaled as empty objects
+		}
+		fields[attr.Key] = value
+	}
+}
+
+// cloneFields deep copies groups, values are shared
+func cloneFields(fields map[string]interface{}) map[string]interface{} {
+	output := make(map[string]interface{}, len(fields))
+	for name, value := range fields {
+		if group, ok := value.(map[string]interface{}); ok {
+			value = cloneFields(group)
+		}
+		output[name] = value
+	}
+	return output
+}
+
+var _ slog.Handler = (*slogHandler)(nil)
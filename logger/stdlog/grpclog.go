@@ -0,0 +1,141 @@
+package stdlog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/go-masonry/mortar/interfaces/log"
+	"google.golang.org/grpc/grpclog"
+)
+
+var (
+	grpcLoggerMutex sync.Mutex
+	// currentGRPCLogger is the last grpclog.LoggerV2 set by RedirectGRPC, nil means the gRPC default one
+	currentGRPCLogger grpclog.LoggerV2
+)
+
+type grpcLogger struct {
+	logger log.Logger
+}
+
+// GRPCLogger creates a grpclog.LoggerV2 that writes to the provided logger with SourceGRPC.
+//
+// gRPC info entries are very verbose, hence they are logged as debug. Fatal entries are logged as errors,
+// grpclog exits after calling Fatal.
+//
+//	grpclog.SetLoggerV2(stdlog.GRPCLogger(logger)) // must be called before any gRPC function
+func GRPCLogger(logger log.Logger) grpclog.LoggerV2 {
+	return &grpcLogger{
+		logger: logger,
+	}
+}
+
+// RedirectGRPC sets GRPCLogger(logger) as the grpclog.LoggerV2, restore sets the previous one back.
+//
+// grpclog has no getter, so the previous logger is only known if it was set by RedirectGRPC as well. Otherwise
+// restore sets a logger that is configured the same way as the gRPC default one, using GRPC_GO_LOG_SEVERITY_LEVEL
+// and GRPC_GO_LOG_VERBOSITY_LEVEL environment variables.
+//
+// grpclog.SetLoggerV2 is not goroutine safe, both RedirectGRPC and restore must be called before any gRPC function
+// or once all of them have returned.
+func RedirectGRPC(logger log.Logger) (restore func()) {
+	grpcLoggerMutex.Lock()
+	defer grpcLoggerMutex.Unlock()
+	previous, redirected := currentGRPCLogger, GRPCLogger(logger)
+	currentGRPCLogger = redirected
+	grpclog.SetLoggerV2(redirected)
+	return func() {
+		grpcLoggerMutex.Lock()
+		defer grpcLoggerMutex.Unlock()
+		if currentGRPCLogger != redirected {
+			return // replaced since, don't override it
+		}
+		currentGRPCLogger = previous
+		if previous == nil {
+			previous = defaultGRPCLogger()
+		}
+		grpclog.SetLoggerV2(previous)
+	}
+}
+
+// defaultGRPCLogger mimics the logger gRPC creates when it's loaded
+func defaultGRPCLogger() grpclog.LoggerV2 {
+	errorW, warningW, infoW := io.Discard, io.Discard, io.Discard
+	switch os.Getenv("GRPC_GO_LOG_SEVERITY_LEVEL") {
+	case "", "ERROR", "error":
+		errorW = os.Stderr
+	case "WARNING", "warning":
+		warningW = os.Stderr
+	case "INFO", "info":
+		infoW = os.Stderr
+	}
+	verbosity, _ := strconv.Atoi(os.Getenv("GRPC_GO_LOG_VERBOSITY_LEVEL"))
+	return grpclog.NewLoggerV2WithVerbosity(infoW, warningW, errorW, verbosity)
+}
+
+func (g *grpcLogger) log(level log.Level, message string) {
+	logMessage(g.logger.WithField(SourceField, SourceGRPC), level, message)
+}
+
+func (g *grpcLogger) Info(args ...interface{}) {
+	g.log(log.DebugLevel, fmt.Sprint(args...))
+}
+
+func (g *grpcLogger) Infoln(args ...interface{}) {
+	g.log(log.DebugLevel, sprintln(args...))
+}
+
+func (g *grpcLogger) Infof(format string, args ...interface{}) {
+	g.log(log.DebugLevel, fmt.Sprintf(format, args...))
+}
+
+func (g *grpcLogger) Warning(args ...interface{}) {
+	g.log(log.WarnLevel, fmt.Sprint(args...))
+}
+
+func (g *grpcLogger) Warningln(args ...interface{}) {
+	g.log(log.WarnLevel, sprintln(args...))
+}
+
+func (g *grpcLogger) Warningf(format string, args ...interface{}) {
+	g.log(log.WarnLevel, fmt.Sprintf(format, args...))
+}
+
+func (g *grpcLogger) Error(args ...interface{}) {
+	g.log(log.ErrorLevel, fmt.Sprint(args...))
+}
+
+func (g *grpcLogger) Errorln(args ...interface{}) {
+	g.log(log.ErrorLevel, sprintln(args...))
+}
+
+func (g *grpcLogger) Errorf(format string, args ...interface{}) {
+	g.log(log.ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+func (g *grpcLogger) Fatal(args ...interface{}) {
+	g.log(log.ErrorLevel, fmt.Sprint(args...))
+}
+
+func (g *grpcLogger) Fatalln(args ...interface{}) {
+	g.log(log.ErrorLevel, sprintln(args...))
+}
+
+func (g *grpcLogger) Fatalf(format string, args ...interface{}) {
+	g.log(log.ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+// V reports verbosity 0, same as the default gRPC logger
+func (g *grpcLogger) V(l int) bool {
+	return l <= 0
+}
+
+func sprintln(args ...interface{}) string {
+	message := fmt.Sprintln(args...)
+	return message[:len(message)-1]
+}
+
+var _ grpclog.LoggerV2 = (*grpcLogger)(nil)
@@ -0,0 +1,101 @@
+// Package stdlog redirects output of the standard library `log` package and of gRPC `grpclog` into a mortar log.Logger.
+//
+// Every entry gets a SourceField, so it's easy to tell where it came from.
+package stdlog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	stdlog "log"
+	"strings"
+
+	"github.com/go-masonry/mortar/interfaces/log"
+)
+
+// SourceField holds where a redirected entry came from
+const SourceField = "source"
+
+// Sources of redirected entries
+const (
+	SourceStdLog     = "stdlog"
+	SourceHTTPServer = "http.server"
+	SourceGRPC       = "grpc"
+)
+
+type writer struct {
+	logger log.Logger
+	level  log.Level
+	source string
+}
+
+// Writer creates an io.Writer that logs every line with the provided logger.
+//
+// Level is detected from the line if it contains one of DEBUG, INFO, WARN, WARNING or ERROR words before the message,
+// otherwise level is used. Lines of net/http servers (`http: ...`) are logged as warnings with SourceHTTPServer.
+func Writer(logger log.Logger, level log.Level, source string) io.Writer {
+	return &writer{
+		logger: logger,
+		level:  level,
+		source: source,
+	}
+}
+
+// New creates a standard library logger that writes to the provided logger, use it as http.Server.ErrorLog
+func New(logger log.Logger, level log.Level, source string) *stdlog.Logger {
+	return stdlog.New(Writer(logger, level, source), "", 0)
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(bytes.TrimRight(p, "\n"), []byte{'\n'}) {
+		if len(line) > 0 {
+			w.writeLine(string(line))
+		}
+	}
+	return len(p), nil
+}
+
+func (w *writer) writeLine(line string) {
+	level, source := w.level, w.source
+	switch {
+	case strings.HasPrefix(line, "http: panic"):
+		level, source = log.ErrorLevel, SourceHTTPServer
+	case strings.HasPrefix(line, "http: "), strings.HasPrefix(line, "http2: "):
+		level, source = log.WarnLevel, SourceHTTPServer
+	default:
+		if detected, ok := detectLevel(line); ok {
+			level = detected
+		}
+	}
+	logMessage(w.logger.WithField(SourceField, source), level, line)
+}
+
+// logMessage uses message as the format when possible, since sampling is done per format
+func logMessage(logger log.Fields, level log.Level, message string) {
+	if strings.ContainsRune(message, '%') {
+		logger.Custom(context.Background(), level, 0, "%s", message)
+		return
+	}
+	logger.Custom(context.Background(), level, 0, message)
+}
+
+// detectLevel looks for a level word in the first few words of a line, `[Mortar] WARNING ...` or `ERROR: ...`
+func detectLevel(line string) (log.Level, bool) {
+	words := strings.Fields(line)
+	if len(words) > 3 {
+		words = words[:3]
+	}
+	for _, word := range words {
+		switch strings.Trim(word, "[]:") {
+		case "DEBUG":
+			return log.DebugLevel, true
+		case "INFO":
+			return log.InfoLevel, true
+		case "WARN", "WARNING":
+			return log.WarnLevel, true
+		case "ERROR":
+			return log.ErrorLevel, true
+		}
+	}
+	return log.InfoLevel, false
+}
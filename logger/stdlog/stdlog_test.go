@@ -0,0 +1,68 @@
+package stdlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	logInt "github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/logger/naive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/grpclog"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) (output []map[string]interface{}) {
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var entry map[string]interface{}
+		require.NoError(t, decoder.Decode(&entry))
+		output = append(output, map[string]interface{}{"level": entry["level"], "message": entry["message"], SourceField: entry[SourceField]})
+	}
+	return
+}
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := naive.Builder().SetWriter(&buf).JSON().ExcludeTime().Build()
+	stdLogger := New(logger, logInt.InfoLevel, SourceStdLog)
+	stdLogger.Printf("[Mortar] WARNING \tNo logger builder provided")
+	stdLogger.Print("plain line 100%\nERROR: second line")
+	stdLogger.Printf("http: TLS handshake error from 127.0.0.1:1234: EOF")
+	stdLogger.Printf("http: panic serving 127.0.0.1:1234: boom")
+	assert.Equal(t, []map[string]interface{}{
+		{"level": "warn", "message": "[Mortar] WARNING \tNo logger builder provided", SourceField: SourceStdLog},
+		{"level": "info", "message": "plain line 100%", SourceField: SourceStdLog},
+		{"level": "error", "message": "ERROR: second line", SourceField: SourceStdLog},
+		{"level": "warn", "message": "http: TLS handshake error from 127.0.0.1:1234: EOF", SourceField: SourceHTTPServer},
+		{"level": "error", "message": "http: panic serving 127.0.0.1:1234: boom", SourceField: SourceHTTPServer},
+	}, decodeLines(t, &buf))
+}
+
+func TestGRPCLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := GRPCLogger(naive.Builder().SetWriter(&buf).JSON().ExcludeTime().Build())
+	logger.Infof("channel %d created", 1)
+	logger.Warningln("transport", "closing")
+	logger.Error("failed")
+	assert.True(t, logger.V(0))
+	assert.False(t, logger.V(2))
+	assert.Equal(t, []map[string]interface{}{
+		{"level": "debug", "message": "channel 1 created", SourceField: SourceGRPC},
+		{"level": "warn", "message": "transport closing", SourceField: SourceGRPC},
+		{"level": "error", "message": "failed", SourceField: SourceGRPC},
+	}, decodeLines(t, &buf))
+}
+
+func TestRedirectGRPC(t *testing.T) {
+	var first, second bytes.Buffer
+	restoreFirst := RedirectGRPC(naive.Builder().SetWriter(&first).JSON().ExcludeTime().Build())
+	restoreSecond := RedirectGRPC(naive.Builder().SetWriter(&second).JSON().ExcludeTime().Build())
+	grpclog.Warning("to second")
+	restoreSecond()
+	grpclog.Warning("to first")
+	restoreFirst()
+	grpclog.Warning("to default")
+	assert.Equal(t, []map[string]interface{}{{"level": "warn", "message": "to first", SourceField: SourceGRPC}}, decodeLines(t, &first))
+	assert.Equal(t, []map[string]interface{}{{"level": "warn", "message": "to second", SourceField: SourceGRPC}}, decodeLines(t, &second))
+}
@@ -2,65 +2,124 @@ package logger
 
 import (
 	"context"
+	"strings"
 
 	"github.com/go-masonry/mortar/interfaces/log"
 )
 
 const (
 	compensateMortarLoggerWrapper = 1
+	// NameField is the field that holds the name of a named logger, see log.Named
+	NameField = "logger"
 )
 
 type loggerWrapper struct {
 	contextExtractors []log.ContextExtractor
 	logger            log.Logger
+	level             *atomicLevel
+	sampler           *sampler
+	redact            FieldRedactor
+	recent            *recentLogs
+	name              string
 }
 
 // CreateMortarLogger creates a new mortar logger which is a wrapper to support
 //   - ContextExtractors
 //
+// Use Builder for additional options such as runtime log level, named log levels and sampling.
+//
 // **Important**
 //
 //	This constructor will call builder.IncrementSkipFrames to peel additional layer of itself.
 func CreateMortarLogger(builder log.Builder, contextExtractors ...log.ContextExtractor) log.Logger {
+	return Builder().AddExtractors(contextExtractors...).Build(builder)
+}
+
+func newLoggerWrapper(cfg *wrapperConfig, builder log.Builder) *loggerWrapper {
 	logger := builder.IncrementSkipFrames(compensateMortarLoggerWrapper).Build() // add 1
-	return &loggerWrapper{
-		contextExtractors: contextExtractors,
+	level := newAtomicLevel(logger, cfg.named)
+	if cfg.level != nil {
+		level.level = int32(*cfg.level) // initial level is not a change
+	}
+	wrapper := &loggerWrapper{
+		contextExtractors: cfg.extractors,
 		logger:            logger,
+		level:             level,
+		redact:            cfg.redact,
 	}
+	if cfg.sampling != nil {
+		wrapper.sampler = newSampler(*cfg.sampling, logger)
+	}
+	if cfg.recent != nil {
+		wrapper.recent = newRecentLogs(*cfg.recent)
+	}
+	return wrapper
 }
 
 func (l *loggerWrapper) Trace(ctx context.Context, format string, args ...interface{}) {
-	newEntry(l.contextExtractors, l.logger, false).Trace(ctx, format, args...)
+	newEntry(l, false).Trace(ctx, format, args...)
 }
 
 func (l *loggerWrapper) Debug(ctx context.Context, format string, args ...interface{}) {
-	newEntry(l.contextExtractors, l.logger, false).Debug(ctx, format, args...)
+	newEntry(l, false).Debug(ctx, format, args...)
 }
 
 func (l *loggerWrapper) Info(ctx context.Context, format string, args ...interface{}) {
-	newEntry(l.contextExtractors, l.logger, false).Info(ctx, format, args...)
+	newEntry(l, false).Info(ctx, format, args...)
 }
 
 func (l *loggerWrapper) Warn(ctx context.Context, format string, args ...interface{}) {
-	newEntry(l.contextExtractors, l.logger, false).Warn(ctx, format, args...)
+	newEntry(l, false).Warn(ctx, format, args...)
 }
 
 func (l *loggerWrapper) Error(ctx context.Context, format string, args ...interface{}) {
-	newEntry(l.contextExtractors, l.logger, false).Error(ctx, format, args...)
+	newEntry(l, false).Error(ctx, format, args...)
 }
 
 func (l *loggerWrapper) Custom(ctx context.Context, level log.Level, skipAdditionalFrames int, format string, args ...interface{}) {
-	newEntry(l.contextExtractors, l.logger, false).Custom(ctx, level, skipAdditionalFrames, format, args...)
+	newEntry(l, false).Custom(ctx, level, skipAdditionalFrames, format, args...)
 }
 
 func (l *loggerWrapper) WithError(err error) log.Fields {
-	return newEntry(l.contextExtractors, l.logger, true).WithError(err)
+	return newEntry(l, true).WithError(err)
 }
 
 func (l *loggerWrapper) WithField(name string, value interface{}) log.Fields {
-	return newEntry(l.contextExtractors, l.logger, true).WithField(name, value)
+	return newEntry(l, true).WithField(name, value)
 }
 
+// Configuration returns AtomicLevel that can be used to change log level at runtime
 func (l *loggerWrapper) Configuration() log.LoggerConfiguration {
-	return l.logger.Configuration()
+	return l.level
+}
+
+// Sampling returns nil if sampling is disabled, see WrapperBuilder.SetSampling
+func (l *loggerWrapper) Sampling() Sampling {
+	if l.sampler == nil {
+		return nil // avoid a typed nil
+	}
+	return l.sampler
+}
+
+// RecentLogs returns nil if recording is disabled, see WrapperBuilder.SetRecentLogs
+func (l *loggerWrapper) RecentLogs() RecentLogs {
+	if l.recent == nil {
+		return nil // avoid a typed nil
+	}
+	return l.recent
 }
+
+// Named returns a child logger that shares everything except its name, it's added to every entry as NameField
+func (l *loggerWrapper) Named(name string) log.Logger {
+	name = strings.ToLower(name)
+	if len(l.name) > 0 {
+		name = l.name + "." + name
+	}
+	child := *l
+	child.name = name
+	return &child
+}
+
+var _ log.NamedLogger = (*loggerWrapper)(nil)
+var _ Sampled = (*loggerWrapper)(nil)
+var _ Recorded = (*loggerWrapper)(nil)
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	logInt "github.com/go-masonry/mortar/interfaces/log"
 	mock_log "github.com/go-masonry/mortar/interfaces/log/mock"
@@ -83,6 +84,22 @@ func (s *wrapperSuite) TestIncludeCallerWithFields() {
 	s.Contains(output.String(), "logger/wrapper_test.go")
 }
 
+func (s *wrapperSuite) TestNaiveJSONWithExtractors() {
+	var output bytes.Buffer
+	extractor := func(context.Context) map[string]interface{} {
+		return map[string]interface{}{"extracted": true}
+	}
+	builder := naive.Builder().SetWriter(&output).JSON().IncludeCaller().SetLevel(logInt.InfoLevel)
+	logger := CreateMortarLogger(builder, extractor)
+	logger.Info(nil, "info line")
+	s.Contains(output.String(), `"extracted":true`)
+	s.Contains(output.String(), "logger/wrapper_test.go")
+	output.Reset()
+	logger.WithField("field", "value").Info(nil, "info line")
+	s.Contains(output.String(), `"field":"value"`)
+	s.Contains(output.String(), "logger/wrapper_test.go")
+}
+
 func (s *wrapperSuite) TestFields() {
 	controller := gomock.NewController(s.T())
 	mockLogger := mock_log.NewMockLogger(controller)
@@ -166,6 +183,87 @@ func (s *wrapperSuite) TestConfiguration() {
 	s.Equal(logInt.InfoLevel, logger.Configuration().Level())
 }
 
+func (s *wrapperSuite) TestRuntimeLevel() {
+	var output bytes.Buffer
+	builder := naive.Builder().SetWriter(&output).SetLevel(logInt.ErrorLevel)
+	logger := Builder().SetLevel(logInt.InfoLevel).Build(builder)
+	level := logger.Configuration().(AtomicLevel)
+	s.Equal(logInt.InfoLevel, level.Level())
+	s.Empty(level.Changes(), "initial level is not a change")
+	logger.Info(nil, "info line")
+	s.Contains(output.String(), "info line", "builder level is overridden")
+	logger.Debug(nil, "no debug line")
+	s.NotContains(output.String(), "no debug line")
+
+	change := level.SetLevel(logInt.DebugLevel, 0, "tester", "")
+	s.Equal(logInt.InfoLevel, change.From)
+	s.Equal(logInt.DebugLevel, change.To)
+	s.Equal("tester", change.ChangedBy)
+	s.Nil(change.RevertAt)
+	logger.WithField("field", "value").Debug(nil, "debug line")
+	s.Contains(output.String(), "debug line")
+	s.Equal([]LevelChange{change}, level.Changes())
+}
+
+func (s *wrapperSuite) TestRuntimeLevelTTL() {
+	logger := Builder().SetLevel(logInt.InfoLevel).Build(naive.Builder())
+	level := logger.Configuration().(AtomicLevel)
+	change := level.SetLevel(logInt.TraceLevel, 10*time.Millisecond, "tester", "")
+	s.Require().NotNil(change.RevertAt)
+	s.Equal(logInt.TraceLevel, level.Level())
+	s.Eventually(func() bool {
+		return level.Level() == logInt.InfoLevel
+	}, time.Second, 5*time.Millisecond)
+	changes := level.Changes()
+	s.Require().Len(changes, 2)
+	s.Equal(ChangedByTTL, changes[1].ChangedBy)
+	s.Equal(logInt.TraceLevel, changes[1].From)
+}
+
+func (s *wrapperSuite) TestRuntimeLevelTTLCanceled() {
+	logger := Builder().SetLevel(logInt.InfoLevel).Build(naive.Builder())
+	level := logger.Configuration().(AtomicLevel)
+	level.SetLevel(logInt.TraceLevel, 10*time.Millisecond, "tester", "")
+	level.SetLevel(logInt.WarnLevel, 0, "tester", "")
+	time.Sleep(30 * time.Millisecond)
+	s.Equal(logInt.WarnLevel, level.Level())
+	s.Len(level.Changes(), 2)
+}
+
+func (s *wrapperSuite) TestNamedLevels() {
+	var output bytes.Buffer
+	builder := naive.Builder().SetWriter(&output).JSON()
+	logger := Builder().
+		SetLevel(logInt.InfoLevel).
+		SetNamedLevels(map[string]logInt.Level{
+			"http":        logInt.WarnLevel,
+			"http.Client": logInt.DebugLevel,
+		}).
+		Build(builder)
+	logInt.Named(logger, "http").Info(nil, "http info")
+	s.Empty(output.String(), "http is warn")
+	client := logInt.Named(logInt.Named(logger, "http"), "client")
+	client.Debug(nil, "client debug")
+	s.Contains(output.String(), `"logger":"http.client"`)
+	s.Contains(output.String(), "client debug")
+	output.Reset()
+	logInt.Named(logger, "http.client.pool").Debug(nil, "longest prefix")
+	s.Contains(output.String(), "longest prefix")
+	output.Reset()
+	logInt.Named(logger, "https").WithField("field", "value").Info(nil, "not a prefix")
+	s.Contains(output.String(), "not a prefix")
+	output.Reset()
+	logger.Configuration().(AtomicLevel).SetLevel(logInt.ErrorLevel, 0, "tester", "")
+	client.Debug(nil, "named level wins")
+	s.Contains(output.String(), "named level wins")
+}
+
+func (s *wrapperSuite) TestNamedNotSupported() {
+	controller := gomock.NewController(s.T())
+	mockLogger := mock_log.NewMockLogger(controller)
+	s.Equal(mockLogger, logInt.Named(mockLogger, "name"))
+}
+
 func (s *wrapperSuite) TestContextExtractors() {
 	contextExtractor := func(ctx context.Context) map[string]interface{} {
 		s.Require().NotNil(ctx)
@@ -0,0 +1,82 @@
+package context
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-masonry/mortar/interfaces/log"
+	"github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Log fields added by LoggerTraceContextExtractor
+const (
+	TraceIDField = "trace_id"
+	SpanIDField  = "span_id"
+	SampledField = "sampled"
+)
+
+// LoggerTraceContextExtractor creates a context extractor for logger that adds trace_id, span_id and sampled fields
+// of the active span. This is useful if you want to join log entries with traces.
+//
+// OpenTelemetry spans are looked up first, then opentracing spans. Opentracing doesn't expose IDs, hence they are
+// taken from span context methods `TraceID()`, `SpanID()`, `IsSampled()` or fields `TraceID`, `SpanID`, `Sampled`
+// which is what Jaeger and the opentracing mock tracer have.
+func LoggerTraceContextExtractor() log.ContextExtractor {
+	return extractTraceInfo
+}
+
+func extractTraceInfo(ctx context.Context) map[string]interface{} {
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		return map[string]interface{}{
+			TraceIDField: spanContext.TraceID().String(),
+			SpanIDField:  spanContext.SpanID().String(),
+			SampledField: spanContext.IsSampled(),
+		}
+	}
+	if span := opentracing.SpanFromContext(ctx); span != nil {
+		return openTracingInfo(span.Context())
+	}
+	return nil
+}
+
+func openTracingInfo(spanContext opentracing.SpanContext) map[string]interface{} {
+	output := make(map[string]interface{})
+	value := reflect.ValueOf(spanContext)
+	if traceID, ok := lookup(value, "TraceID"); ok {
+		output[TraceIDField] = fmt.Sprint(traceID)
+	}
+	if spanID, ok := lookup(value, "SpanID"); ok {
+		output[SpanIDField] = fmt.Sprint(spanID)
+	}
+	if sampled, ok := lookup(value, "IsSampled", "Sampled"); ok {
+		switch v := sampled.(type) {
+		case bool:
+			output[SampledField] = v
+		case *bool:
+			if v != nil {
+				output[SampledField] = *v
+			}
+		}
+	}
+	return output
+}
+
+// lookup returns the result of the first method without arguments or exported struct field with one of the names
+func lookup(value reflect.Value, names ...string) (interface{}, bool) {
+	if !value.IsValid() {
+		return nil, false
+	}
+	for _, name := range names {
+		if method := value.MethodByName(name); method.IsValid() && method.Type().NumIn() == 0 && method.Type().NumOut() == 1 {
+			return method.Call(nil)[0].Interface(), true
+		}
+		if structValue := reflect.Indirect(value); structValue.Kind() == reflect.Struct {
+			if field := structValue.FieldByName(name); field.IsValid() && field.CanInterface() {
+				return field.Interface(), true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,224 @@
+package grpcgateway
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	serverInt "github.com/go-masonry/mortar/interfaces/http/server"
+	"go.uber.org/fx"
+)
+
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	defaultCORSHeaders = []string{"Accept", "Authorization", "Content-Type", "X-Requested-With"}
+	// headers browser clients send with every call, they are allowed when the protocol is enabled
+	grpcWebCORSHeaders = []string{"Content-Type", "X-Grpc-Web", "X-User-Agent", "Grpc-Timeout"}
+	connectCORSHeaders = []string{"Content-Type", "Connect-Protocol-Version", "Connect-Timeout-Ms"}
+)
+
+type corsDeps struct {
+	fx.In
+
+	Config cfg.Config
+}
+
+type corsPolicy struct {
+	origins          []string
+	wildcardOrigins  [][2]string // prefix and suffix around `*`
+	originPatterns   []*regexp.Regexp
+	allowAnyOrigin   bool
+	methods          []string
+	headers          map[string]struct{}
+	allowAnyHeader   bool
+	allowedMethods   string
+	allowedHeaders   string
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           string
+}
+
+// CORSInterceptor answers CORS preflight requests and adds CORS headers to responses of allowed origins, see keys.CORSAllowedOrigins.
+// Requests without an Origin header are not affected. If no origins are configured requests are passed as is.
+//
+// Allowing any origin (`*`) together with credentials is an error, since every site could make authenticated calls.
+func CORSInterceptor(deps corsDeps) (serverInt.GRPCGatewayInterceptor, error) {
+	policy, err := deps.policy()
+	if err != nil {
+		return nil, err
+	}
+	if !policy.allowAnyOrigin && len(policy.origins) == 0 && len(policy.wildcardOrigins) == 0 && len(policy.originPatterns) == 0 {
+		return func(handler http.Handler) http.Handler { return handler }, nil
+	}
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if len(origin) == 0 {
+				handler.ServeHTTP(w, r)
+				return
+			}
+			if r.Method == http.MethodOptions && len(r.Header.Get("Access-Control-Request-Method")) > 0 {
+				policy.preflight(w, r, origin)
+				return
+			}
+			w.Header().Add("Vary", "Origin")
+			if policy.originAllowed(origin) {
+				policy.allowOrigin(w.Header(), origin)
+				if len(policy.exposedHeaders) > 0 {
+					w.Header().Set("Access-Control-Expose-Headers", policy.exposedHeaders)
+				}
+			}
+			handler.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+func (deps corsDeps) policy() (*corsPolicy, error) {
+	policy := &corsPolicy{
+		headers:          make(map[string]struct{}),
+		exposedHeaders:   strings.Join(deps.Config.Get(confkeys.CORSExposedHeaders).StringSlice(), ", "),
+		allowCredentials: deps.Config.Get(confkeys.CORSAllowCredentials).Bool(),
+	}
+	for _, origin := range deps.Config.Get(confkeys.CORSAllowedOrigins).StringSlice() {
+		origin = strings.ToLower(origin)
+		switch index := strings.Index(origin, "*"); {
+		case origin == "*":
+			policy.allowAnyOrigin = true
+		case index >= 0:
+			policy.wildcardOrigins = append(policy.wildcardOrigins, [2]string{origin[:index], origin[index+1:]})
+		default:
+			policy.origins = append(policy.origins, origin)
+		}
+	}
+	for _, pattern := range deps.Config.Get(confkeys.CORSAllowedOriginPatterns).StringSlice() {
+		expression, err := regexp.Compile("^(?:" + pattern + ")$") // the entire origin must match
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q, %w", confkeys.CORSAllowedOriginPatterns, pattern, err)
+		}
+		policy.originPatterns = append(policy.originPatterns, expression)
+	}
+	if policy.allowAnyOrigin && policy.allowCredentials {
+		return nil, fmt.Errorf("%s can't allow any origin when %s is set, list the allowed origins instead", confkeys.CORSAllowedOrigins, confkeys.CORSAllowCredentials)
+	}
+	methods := deps.Config.Get(confkeys.CORSAllowedMethods).StringSlice()
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	for _, method := range methods {
+		policy.methods = append(policy.methods, strings.ToUpper(method))
+	}
+	policy.allowedMethods = strings.Join(policy.methods, ", ")
+	headers := deps.Config.Get(confkeys.CORSAllowedHeaders).StringSlice()
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+	if deps.Config.Get(confkeys.ExternalRESTGRPCWeb).Bool() {
+		headers = append(headers, grpcWebCORSHeaders...)
+	}
+	if deps.Config.Get(confkeys.ExternalRESTConnect).Bool() {
+		headers = append(headers, connectCORSHeaders...)
+	}
+	allowedHeaders := make([]string, 0, len(headers))
+	for _, header := range headers {
+		if header == "*" {
+			policy.allowAnyHeader = true
+		}
+		if _, ok := policy.headers[strings.ToLower(header)]; !ok {
+			policy.headers[strings.ToLower(header)] = struct{}{}
+			allowedHeaders = append(allowedHeaders, header)
+		}
+	}
+	policy.allowedHeaders = strings.Join(allowedHeaders, ", ")
+	if maxAge := deps.Config.Get(confkeys.CORSMaxAge); maxAge.IsSet() {
+		policy.maxAge = strconv.Itoa(int(maxAge.Duration().Seconds()))
+	}
+	return policy, nil
+}
+
+// preflight requests are answered here, they are not passed to the handler
+func (p *corsPolicy) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	header := w.Header()
+	header.Add("Vary", "Origin")
+	header.Add("Vary", "Access-Control-Request-Method")
+	header.Add("Vary", "Access-Control-Request-Headers")
+	requestedHeaders := r.Header.Values("Access-Control-Request-Headers")
+	if p.originAllowed(origin) && p.methodAllowed(r.Header.Get("Access-Control-Request-Method")) && p.headersAllowed(requestedHeaders) {
+		p.allowOrigin(header, origin)
+		header.Set("Access-Control-Allow-Methods", p.allowedMethods)
+		if p.allowAnyHeader {
+			if requested := strings.Join(requestedHeaders, ", "); len(requested) > 0 {
+				header.Set("Access-Control-Allow-Headers", requested)
+			}
+		} else {
+			header.Set("Access-Control-Allow-Headers", p.allowedHeaders)
+		}
+		if len(p.maxAge) > 0 {
+			header.Set("Access-Control-Max-Age", p.maxAge)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *corsPolicy) allowOrigin(header http.Header, origin string) {
+	if p.allowAnyOrigin { // never with credentials, see policy
+		header.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		header.Set("Access-Control-Allow-Origin", origin)
+	}
+	if p.allowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func (p *corsPolicy) originAllowed(origin string) bool {
+	if p.allowAnyOrigin {
+		return true
+	}
+	lowerOrigin := strings.ToLower(origin)
+	for _, allowed := range p.origins {
+		if allowed == lowerOrigin {
+			return true
+		}
+	}
+	for _, wildcard := range p.wildcardOrigins {
+		if len(lowerOrigin) >= len(wildcard[0])+len(wildcard[1]) &&
+			strings.HasPrefix(lowerOrigin, wildcard[0]) && strings.HasSuffix(lowerOrigin, wildcard[1]) {
+			return true
+		}
+	}
+	for _, pattern := range p.originPatterns {
+		if pattern.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *corsPolicy) methodAllowed(method string) bool {
+	for _, allowed := range p.methods {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *corsPolicy) headersAllowed(requested []string) bool {
+	if p.allowAnyHeader {
+		return true
+	}
+	for _, line := range requested {
+		for _, header := range strings.Split(line, ",") {
+			if header = strings.ToLower(strings.TrimSpace(header)); len(header) > 0 {
+				if _, ok := p.headers[header]; !ok {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
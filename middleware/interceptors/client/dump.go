@@ -9,6 +9,9 @@ import (
 	"go.uber.org/fx"
 )
 
+// DumpLoggerName is the name of the dump interceptor logger, see log.Named
+const DumpLoggerName = "middleware.http.client"
+
 type dumpHTTPDeps struct {
 	fx.In
 
@@ -18,13 +21,14 @@ type dumpHTTPDeps struct {
 // DumpRESTClientInterceptor usefull when you want to log what is actually sent to the external HTTP server
 // and was returned.
 func DumpRESTClientInterceptor(deps dumpHTTPDeps) client.HTTPClientInterceptor {
+	logger := log.Named(deps.Logger, DumpLoggerName)
 	return func(req *http.Request, handler client.HTTPHandler) (*http.Response, error) {
 		reqBody, err := httputil.DumpRequestOut(req, true)
-		deps.Logger.WithError(err).Debug(req.Context(), "Request:\n%s\n", reqBody)
+		logger.WithError(err).Debug(req.Context(), "Request:\n%s\n", reqBody)
 		res, err := handler(req)
 		if err == nil {
 			resBody, dumpErr := httputil.DumpResponse(res, true)
-			deps.Logger.WithError(dumpErr).Debug(req.Context(), "Response:\n%s\n", resBody)
+			logger.WithError(dumpErr).Debug(req.Context(), "Response:\n%s\n", resBody)
 		}
 		return res, err
 	}
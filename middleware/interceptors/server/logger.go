@@ -12,6 +12,9 @@ import (
 	"google.golang.org/grpc"
 )
 
+// LoggerName is the name of the logging interceptor logger, see log.Named
+const LoggerName = "middleware.grpc.server"
+
 type loggerInterceptorDeps struct {
 	fx.In
 
@@ -21,6 +24,8 @@ type loggerInterceptorDeps struct {
 
 // LoggerGRPCInterceptor logging interceptor, it will log grpc server call with request/response if configured
 func LoggerGRPCInterceptor(deps loggerInterceptorDeps) grpc.UnaryServerInterceptor {
+	logger := log.Named(deps.Logger, LoggerName)
+	redactor := utils.RedactorFromConfig(deps.Config)
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		start := time.Now()
 		resp, err = handler(ctx, req)
@@ -33,7 +38,7 @@ func LoggerGRPCInterceptor(deps loggerInterceptorDeps) grpc.UnaryServerIntercept
 					}
 				}
 			}
-			entry := deps.Logger.
+			entry := logger.
 				WithError(err).
 				WithField("api", info.FullMethod).
 				WithField("start", start).
@@ -43,11 +48,11 @@ func LoggerGRPCInterceptor(deps loggerInterceptorDeps) grpc.UnaryServerIntercept
 			}
 			// log request if needed
 			if deps.Config.Get(confkeys.MiddlewareLogIncludeRequest).Bool() {
-				entry = addBodyToLogger(entry, "request", req)
+				entry = addBodyToLogger(redactor, entry, "request", req)
 			}
 			// log response if needed
 			if deps.Config.Get(confkeys.MiddlewareLogIncludeResponse).Bool() {
-				entry = addBodyToLogger(entry, "response", resp)
+				entry = addBodyToLogger(redactor, entry, "response", resp)
 			}
 			entry.Custom(ctx, level, 0, "gRPC call finished")
 		}
@@ -55,8 +60,8 @@ func LoggerGRPCInterceptor(deps loggerInterceptorDeps) grpc.UnaryServerIntercept
 	}
 }
 
-func addBodyToLogger(entry log.Fields, name string, i interface{}) log.Fields {
-	if bytes, err := utils.MarshalMessageBody(i); err == nil {
+func addBodyToLogger(redactor *utils.Redactor, entry log.Fields, name string, i interface{}) log.Fields {
+	if bytes, err := redactor.MarshalMessageBody(i); err == nil {
 		return entry.WithField(name, bytes)
 	}
 	return entry
@@ -3,10 +3,11 @@ package trace
 import (
 	"context"
 	"net/http"
-	"net/http/httputil"
 
 	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
 	"github.com/go-masonry/mortar/interfaces/http/client"
+	"github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/utils"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
 	"google.golang.org/grpc"
@@ -15,6 +16,8 @@ import (
 
 // TracerGRPCClientInterceptor is a grpc tracing client interceptor, it can log req/resp if needed
 func TracerGRPCClientInterceptor(deps tracingDeps) grpc.UnaryClientInterceptor {
+	deps.Logger = log.Named(deps.Logger, GRPCClientLoggerName)
+	redactor := utils.RedactorFromConfig(deps.Config)
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
 		if deps.Tracer == nil {
 			return invoker(ctx, method, req, reply, cc, opts...)
@@ -24,7 +27,7 @@ func TracerGRPCClientInterceptor(deps tracingDeps) grpc.UnaryClientInterceptor {
 		defer span.Finish()
 		// log request if needed
 		if deps.Config.Get(confkeys.GRPCClientTraceIncludeRequest).Bool() {
-			addBodyToSpan(span, "request", req)
+			addBodyToSpan(redactor, span, "request", req)
 		}
 		err := invoker(ctx, method, req, reply, cc, opts...)
 		if err != nil {
@@ -32,7 +35,7 @@ func TracerGRPCClientInterceptor(deps tracingDeps) grpc.UnaryClientInterceptor {
 		} else {
 			// log response if needed
 			if deps.Config.Get(confkeys.GRPCClientTraceIncludeResponse).Bool() {
-				addBodyToSpan(span, "response", reply)
+				addBodyToSpan(redactor, span, "response", reply)
 			}
 		}
 		return err
@@ -41,11 +44,13 @@ func TracerGRPCClientInterceptor(deps tracingDeps) grpc.UnaryClientInterceptor {
 
 // TracerRESTClientInterceptor is a REST tracing client interceptor, it can log req/resp if needed
 func TracerRESTClientInterceptor(deps tracingDeps) client.HTTPClientInterceptor {
+	deps.Logger = log.Named(deps.Logger, RESTClientLoggerName)
+	redactor := utils.RedactorFromConfig(deps.Config)
 	return func(req *http.Request, handler client.HTTPHandler) (resp *http.Response, err error) {
 		if deps.Tracer == nil {
 			return handler(req)
 		}
-		span, ctx := deps.newClientSpanForREST(req)
+		span, ctx := deps.newClientSpanForREST(redactor, req)
 		defer span.Finish()
 
 		req = req.WithContext(ctx)
@@ -55,8 +60,8 @@ func TracerRESTClientInterceptor(deps tracingDeps) client.HTTPClientInterceptor
 		} else {
 			ext.HTTPStatusCode.Set(span, uint16(resp.StatusCode))
 			if deps.Config.Get(confkeys.HTTPClientTraceIncludeResponse).Bool() {
-				if respDump, dumpErr := httputil.DumpResponse(resp, true); dumpErr == nil {
-					addBodyToSpan(span, "response", respDump)
+				if respDump, dumpErr := redactor.DumpResponse(resp); dumpErr == nil {
+					addBodyToSpan(redactor, span, "response", respDump)
 				} else {
 					deps.Logger.WithError(dumpErr).Debug(ctx, "failed to dump response")
 				}
@@ -76,15 +81,15 @@ func (d tracingDeps) newClientSpanForGRPC(ctx context.Context, methodName string
 	return span, clientContext
 }
 
-func (d tracingDeps) newClientSpanForREST(req *http.Request) (opentracing.Span, context.Context) {
+func (d tracingDeps) newClientSpanForREST(redactor *utils.Redactor, req *http.Request) (opentracing.Span, context.Context) {
 	var ctx = context.Background()
 	if req.Context() != nil {
 		ctx = req.Context()
 	}
 	span, clientContext := opentracing.StartSpanFromContextWithTracer(ctx, d.Tracer, req.URL.Path, ext.SpanKindRPCClient, restTag)
 	if d.Config.Get(confkeys.HTTPClientTraceIncludeRequest).Bool() {
-		if reqDump, dumpErr := httputil.DumpRequestOut(req, true); dumpErr == nil {
-			addBodyToSpan(span, "request", reqDump)
+		if reqDump, dumpErr := redactor.DumpRequestOut(req); dumpErr == nil {
+			addBodyToSpan(redactor, span, "request", reqDump)
 		} else {
 			d.Logger.WithError(dumpErr).Debug(ctx, "failed to dump request")
 		}
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	"github.com/go-masonry/mortar/interfaces/log"
+	"github.com/go-masonry/mortar/utils"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
 	"google.golang.org/grpc"
@@ -11,6 +13,8 @@ import (
 
 // GRPCTracingUnaryServerInterceptor is a grpc unary server interceptor that adds trace information of the invoked grpc method and starts a new span
 func GRPCTracingUnaryServerInterceptor(deps tracingDeps) grpc.UnaryServerInterceptor {
+	deps.Logger = log.Named(deps.Logger, GRPCServerLoggerName)
+	redactor := utils.RedactorFromConfig(deps.Config)
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		if deps.Tracer == nil {
 			return handler(ctx, req)
@@ -21,7 +25,7 @@ func GRPCTracingUnaryServerInterceptor(deps tracingDeps) grpc.UnaryServerInterce
 
 		// log request if needed
 		if deps.Config.Get(confkeys.GRPCServerTraceIncludeRequest).Bool() {
-			addBodyToSpan(span, "request", req)
+			addBodyToSpan(redactor, span, "request", req)
 		}
 		// call handler
 		resp, err = handler(ctx, req)
@@ -30,7 +34,7 @@ func GRPCTracingUnaryServerInterceptor(deps tracingDeps) grpc.UnaryServerInterce
 		} else {
 			// log response if needed
 			if deps.Config.Get(confkeys.GRPCServerTraceIncludeResponse).Bool() {
-				addBodyToSpan(span, "response", resp)
+				addBodyToSpan(redactor, span, "response", resp)
 			}
 		}
 
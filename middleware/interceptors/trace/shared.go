@@ -13,6 +13,13 @@ import (
 	"google.golang.org/grpc/metadata"
 )
 
+// Logger names of tracing interceptors, see log.Named
+const (
+	GRPCClientLoggerName = "middleware.trace.grpc.client"
+	RESTClientLoggerName = "middleware.trace.rest.client"
+	GRPCServerLoggerName = "middleware.trace.grpc.server"
+)
+
 type tracingDeps struct {
 	fx.In
 
@@ -21,8 +28,8 @@ type tracingDeps struct {
 	Tracer opentracing.Tracer `optional:"true"`
 }
 
-func addBodyToSpan(span opentracing.Span, name string, msg interface{}) {
-	bytes, err := utils.MarshalMessageBody(msg)
+func addBodyToSpan(redactor *utils.Redactor, span opentracing.Span, name string, msg interface{}) {
+	bytes, err := redactor.MarshalMessageBody(msg)
 	if err == nil {
 		span.LogFields(traceLog.String(name, string(bytes))) // TODO: can exceed length limit, introduce option
 	} else {
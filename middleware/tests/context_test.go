@@ -2,11 +2,15 @@ package tests
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/go-masonry/mortar/interfaces/cfg"
 	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
 	mock_cfg "github.com/go-masonry/mortar/interfaces/cfg/mock"
 	contextMiddleware "github.com/go-masonry/mortar/middleware/context"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 	"google.golang.org/grpc/metadata"
 )
@@ -36,3 +40,38 @@ func (s *middlewareSuite) testLoggerGRPCIncomingContextExtractorBeforeTest() fx.
 		fx.Populate(&s.logExtractor),
 	)
 }
+
+func (s *middlewareSuite) TestLoggerTraceContextExtractorOpenTracing() {
+	tracer := mocktracer.New()
+	span := tracer.StartSpan("operation")
+	defer span.Finish()
+	spanContext := span.Context().(mocktracer.MockSpanContext)
+	extracted := s.logExtractor(opentracing.ContextWithSpan(context.Background(), span))
+	s.Equal(map[string]interface{}{
+		contextMiddleware.TraceIDField: fmt.Sprint(spanContext.TraceID),
+		contextMiddleware.SpanIDField:  fmt.Sprint(spanContext.SpanID),
+		contextMiddleware.SampledField: true,
+	}, extracted)
+	s.Empty(s.logExtractor(context.Background()))
+}
+
+func (s *middlewareSuite) TestLoggerTraceContextExtractorOpenTelemetry() {
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	extracted := s.logExtractor(trace.ContextWithSpanContext(context.Background(), spanContext))
+	s.Equal(map[string]interface{}{
+		contextMiddleware.TraceIDField: "4bf92f3577b34da6a3ce929d0e0e4736",
+		contextMiddleware.SpanIDField:  "00f067aa0ba902b7",
+		contextMiddleware.SampledField: true,
+	}, extracted)
+}
+
+func (s *middlewareSuite) testLoggerTraceContextExtractorBeforeTest() fx.Option {
+	return fx.Options(
+		fx.Provide(contextMiddleware.LoggerTraceContextExtractor),
+		fx.Populate(&s.logExtractor),
+	)
+}
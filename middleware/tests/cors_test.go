@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	mock_cfg "github.com/go-masonry/mortar/interfaces/cfg/mock"
+	"github.com/go-masonry/mortar/middleware/grpcgateway"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/fx"
+)
+
+func (s *middlewareSuite) TestCORSInterceptor() {
+	var called int
+	handler := s.corsInterceptor(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called++
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(method, origin string, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/v1/demo", nil)
+		if len(origin) > 0 {
+			req.Header.Set("Origin", origin)
+		}
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Add(headers[i], headers[i+1])
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// exact, wildcard and regex origins
+	for _, origin := range []string{"https://app.example.com", "https://Admin.example.com", "https://docs.example.org"} {
+		response := serve(http.MethodGet, origin)
+		s.Equal(origin, response.Header().Get("Access-Control-Allow-Origin"), origin)
+		s.Equal("X-Request-Id", response.Header().Get("Access-Control-Expose-Headers"))
+		s.Empty(response.Header().Get("Access-Control-Allow-Credentials"))
+	}
+	response := serve(http.MethodGet, "https://example.com.evil.io")
+	s.Empty(response.Header().Get("Access-Control-Allow-Origin"))
+	s.Equal("Origin", response.Header().Get("Vary"))
+	s.Empty(serve(http.MethodGet, "").Header().Get("Vary"), "not a CORS request")
+	s.Equal(5, called)
+
+	// preflight
+	response = serve(http.MethodOptions, "https://app.example.com",
+		"Access-Control-Request-Method", http.MethodPost,
+		"Access-Control-Request-Headers", "content-type, Authorization")
+	s.Equal(http.StatusNoContent, response.Code)
+	s.Equal("https://app.example.com", response.Header().Get("Access-Control-Allow-Origin"))
+	s.Equal("GET, POST", response.Header().Get("Access-Control-Allow-Methods"))
+	s.Equal("Authorization, Content-Type", response.Header().Get("Access-Control-Allow-Headers"))
+	s.Equal("600", response.Header().Get("Access-Control-Max-Age"))
+	s.Equal(5, called, "preflight is not passed to the handler")
+
+	for _, headers := range [][]string{
+		{"Access-Control-Request-Method", http.MethodDelete},
+		{"Access-Control-Request-Method", http.MethodPost, "Access-Control-Request-Headers", "x-secret"},
+	} {
+		response = serve(http.MethodOptions, "https://app.example.com", headers...)
+		s.Equal(http.StatusNoContent, response.Code)
+		s.Empty(response.Header().Get("Access-Control-Allow-Origin"), headers)
+	}
+	// OPTIONS that isn't a preflight is a regular request
+	serve(http.MethodOptions, "https://app.example.com")
+	s.Equal(6, called)
+}
+
+func (s *middlewareSuite) TestCORSInterceptorWithCredentials() {
+	handler := s.corsInterceptor(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodGet, "/v1/demo", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	s.Equal("https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+	s.Equal("true", recorder.Header().Get("Access-Control-Allow-Credentials"))
+
+	// any origin with credentials is rejected
+	anyOriginConfig := mock_cfg.NewMockConfig(s.ctrl)
+	s.expectCORSConfig(anyOriginConfig, map[string]interface{}{
+		confkeys.CORSAllowedOrigins:   []string{"https://app.example.com", "*"},
+		confkeys.CORSAllowCredentials: true,
+	})
+	err := fx.New(
+		fx.NopLogger,
+		fx.Supply(fx.Annotate(anyOriginConfig, fx.As(new(cfg.Config)))),
+		fx.Invoke(grpcgateway.CORSInterceptor),
+	).Err()
+	s.ErrorContains(err, "can't allow any origin")
+}
+
+func (s *middlewareSuite) TestCORSInterceptorBrowserProtocols() {
+	handler := s.corsInterceptor(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodOptions, "/demo.Demo/Ping", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "content-type,x-grpc-web,x-user-agent,grpc-timeout,connect-protocol-version,connect-timeout-ms")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	s.Equal("https://app.example.com", recorder.Header().Get("Access-Control-Allow-Origin"), "default headers and headers of enabled protocols are allowed")
+	s.Equal("Accept, Authorization, Content-Type, X-Requested-With, X-Grpc-Web, X-User-Agent, Grpc-Timeout, Connect-Protocol-Version, Connect-Timeout-Ms",
+		recorder.Header().Get("Access-Control-Allow-Headers"))
+}
+
+func (s *middlewareSuite) testCORSInterceptorBrowserProtocolsBeforeTest() fx.Option {
+	s.expectCORSConfig(s.cfgMock, map[string]interface{}{
+		confkeys.CORSAllowedOrigins:  []string{"https://app.example.com"},
+		confkeys.ExternalRESTGRPCWeb: true,
+		confkeys.ExternalRESTConnect: true,
+	})
+	return fx.Options(
+		fx.Provide(grpcgateway.CORSInterceptor),
+		fx.Populate(&s.corsInterceptor),
+	)
+}
+
+func (s *middlewareSuite) testCORSInterceptorBeforeTest(credentials bool) fx.Option {
+	values := map[string]interface{}{
+		confkeys.CORSAllowedOrigins:        []string{"https://app.example.com", "https://*.example.com"},
+		confkeys.CORSAllowedOriginPatterns: []string{`https://[a-z]+\.example\.(com|org)`},
+		confkeys.CORSAllowedMethods:        []string{"get", "post"},
+		confkeys.CORSAllowedHeaders:        []string{"Authorization", "Content-Type"},
+		confkeys.CORSExposedHeaders:        []string{"X-Request-Id"},
+		confkeys.CORSMaxAge:                10 * time.Minute,
+	}
+	if credentials {
+		values = map[string]interface{}{
+			confkeys.CORSAllowedOrigins:   []string{"https://app.example.com"},
+			confkeys.CORSAllowCredentials: true,
+		}
+	}
+	s.expectCORSConfig(s.cfgMock, values)
+	return fx.Options(
+		fx.Provide(grpcgateway.CORSInterceptor),
+		fx.Populate(&s.corsInterceptor),
+	)
+}
+
+func (s *middlewareSuite) expectCORSConfig(cfgMock *mock_cfg.MockConfig, values map[string]interface{}) {
+	cfgMock.EXPECT().Get(gomock.Any()).DoAndReturn(func(key string) cfg.Value {
+		value := mock_cfg.NewMockValue(s.ctrl)
+		configured, set := values[key]
+		value.EXPECT().IsSet().Return(set).AnyTimes()
+		switch v := configured.(type) {
+		case time.Duration:
+			value.EXPECT().Duration().Return(v).AnyTimes()
+		case bool:
+			value.EXPECT().Bool().Return(v).AnyTimes()
+		default:
+			slice, _ := configured.([]string)
+			value.EXPECT().StringSlice().Return(slice).AnyTimes()
+			value.EXPECT().Bool().Return(false).AnyTimes()
+		}
+		return value
+	}).AnyTimes()
+}
@@ -5,8 +5,10 @@ import (
 	"testing"
 
 	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
 	mock_cfg "github.com/go-masonry/mortar/interfaces/cfg/mock"
 	"github.com/go-masonry/mortar/interfaces/http/client"
+	"github.com/go-masonry/mortar/interfaces/http/server"
 	"github.com/go-masonry/mortar/interfaces/log"
 	mock_monitor "github.com/go-masonry/mortar/interfaces/monitor/mock"
 	"github.com/golang/mock/gomock"
@@ -31,6 +33,7 @@ type middlewareSuite struct {
 	restClientInterceptor client.HTTPClientInterceptor
 	serverInterceptor     grpc.UnaryServerInterceptor
 	tracer                opentracing.Tracer
+	corsInterceptor       server.GRPCGatewayInterceptor
 }
 
 func TestMiddleware(t *testing.T) {
@@ -50,24 +53,36 @@ func (s *middlewareSuite) BeforeTest(suiteName, testName string) {
 	switch testName {
 	case "TestLoggerGRPCIncomingContextExtractor":
 		extraOptions = s.testLoggerGRPCIncomingContextExtractorBeforeTest()
+	case "TestLoggerTraceContextExtractorOpenTracing", "TestLoggerTraceContextExtractorOpenTelemetry":
+		extraOptions = s.testLoggerTraceContextExtractorBeforeTest()
 	case "TestClientInterceptorHeaderCopier", "TestHTTPClientInterceptorHeaderCopier":
 		extraOptions = s.testClientInterceptorHeaderCopierBeforeTest()
 	case "TestLoggerGRPCInterceptor":
-		extraOptions = s.testLoggerGRPCInterceptorBeforeTest(false)
+		extraOptions = s.testLoggerGRPCInterceptorBeforeTest(false, nil, nil)
 	case "TestLoggerGRPCInterceptorWithError":
-		extraOptions = s.testLoggerGRPCInterceptorBeforeTest(true)
+		extraOptions = s.testLoggerGRPCInterceptorBeforeTest(true, nil, nil)
+	case "TestLoggerGRPCInterceptorRedacted":
+		extraOptions = s.testLoggerGRPCInterceptorBeforeTest(false, []string{"password"}, []string{"users.*.email"})
 	case "TestMonitorGRPCInterceptor":
 		extraOptions = s.testMonitorGRPCInterceptorBeforeTest()
 	case "TestTracerGRPCClientInterceptor":
 		extraOptions = s.testTracerGRPCClientInterceptorBeforeTest()
 	case "TestTracerRESTClientInterceptor":
-		extraOptions = s.testTracerRESTClientInterceptorBeforeTest()
+		extraOptions = s.testTracerRESTClientInterceptorBeforeTest(nil)
+	case "TestTracerRESTClientInterceptorRedacted":
+		extraOptions = s.testTracerRESTClientInterceptorBeforeTest([]string{"password", "token"})
 	case "TestGRPCTracingUnaryServerInterceptor":
 		extraOptions = s.testGRPCTracingUnaryServerInterceptorBeforeTest()
 	case "TestDumpRESTClientInterceptor":
 		extraOptions = s.testDumpRESTClientInterceptorBeforeTest()
 	case "TestRESTClientMetrics", "TestGRPCClientMetrics":
 		extraOptions = s.testClientMetricsBeforeTest()
+	case "TestCORSInterceptor":
+		extraOptions = s.testCORSInterceptorBeforeTest(false)
+	case "TestCORSInterceptorWithCredentials":
+		extraOptions = s.testCORSInterceptorBeforeTest(true)
+	case "TestCORSInterceptorBrowserProtocols":
+		extraOptions = s.testCORSInterceptorBrowserProtocolsBeforeTest()
 	default:
 		s.T().Fatalf("no pre test logic found for %s", testName)
 	}
@@ -91,3 +106,16 @@ func (s *middlewareSuite) TearDownTest() {
 	s.app.RequireStop()
 	s.ctrl.Finish()
 }
+
+func (s *middlewareSuite) expectRedactConfig(keys, paths []string) {
+	s.cfgMock.EXPECT().Get(confkeys.RedactKeys).DoAndReturn(func(key string) cfg.Value {
+		value := mock_cfg.NewMockValue(s.ctrl)
+		value.EXPECT().StringSlice().Return(keys)
+		return value
+	})
+	s.cfgMock.EXPECT().Get(confkeys.RedactPaths).DoAndReturn(func(key string) cfg.Value {
+		value := mock_cfg.NewMockValue(s.ctrl)
+		value.EXPECT().StringSlice().Return(paths)
+		return value
+	})
+}
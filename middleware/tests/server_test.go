@@ -40,7 +40,21 @@ func (s *middlewareSuite) TestLoggerGRPCInterceptorWithError() {
 	s.Error(err)
 }
 
-func (s *middlewareSuite) testLoggerGRPCInterceptorBeforeTest(hasError bool) fx.Option {
+func (s *middlewareSuite) TestLoggerGRPCInterceptorRedacted() {
+	unaryHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return map[string]string{"token": "averysecrettoken"}, nil
+	}
+	request := map[string]interface{}{
+		"users": []map[string]string{{"email": "john.doe@example.com", "password": "verysecretpassword"}},
+	}
+	_, err := s.serverInterceptor(context.Background(), request, &grpc.UnaryServerInfo{FullMethod: "fake method"}, unaryHandler)
+	s.NoError(err)
+	s.Contains(s.loggerOutput.String(), `"request":{"users":[{"email":"john****.com","password":"very****word"}]}`)
+	s.Contains(s.loggerOutput.String(), `"response":{"token":"averysecrettoken"}`)
+}
+
+func (s *middlewareSuite) testLoggerGRPCInterceptorBeforeTest(hasError bool, redactKeys, redactPaths []string) fx.Option {
+	s.expectRedactConfig(redactKeys, redactPaths)
 	s.cfgMock.EXPECT().Get(confkeys.MiddlewareLogLevel).DoAndReturn(func(key string) cfg.Value {
 		value := mock_cfg.NewMockValue(s.ctrl)
 		value.EXPECT().IsSet().Return(true)
@@ -72,7 +86,7 @@ func (s *middlewareSuite) testLoggerGRPCInterceptorBeforeTest(hasError bool) fx.
 	return fx.Options(
 		fx.Provide(server.LoggerGRPCInterceptor),
 		fx.Provide(func() log.Logger {
-			return naive.Builder().SetWriter(&s.loggerOutput).SetLevel(log.DebugLevel).Build()
+			return naive.Builder().JSON().SetWriter(&s.loggerOutput).SetLevel(log.DebugLevel).Build()
 		}),
 		fx.Populate(&s.serverInterceptor),
 	)
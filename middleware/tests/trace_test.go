@@ -39,6 +39,7 @@ func (s *middlewareSuite) TestTracerGRPCClientInterceptor() {
 }
 
 func (s *middlewareSuite) testTracerGRPCClientInterceptorBeforeTest() fx.Option {
+	s.expectRedactConfig(nil, nil)
 	s.cfgMock.EXPECT().Get(confkeys.GRPCClientTraceIncludeRequest).DoAndReturn(func(key string) cfg.Value {
 		value := mock_cfg.NewMockValue(s.ctrl)
 		value.EXPECT().Bool().Return(true)
@@ -84,7 +85,47 @@ func (s *middlewareSuite) TestTracerRESTClientInterceptor() {
 	s.Empty(s.loggerOutput.String())
 }
 
-func (s *middlewareSuite) testTracerRESTClientInterceptorBeforeTest() fx.Option {
+func (s *middlewareSuite) TestTracerRESTClientInterceptorRedacted() {
+	handler := func(req *http.Request) (*http.Response, error) {
+		body, err := ioutil.ReadAll(req.Body)
+		s.Require().NoError(err)
+		s.JSONEq(`{"name":"john","password":"verysecretpassword"}`, string(body), "request body must be sent as is")
+		return &http.Response{
+			Status:     "200 OK",
+			StatusCode: 200,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{"Set-Cookie": []string{"session=verysecretsession"}},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"token":"verysecrettoken"}`)),
+		}, nil
+	}
+	req, _ := http.NewRequest(http.MethodPost, "http://somewhere/path", strings.NewReader(`{"name":"john","password":"verysecretpassword"}`))
+	req.Header.Set("Authorization", "Bearer verysecretbearer")
+	resp, err := s.restClientInterceptor(req, handler)
+	s.Require().NoError(err)
+	body, err := ioutil.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.Equal(`{"token":"verysecrettoken"}`, string(body), "response body must be returned as is")
+
+	spans := s.tracer.(*mocktracer.MockTracer).FinishedSpans()
+	s.Require().Len(spans, 1)
+	var dumps []string
+	for _, record := range spans[0].Logs() {
+		for _, field := range record.Fields {
+			dumps = append(dumps, field.ValueString)
+		}
+	}
+	s.Require().Len(dumps, 2, "request or response is missing")
+	for _, secret := range []string{"verysecretpassword", "verysecretbearer", "verysecretsession", "verysecrettoken"} {
+		s.NotContains(dumps[0]+dumps[1], secret)
+	}
+	s.Contains(dumps[0], `"name":"john"`)
+	s.Contains(dumps[0], "Authorization: Bear")
+}
+
+func (s *middlewareSuite) testTracerRESTClientInterceptorBeforeTest(redactKeys []string) fx.Option {
+	s.expectRedactConfig(redactKeys, nil)
 	s.cfgMock.EXPECT().Get(confkeys.HTTPClientTraceIncludeResponse).DoAndReturn(func(key string) cfg.Value {
 		value := mock_cfg.NewMockValue(s.ctrl)
 		value.EXPECT().Bool().Return(true)
@@ -123,6 +164,7 @@ func (s *middlewareSuite) TestGRPCTracingUnaryServerInterceptor() {
 }
 
 func (s *middlewareSuite) testGRPCTracingUnaryServerInterceptorBeforeTest() fx.Option {
+	s.expectRedactConfig(nil, nil)
 	s.cfgMock.EXPECT().Get(confkeys.GRPCServerTraceIncludeRequest).DoAndReturn(func(key string) cfg.Value {
 		value := mock_cfg.NewMockValue(s.ctrl)
 		value.EXPECT().Bool().Return(true)
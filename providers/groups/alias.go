@@ -3,6 +3,7 @@ package groups
 import (
 	"github.com/go-masonry/mortar/constructors"
 	"github.com/go-masonry/mortar/constructors/partial"
+	"github.com/go-masonry/mortar/handlers"
 )
 
 const (
@@ -37,6 +38,15 @@ const (
 	// StreamServerInterceptors - GRPC Stream Server Interceptors group. Register different gRPC server interceptors
 	StreamServerInterceptors = partial.FxGroupStreamServerInterceptors
 
+	// InternalGRPCServerAPIs - Internal GRPC Service APIs group. Registered only on the internal gRPC server, when `mortar.server.grpc.internal.port` is set
+	InternalGRPCServerAPIs = partial.FxGroupInternalGRPCServerAPIs
+
+	// InternalUnaryServerInterceptors - Internal GRPC Unary Server Interceptors group. Interceptors of the internal gRPC server only
+	InternalUnaryServerInterceptors = partial.FxGroupInternalUnaryServerInterceptors
+
+	// InternalStreamServerInterceptors - Internal GRPC Stream Server Interceptors group. Interceptors of the internal gRPC server only
+	InternalStreamServerInterceptors = partial.FxGroupInternalStreamServerInterceptors
+
 	// InternalHTTPHandlers - Internal Http Handlers group. Mortar comes with several internal handlers, you can add yours.
 	InternalHTTPHandlers = partial.FxGroupInternalHTTPHandlers
 
@@ -51,4 +61,10 @@ const (
 
 	// MonitorContextExtractors - Monitor Context extractors group. Add different tags from context to each metric
 	MonitorContextExtractors = constructors.FxGroupMonitorContextExtractors
+
+	// HealthChecks - Health Checks group. Provide health.Check of your components, they are registered in the default health.Registry
+	HealthChecks = constructors.FxGroupHealthChecks
+
+	// OpenAPIDocuments - OpenAPI Documents group. Provide handlers.OpenAPIDocument of your gRPC Gateway handlers, they are served by providers.OpenAPIHandlersFxOption
+	OpenAPIDocuments = handlers.FxGroupOpenAPIDocuments
 )
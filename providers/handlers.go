@@ -1,6 +1,8 @@
 package providers
 
 import (
+	"io/fs"
+
 	"github.com/go-masonry/mortar/handlers"
 	"github.com/go-masonry/mortar/providers/groups"
 	"go.uber.org/fx"
@@ -63,3 +65,99 @@ func InternalSelfHandlersFxOption() fx.Option {
 //
 // Consider using InternalSelfHandlersFxOption if you only want to provide it.
 var SelfHandlers = handlers.SelfHandlers
+
+// InternalLogLevelHandlersFxOption adds Internal Log Level HTTP Handlers to the graph
+//
+// Adds these endpoint on Internal web service
+//   - GET/PUT /self/loglevel
+func InternalLogLevelHandlersFxOption() fx.Option {
+	return fx.Provide(
+		fx.Annotated{
+			Group:  groups.InternalHTTPHandlers + ",flatten",
+			Target: handlers.LogLevelHandlers,
+		})
+}
+
+// LogLevelHandlers is a constructor that creates Internal Log Level HTTP Handlers
+//
+//	*Note* normally this dependency is part of a group. If you want to create it as a standalone
+//	dependency, remember that there can be only one of this kind in the graph.
+//
+// Adds these endpoint on Internal web service
+//   - GET/PUT /self/loglevel
+//
+// Consider using InternalLogLevelHandlersFxOption if you only want to provide it.
+var LogLevelHandlers = handlers.LogLevelHandlers
+
+// InternalRecentLogsHandlersFxOption adds Internal Recent Logs HTTP Handlers to the graph, see keys.LogRecentSize
+//
+// Adds these endpoint on Internal web service
+//   - GET /self/logs
+func InternalRecentLogsHandlersFxOption() fx.Option {
+	return fx.Provide(
+		fx.Annotated{
+			Group:  groups.InternalHTTPHandlers + ",flatten",
+			Target: handlers.RecentLogsHandlers,
+		})
+}
+
+// RecentLogsHandlers is a constructor that creates Internal Recent Logs HTTP Handlers
+//
+//	*Note* normally this dependency is part of a group. If you want to create it as a standalone
+//	dependency, remember that there can be only one of this kind in the graph.
+//
+// Adds these endpoint on Internal web service
+//   - GET /self/logs
+//
+// Consider using InternalRecentLogsHandlersFxOption if you only want to provide it.
+var RecentLogsHandlers = handlers.RecentLogsHandlers
+
+// OpenAPIHandlersFxOption adds OpenAPI document and docs UI HTTP Handlers to the graph.
+// Documents provided to the groups.OpenAPIDocuments group are merged into one document per port they describe.
+//
+// Adds these endpoint on the External and/or Internal web service, see keys.OpenAPISpecPath and keys.OpenAPIUIPath
+//   - GET /openapi.json
+//   - GET /docs/
+func OpenAPIHandlersFxOption() fx.Option {
+	return fx.Provide(
+		fx.Annotated{
+			Group:  groups.ExternalHTTPHandlers + ",flatten",
+			Target: handlers.ExternalOpenAPIHandlers,
+		},
+		fx.Annotated{
+			Group:  groups.InternalHTTPHandlers + ",flatten",
+			Target: handlers.InternalOpenAPIHandlers,
+		})
+}
+
+// ExternalOpenAPIHandlers is a constructor that creates External OpenAPI HTTP Handlers
+//
+//	*Note* normally this dependency is part of a group. If you want to create it as a standalone
+//	dependency, remember that there can be only one of this kind in the graph.
+//
+// Consider using OpenAPIHandlersFxOption if you only want to provide it.
+var ExternalOpenAPIHandlers = handlers.ExternalOpenAPIHandlers
+
+// InternalOpenAPIHandlers is a constructor that creates Internal OpenAPI HTTP Handlers
+//
+//	*Note* normally this dependency is part of a group. If you want to create it as a standalone
+//	dependency, remember that there can be only one of this kind in the graph.
+//
+// Consider using OpenAPIHandlersFxOption if you only want to provide it.
+var InternalOpenAPIHandlers = handlers.InternalOpenAPIHandlers
+
+// OpenAPIDocumentsFxOption adds OpenAPI documents found in fsys to the groups.OpenAPIDocuments group, see handlers.OpenAPIDocumentsFromFS
+//
+//	//go:embed api/*.swagger.json
+//	var apiDocuments embed.FS
+//
+//	providers.OpenAPIDocumentsFxOption(apiDocuments, "api/*.swagger.json", false)
+func OpenAPIDocumentsFxOption(fsys fs.FS, pattern string, internal bool) fx.Option {
+	return fx.Provide(
+		fx.Annotated{
+			Group: groups.OpenAPIDocuments + ",flatten",
+			Target: func() ([]handlers.OpenAPIDocument, error) {
+				return handlers.OpenAPIDocumentsFromFS(fsys, pattern, internal)
+			},
+		})
+}
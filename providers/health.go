@@ -0,0 +1,47 @@
+package providers
+
+import (
+	"github.com/go-masonry/mortar/constructors"
+	"github.com/go-masonry/mortar/handlers"
+	"github.com/go-masonry/mortar/providers/groups"
+	"go.uber.org/fx"
+)
+
+// HealthRegistryFxOption adds default health.Registry to the graph, checks are provided to groups.HealthChecks.
+//
+// Internal gRPC health service will fail when a critical readiness check fails.
+func HealthRegistryFxOption() fx.Option {
+	return fx.Provide(constructors.HealthRegistry)
+}
+
+// HealthRegistry is a constructor that creates a health.Registry with all the checks of groups.HealthChecks
+//
+// Consider using HealthRegistryFxOption if you only want to provide it.
+var HealthRegistry = constructors.HealthRegistry
+
+// InternalHealthHandlersFxOption adds Internal Health Probe HTTP Handlers to the graph, it depends on health.Registry
+//
+// Adds these endpoint on Internal web service
+//   - /health/live
+//   - /health/ready
+//   - /health/startup
+func InternalHealthHandlersFxOption() fx.Option {
+	return fx.Provide(
+		fx.Annotated{
+			Group:  groups.InternalHTTPHandlers + ",flatten",
+			Target: handlers.HealthHandlers,
+		})
+}
+
+// HealthHandlers is a constructor that creates Internal Health Probe HTTP Handlers
+//
+//	*Note* normally this dependency is part of a group. If you want to create it as a standalone
+//	dependency, remember that there can be only one of this kind in the graph.
+//
+// Adds these endpoint on Internal web service
+//   - /health/live
+//   - /health/ready
+//   - /health/startup
+//
+// Consider using InternalHealthHandlersFxOption if you only want to provide it.
+var HealthHandlers = handlers.HealthHandlers
@@ -3,6 +3,7 @@ package providers
 import (
 	"github.com/go-masonry/mortar/constructors"
 	"github.com/go-masonry/mortar/constructors/partial"
+	serverInt "github.com/go-masonry/mortar/interfaces/http/server"
 	"github.com/go-masonry/mortar/middleware/grpcgateway"
 	"github.com/go-masonry/mortar/middleware/interceptors/client"
 	"github.com/go-masonry/mortar/providers/groups"
@@ -31,6 +32,18 @@ func HTTPServerBuilderFxOption() fx.Option {
 // Consider using HTTPServerBuilderFxOption if you only want to provide it.
 var HTTPServerBuilder = partial.HTTPServerBuilder
 
+// ListenersFxOption adds inherit.Listeners to the graph, the Http Server builder will use them to
+//   - start from listeners inherited via LISTEN_FDS (systemd socket activation or a previous instance)
+//   - hand its listeners to a new instance of the service on SIGUSR2, if `mortar.server.listeners.handoff` is enabled
+func ListenersFxOption() fx.Option {
+	return fx.Provide(constructors.Listeners)
+}
+
+// Listeners is a constructor that creates inherit.Listeners
+//
+// Consider using ListenersFxOption if you only want to provide it.
+var Listeners = constructors.Listeners
+
 // HTTPClientBuildersFxOption adds both (GRPC, REST) partial http clients to the graph
 func HTTPClientBuildersFxOption() fx.Option {
 	return fx.Provide(
@@ -156,3 +169,28 @@ func MapHTTPHeadersToClientMetadataMuxOptionFxOption() fx.Option {
 //
 // Consider using MapHTTPHeadersToClientMetadataMuxOptionFxOption if you only want to provide it.
 var MapHTTPHeadersToClientMetadataMuxOption = grpcgateway.MapHTTPHeadersToClientMetadataMuxOption
+
+// CORSInterceptorFxOption adds a CORS interceptor to the External HTTP Interceptors group, the same interceptor
+// also covers external HTTP handlers and gRPC-Web/Connect calls. CORS is configured under `mortar.middleware.cors`
+func CORSInterceptorFxOption() fx.Option {
+	return fx.Provide(
+		fx.Annotated{
+			Name:   partial.FxNameExternalCORSInterceptor,
+			Target: grpcgateway.CORSInterceptor,
+		},
+		fx.Annotate(
+			func(interceptor serverInt.GRPCGatewayInterceptor) serverInt.GRPCGatewayInterceptor {
+				return interceptor
+			},
+			fx.ParamTags(`name:"`+partial.FxNameExternalCORSInterceptor+`"`),
+			fx.ResultTags(`group:"`+groups.ExternalHTTPInterceptors+`"`),
+		))
+}
+
+// CORSInterceptor is a constructor that creates a CORS interceptor of the external REST port
+//
+//	*Note* normally this dependency is part of a group. If you want to create it as a standalone
+//	dependency, remember that there can be only one of this kind in the graph.
+//
+// Consider using CORSInterceptorFxOption if you only want to provide it.
+var CORSInterceptor = grpcgateway.CORSInterceptor
@@ -2,6 +2,7 @@ package providers
 
 import (
 	"github.com/go-masonry/mortar/constructors"
+	"github.com/go-masonry/mortar/constructors/partial"
 	"github.com/go-masonry/mortar/logger"
 	"github.com/go-masonry/mortar/middleware/context"
 	"github.com/go-masonry/mortar/middleware/interceptors/server"
@@ -19,6 +20,33 @@ func LoggerFxOption() fx.Option {
 // Consider using LoggerFxOption if you only want to invoke it.
 var DefaultLogger = constructors.DefaultLogger
 
+// LoggerSamplingMetricsFxOption counts log entries dropped by sampling when monitor.Metrics are available in the graph
+func LoggerSamplingMetricsFxOption() fx.Option {
+	return fx.Invoke(constructors.LoggerSamplingMetrics)
+}
+
+// LoggerSamplingMetrics is a function that binds log sampling drops to a metric counter
+//
+// Consider using LoggerSamplingMetricsFxOption if you only want to invoke it.
+var LoggerSamplingMetrics = constructors.LoggerSamplingMetrics
+
+// RedirectStandardLogsFxOption redirects the standard library `log` package and gRPC `grpclog` into the Logger,
+// every entry gets a `source` field. REST servers built by the default HTTP server builder write their errors to the Logger as well.
+func RedirectStandardLogsFxOption() fx.Option {
+	return fx.Options(
+		fx.Invoke(constructors.RedirectStandardLogs),
+		fx.Provide(fx.Annotated{
+			Name:   partial.FxNameRESTServerErrorLog,
+			Target: constructors.RESTServerErrorLog,
+		}),
+	)
+}
+
+// RedirectStandardLogs is a function that redirects the standard library and gRPC loggers into the Logger
+//
+// Consider using RedirectStandardLogsFxOption if you only want to invoke it.
+var RedirectStandardLogs = constructors.RedirectStandardLogs
+
 // FxEventLoggerOption add new Fx Event option to output fx events using structured logger
 func FxEventLoggerOption() fx.Option {
 	return fx.WithLogger(logger.CreateFxEventLogger)
@@ -48,6 +76,26 @@ func LoggerGRPCIncomingContextExtractorFxOption() fx.Option {
 // Consider using LoggerGRPCIncomingContextExtractorFxOption if you only want to provide it.
 var LoggerGRPCIncomingContextExtractor = context.LoggerGRPCIncomingContextExtractor
 
+// LoggerTraceContextExtractorFxOption adds Logger Context Extractor that adds trace_id, span_id and sampled fields
+// of the active OpenTelemetry or opentracing span
+//
+// This one will be included during Logger build
+func LoggerTraceContextExtractorFxOption() fx.Option {
+	return fx.Provide(fx.Annotated{
+		Group:  groups.LoggerContextExtractors,
+		Target: context.LoggerTraceContextExtractor,
+	})
+}
+
+// LoggerTraceContextExtractor is a constructor that creates log.ContextExtractor.
+// This Extractor will add trace_id, span_id and sampled fields of the active span when writing log
+//
+//	*Note* normally this dependency is part of a group. If you want to create it as a standalone
+//	dependency, remember that there can be only one of this kind in the graph.
+//
+// Consider using LoggerTraceContextExtractorFxOption if you only want to provide it.
+var LoggerTraceContextExtractor = context.LoggerTraceContextExtractor
+
 // LoggerGRPCInterceptorFxOption adds Unary Server Interceptor that will log Request and Response if needed
 func LoggerGRPCInterceptorFxOption() fx.Option {
 	return fx.Provide(fx.Annotated{
@@ -0,0 +1,285 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-masonry/mortar/interfaces/cfg"
+	confkeys "github.com/go-masonry/mortar/interfaces/cfg/keys"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	redactEdgesLength = 4
+	// RedactPathWildcard matches any single key or array index within a JSON path
+	RedactPathWildcard = "*"
+)
+
+// credentialHeaders are always redacted, regardless of the configured key patterns
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// Redactor obfuscates sensitive values of log fields and message bodies, values are obfuscated using Obfuscate.
+//
+// Values are chosen by
+//   - proto fields annotated with the standard `[debug_redact = true]` field option
+//   - JSON paths such as `user.password` or `users.*.card.number`, `*` matches any key or array index
+//   - key patterns, a key that contains one of the patterns (case insensitive) is obfuscated, same as ObfuscateIfNeeded
+type Redactor struct {
+	keyPatterns []string
+	paths       [][]string
+	protoPaths  sync.Map // protoreflect.FullName -> [][]string
+}
+
+// NewRedactor creates a Redactor, proto annotations are always honored
+func NewRedactor(keyPatterns []string, paths []string) *Redactor {
+	redactor := &Redactor{}
+	for _, pattern := range keyPatterns {
+		redactor.keyPatterns = append(redactor.keyPatterns, strings.ToLower(pattern))
+	}
+	for _, path := range paths {
+		redactor.paths = append(redactor.paths, strings.Split(strings.ToLower(path), "."))
+	}
+	return redactor
+}
+
+// RedactorFromConfig creates a Redactor using `mortar.middleware.redact.keys` and `mortar.middleware.redact.paths`
+func RedactorFromConfig(config cfg.Config) *Redactor {
+	return NewRedactor(config.Get(confkeys.RedactKeys).StringSlice(), config.Get(confkeys.RedactPaths).StringSlice())
+}
+
+// MarshalMessageBody marshals body the same way as MarshalMessageBody does and redacts the output.
+//
+// []byte bodies that are not valid JSON are returned as is
+func (r *Redactor) MarshalMessageBody(body interface{}) ([]byte, error) {
+	output, err := MarshalMessageBody(body)
+	if err != nil {
+		return output, err
+	}
+	paths := r.paths
+	if msg, ok := body.(proto.Message); ok && msg != nil {
+		paths = append(r.annotatedPaths(msg.ProtoReflect().Descriptor()), paths...)
+	}
+	if len(paths) == 0 && len(r.keyPatterns) == 0 {
+		return output, nil
+	}
+	var tree interface{}
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	decoder.UseNumber()
+	if decoder.Decode(&tree) != nil {
+		return output, nil
+	}
+	return json.Marshal(r.redact(tree, nil, paths))
+}
+
+// Header returns a copy of header where credential headers (Authorization, Cookie, ...) and headers that
+// match one of the key patterns are obfuscated
+func (r *Redactor) Header(header http.Header) http.Header {
+	output := header.Clone()
+	for name, values := range output {
+		if !r.matchKey(name) && !isCredentialHeader(name) {
+			continue
+		}
+		for i, value := range values {
+			values[i] = Obfuscate(value, redactEdgesLength)
+		}
+	}
+	return output
+}
+
+// DumpRequestOut is a redacting version of httputil.DumpRequestOut, headers are redacted using Header and the
+// body using MarshalMessageBody. Request body is read and replaced, so req can still be sent.
+func (r *Redactor) DumpRequestOut(req *http.Request) ([]byte, error) {
+	body, err := drainBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Header = r.Header(req.Header)
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	dump, err := httputil.DumpRequestOut(clone, false)
+	if err != nil {
+		return nil, err
+	}
+	return r.appendBody(dump, body)
+}
+
+// DumpResponse is a redacting version of httputil.DumpResponse, see DumpRequestOut
+func (r *Redactor) DumpResponse(resp *http.Response) ([]byte, error) {
+	body, err := drainBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	clone := *resp
+	clone.Header = r.Header(resp.Header)
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	dump, err := httputil.DumpResponse(&clone, false)
+	if err != nil {
+		return nil, err
+	}
+	return r.appendBody(dump, body)
+}
+
+func (r *Redactor) appendBody(dump, body []byte) ([]byte, error) {
+	if len(body) == 0 {
+		return dump, nil
+	}
+	redacted, err := r.MarshalMessageBody(body)
+	if err != nil {
+		return nil, err
+	}
+	return append(dump, redacted...), nil
+}
+
+// drainBody reads the entire body and replaces it with an in-memory copy
+func drainBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	content, err := io.ReadAll(*body)
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(content))
+	return content, err
+}
+
+func isCredentialHeader(name string) bool {
+	for _, header := range credentialHeaders {
+		if strings.EqualFold(header, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Value redacts a single field value, it's obfuscated if name matches one of the key patterns.
+// Proto messages are marshaled and redacted, other values are returned as is.
+func (r *Redactor) Value(name string, value interface{}) interface{} {
+	if r.matchKey(name) {
+		return r.obfuscate(value)
+	}
+	if msg, ok := value.(proto.Message); ok {
+		if output, err := r.MarshalMessageBody(msg); err == nil {
+			return output
+		}
+	}
+	return value
+}
+
+func (r *Redactor) redact(value interface{}, path []string, paths [][]string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			itemPath := append(append(make([]string, 0, len(path)+1), path...), strings.ToLower(key))
+			if r.matchKey(key) || matchPath(paths, itemPath) {
+				v[key] = r.obfuscate(item)
+			} else {
+				v[key] = r.redact(item, itemPath, paths)
+			}
+		}
+	case []interface{}:
+		for index, item := range v {
+			itemPath := append(append(make([]string, 0, len(path)+1), path...), strconv.Itoa(index))
+			if matchPath(paths, itemPath) {
+				v[index] = r.obfuscate(item)
+			} else {
+				v[index] = r.redact(item, itemPath, paths)
+			}
+		}
+	}
+	return value
+}
+
+func (r *Redactor) matchKey(key string) bool {
+	lowered := strings.ToLower(key)
+	for _, pattern := range r.keyPatterns {
+		if strings.Contains(lowered, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) obfuscate(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return Obfuscate(v, redactEdgesLength)
+	case json.Number, fmt.Stringer:
+		return Obfuscate(fmt.Sprintf("%s", v), redactEdgesLength)
+	case map[string]interface{}, []interface{}:
+		output, _ := json.Marshal(v)
+		return Obfuscate(string(output), redactEdgesLength)
+	default:
+		return Obfuscate(fmt.Sprintf("%v", v), redactEdgesLength)
+	}
+}
+
+// annotatedPaths returns JSON paths of fields annotated with debug_redact, they are cached per message type
+func (r *Redactor) annotatedPaths(descriptor protoreflect.MessageDescriptor) [][]string {
+	if cached, ok := r.protoPaths.Load(descriptor.FullName()); ok {
+		return cached.([][]string)
+	}
+	paths := collectAnnotatedPaths(descriptor, nil, make(map[protoreflect.FullName]bool))
+	r.protoPaths.Store(descriptor.FullName(), paths)
+	return paths
+}
+
+func collectAnnotatedPaths(descriptor protoreflect.MessageDescriptor, prefix []string, visiting map[protoreflect.FullName]bool) (paths [][]string) {
+	if visiting[descriptor.FullName()] { // recursive message
+		return nil
+	}
+	visiting[descriptor.FullName()] = true
+	defer delete(visiting, descriptor.FullName())
+	fields := descriptor.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		path := append(append(make([]string, 0, len(prefix)+2), prefix...), strings.ToLower(field.JSONName()))
+		if options, ok := field.Options().(*descriptorpb.FieldOptions); ok && options.GetDebugRedact() {
+			paths = append(paths, path)
+			continue
+		}
+		var message protoreflect.MessageDescriptor
+		switch {
+		case field.IsMap():
+			message = field.MapValue().Message()
+			path = append(path, RedactPathWildcard)
+		case field.IsList():
+			message = field.Message()
+			path = append(path, RedactPathWildcard)
+		default:
+			message = field.Message()
+		}
+		if message != nil {
+			paths = append(paths, collectAnnotatedPaths(message, path, visiting)...)
+		}
+	}
+	return paths
+}
+
+func matchPath(paths [][]string, path []string) bool {
+	for _, candidate := range paths {
+		if len(candidate) != len(path) {
+			continue
+		}
+		matched := true
+		for i := range candidate {
+			if candidate[i] != RedactPathWildcard && candidate[i] != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
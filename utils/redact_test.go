@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// userDescriptor builds the following message without protoc
+//
+//	message Card { string number = 1 [debug_redact = true]; string holder = 2; }
+//	message User { string name = 1; string password = 2 [debug_redact = true]; repeated Card cards = 3; User manager = 4; }
+func userDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	redacted := &descriptorpb.FieldOptions{DebugRedact: proto.Bool(true)}
+	field := func(name string, number int32, options *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Options:  options,
+		}
+	}
+	message := func(name string, number int32, typeName string, label descriptorpb.FieldDescriptorProto_Label) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    label.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: proto.String(typeName),
+		}
+	}
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("redact_test.proto"),
+		Package: proto.String("redact"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:  proto.String("Card"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("number", 1, redacted), field("holder", 2, nil)},
+			},
+			{
+				Name: proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("name", 1, nil),
+					field("password", 2, redacted),
+					message("cards", 3, ".redact.Card", descriptorpb.FieldDescriptorProto_LABEL_REPEATED),
+					message("manager", 4, ".redact.User", descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL),
+				},
+			},
+		},
+	}, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+	return file.Messages().ByName("User")
+}
+
+func TestRedactProtoAnnotations(t *testing.T) {
+	descriptor := userDescriptor(t)
+	user := dynamicpb.NewMessage(descriptor)
+	user.Set(descriptor.Fields().ByName("name"), protoreflect.ValueOfString("john"))
+	user.Set(descriptor.Fields().ByName("password"), protoreflect.ValueOfString("verysecretpassword"))
+	cards := user.Mutable(descriptor.Fields().ByName("cards")).List()
+	card := cards.NewElement()
+	card.Message().Set(descriptor.Fields().ByName("cards").Message().Fields().ByName("number"), protoreflect.ValueOfString("4580123456789012"))
+	card.Message().Set(descriptor.Fields().ByName("cards").Message().Fields().ByName("holder"), protoreflect.ValueOfString("john"))
+	cards.Append(card)
+
+	output, err := NewRedactor(nil, nil).MarshalMessageBody(user)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"john","password":"very****word","cards":[{"number":"4580****9012","holder":"john"}]}`, string(output))
+}
+
+func TestRedactPathsAndKeys(t *testing.T) {
+	body := map[string]interface{}{
+		"user": map[string]interface{}{
+			"email":  "john.doe@example.com",
+			"age":    42,
+			"Tokens": []string{"first", "second"},
+		},
+		"items": []interface{}{
+			map[string]interface{}{"card": "4580123456789012"},
+			map[string]interface{}{"card": nil},
+		},
+	}
+	redactor := NewRedactor([]string{"token"}, []string{"User.email", "user.age", "items.*.card"})
+	output, err := redactor.MarshalMessageBody(body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"user": {"email":"john****.com", "age":"****", "Tokens":"[\"fi****nd\"]"},
+		"items": [{"card":"4580****9012"}, {"card":null}]
+	}`, string(output))
+}
+
+func TestRedactNotJSON(t *testing.T) {
+	dump := []byte("GET /path HTTP/1.1\r\nAuthorization: secret")
+	output, err := NewRedactor([]string{"authorization"}, nil).MarshalMessageBody(dump)
+	require.NoError(t, err)
+	assert.Equal(t, dump, output)
+}
+
+func TestRedactValue(t *testing.T) {
+	redactor := NewRedactor([]string{"pass"}, nil)
+	assert.Equal(t, "very****word", redactor.Value("Password", "verysecretpassword"))
+	assert.Equal(t, 42, redactor.Value("answer", 42))
+}
@@ -25,3 +25,38 @@ func Obfuscate(input string, edgesLength int) string {
 	}
 	return strings.Repeat("*", edgesLength)
 }
+
+// ObfuscateIfNeeded converts value to string and obfuscates it if key contains one of hideKeys (case insensitive)
+func ObfuscateIfNeeded(key string, value interface{}, hideKeys []string, edgesLength int) string {
+	var valueAsString string
+	if value == nil {
+		return ""
+	}
+	switch v := value.(type) {
+	case string, fmt.Stringer:
+		valueAsString = fmt.Sprintf("%s", v)
+	default:
+		valueAsString = fmt.Sprintf("%v", v)
+	}
+	for _, hidePart := range hideKeys {
+		if strings.Contains(strings.ToLower(key), strings.ToLower(hidePart)) {
+			return Obfuscate(valueAsString, edgesLength)
+		}
+	}
+	return valueAsString
+}
+
+// ObfuscateMapWhereNeeded returns a copy of a configuration map where every value is converted to string using ObfuscateIfNeeded.
+// Keys are joined with '.' and prefix
+func ObfuscateMapWhereNeeded(prefix string, confMap map[string]interface{}, hideKeys []string, edgesLength int) map[string]interface{} {
+	output := make(map[string]interface{})
+	for k, v := range confMap {
+		obfuscateKey := fmt.Sprintf("%s.%s", prefix, k)
+		if mValue, ok := v.(map[string]interface{}); ok {
+			output[k] = ObfuscateMapWhereNeeded(obfuscateKey, mValue, hideKeys, edgesLength)
+		} else {
+			output[k] = ObfuscateIfNeeded(obfuscateKey, v, hideKeys, edgesLength)
+		}
+	}
+	return output
+}
@@ -13,6 +13,23 @@ func TestObfuscate(t *testing.T) {
 	assert.Equal(t, "123***890", Obfuscate(largeInput, 3))
 }
 
+func TestObfuscateMapWhereNeeded(t *testing.T) {
+	confMap := map[string]interface{}{
+		"db": map[string]interface{}{
+			"password": "verysecretpassword",
+			"port":     5432,
+		},
+		"name": nil,
+	}
+	assert.Equal(t, map[string]interface{}{
+		"db": map[string]interface{}{
+			"password": "very****word",
+			"port":     "5432",
+		},
+		"name": "",
+	}, ObfuscateMapWhereNeeded("", confMap, []string{"PASS"}, 4))
+}
+
 func TestSplitMethodAndPackage(t *testing.T) {
 	realGRPCPath := "/package.Service/Method"
 	packageAndService, methodName := SplitMethodAndPackage(realGRPCPath)